@@ -2,21 +2,55 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/antimatter007/go-backend/db/sqlc"
 	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/webhook"
 )
 
+// accountResponse adds the available balance, the balance less the active holds,
+// and both balances as decimal strings in major units of the account's currency.
+type accountResponse struct {
+	db.Account
+	AvailableBalance        int64  `json:"available_balance"`
+	BalanceDecimal          string `json:"balance_decimal"`
+	AvailableBalanceDecimal string `json:"available_balance_decimal"`
+}
+
+func newAccountResponse(account db.Account, heldAmount int64) accountResponse {
+	availableBalance := account.Balance - heldAmount
+	return accountResponse{
+		Account:                 account,
+		AvailableBalance:        availableBalance,
+		BalanceDecimal:          util.FormatAmount(account.Balance, account.Currency),
+		AvailableBalanceDecimal: util.FormatAmount(availableBalance, account.Currency),
+	}
+}
+
+// accountResponse loads the active holds on the account to build its response
+func (server *Server) accountResponse(ctx *gin.Context, account db.Account) (accountResponse, error) {
+	heldAmount, err := server.store.GetHeldAmount(ctx, account.ID)
+	if err != nil {
+		return accountResponse{}, err
+	}
+	return newAccountResponse(account, heldAmount), nil
+}
+
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
+	// Product is the name of the account product that sets the interest rate and fees, none when empty
+	Product string `json:"product"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
 	var req createAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -27,18 +61,37 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		Balance:  0,
 	}
 
+	if req.Product != "" {
+		product, err := server.store.GetAccountProductByName(ctx, req.Product)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				err = fmt.Errorf("unknown account product %q", req.Product)
+				abortWithError(ctx, http.StatusBadRequest, err)
+				return
+			}
+			abortWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		arg.ProductID = pgtype.Int8{Int64: product.ID, Valid: true}
+	}
+
 	account, err := server.store.CreateAccount(ctx, arg)
 	if err != nil {
-		errCode := db.ErrorCode(err)
-		if errCode == db.ForeignKeyViolation || errCode == db.UniqueViolation {
-			ctx.JSON(http.StatusForbidden, errorResponse(err))
+		switch db.ErrorCode(err) {
+		case db.ForeignKeyViolation:
+			abortWithError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		case db.UniqueViolation:
+			abortWithError(ctx, http.StatusConflict, err)
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	server.publishWebhookEvent(ctx, webhook.EventAccountCreated, []string{account.Owner}, account)
+	// a new account has no holds yet
+	ctx.JSON(http.StatusOK, newAccountResponse(account, 0))
 }
 
 type getAccountRequest struct {
@@ -48,29 +101,35 @@ type getAccountRequest struct {
 func (server *Server) getAccount(ctx *gin.Context) {
 	var req getAccountRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	account, err := server.store.GetAccount(ctx, req.ID)
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			abortWithError(ctx, http.StatusNotFound, err)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if account.Owner != authPayload.Username {
 		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	rsp, err := server.accountResponse(ctx, account)
+	if err != nil {
+		abortWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, rsp)
 }
 
 type listAccountRequest struct {
@@ -81,7 +140,7 @@ type listAccountRequest struct {
 func (server *Server) listAccounts(ctx *gin.Context) {
 	var req listAccountRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -94,9 +153,52 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 
 	accounts, err := server.store.ListAccounts(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		rsp[i], err = server.accountResponse(ctx, account)
+		if err != nil {
+			abortWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// getTransferAllowance returns the transfer limits of an account with what is left of them today and this month
+func (server *Server) getTransferAllowance(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			abortWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		abortWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	allowance, err := server.store.GetTransferAllowance(ctx, account)
+	if err != nil {
+		abortWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
+	ctx.JSON(http.StatusOK, allowance)
 }
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/fx"
+	"github.com/antimatter007/go-backend/token"
+)
+
+type createFxQuoteRequest struct {
+	FromCurrency string `json:"from_currency" binding:"required,currency"`
+	ToCurrency   string `json:"to_currency" binding:"required,currency,nefield=FromCurrency"`
+}
+
+type fxQuoteResponse struct {
+	ID           uuid.UUID `json:"id"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	Rate         string    `json:"rate"`
+	SpreadBps    int32     `json:"spread_bps"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func newFxQuoteResponse(quote db.FxQuote) fxQuoteResponse {
+	return fxQuoteResponse{
+		ID:           quote.ID,
+		FromCurrency: quote.FromCurrency,
+		ToCurrency:   quote.ToCurrency,
+		Rate:         fx.FormatRate(quote.Rate),
+		SpreadBps:    quote.SpreadBps,
+		ExpiresAt:    quote.ExpiresAt,
+	}
+}
+
+// createFxQuote locks the current exchange rate between two currencies for the authenticated user.
+// The quote can be used once for a transfer until it expires.
+func (server *Server) createFxQuote(ctx *gin.Context) {
+	var req createFxQuoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	rate, err := server.rateProvider.Rate(ctx, req.FromCurrency, req.ToCurrency)
+	if err != nil {
+		if errors.Is(err, fx.ErrRateNotFound) {
+			abortWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		abortWithError(ctx, http.StatusBadGateway, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.CreateFxQuoteParams{
+		ID:           uuid.New(),
+		Owner:        authPayload.Username,
+		FromCurrency: req.FromCurrency,
+		ToCurrency:   req.ToCurrency,
+		Rate:         rate.Value,
+		SpreadBps:    server.config.FXSpreadBps,
+		Source:       rate.Source,
+		ExpiresAt:    time.Now().Add(server.config.FXQuoteTTL),
+	}
+
+	quote, err := server.store.CreateFxQuote(ctx, arg)
+	if err != nil {
+		abortWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newFxQuoteResponse(quote))
+}
@@ -1,28 +1,78 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/fx"
+	"github.com/antimatter007/go-backend/problems"
 	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/worker"
 )
 
-func newTestServer(t *testing.T, store db.Store) *Server {
+func newTestServer(t *testing.T, store db.Store, taskDistributor worker.TaskDistributor) *Server {
 	config := util.Config{
 		TokenSymmetricKey:   util.RandomString(32),
 		AccessTokenDuration: time.Minute,
+		FXQuoteTTL:          30 * time.Second,
+		FXSpreadBps:         50,
 	}
 
-	server, err := NewServer(config, store)
+	rateProvider, err := fx.NewStaticProvider("test", fx.RateTable{
+		Base:  util.USD,
+		Rates: map[string]json.Number{util.EUR: "0.92", util.CAD: "1.36"},
+	})
+	require.NoError(t, err)
+
+	server, err := NewServer(config, store, taskDistributor, rateProvider, nil)
 	require.NoError(t, err)
 
 	return server
 }
 
+type eqWebhookEventMatcher struct {
+	event     string
+	usernames []string
+}
+
+func (e eqWebhookEventMatcher) Matches(x interface{}) bool {
+	payload, ok := x.(*worker.PayloadPublishWebhookEvent)
+	if !ok {
+		return false
+	}
+
+	return payload.Event == e.event && reflect.DeepEqual(payload.Usernames, e.usernames)
+}
+
+func (e eqWebhookEventMatcher) String() string {
+	return fmt.Sprintf("matches event %v for users %v", e.event, e.usernames)
+}
+
+func EqWebhookEvent(event string, usernames ...string) gomock.Matcher {
+	return eqWebhookEventMatcher{event, usernames}
+}
+
+func requireProblem(t *testing.T, body *bytes.Buffer, code problems.Code) problems.Problem {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var p problems.Problem
+	err = json.Unmarshal(data, &p)
+	require.NoError(t, err)
+	require.Equal(t, code, p.Code)
+	return p
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 
@@ -3,10 +3,14 @@ package api
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/antimatter007/go-backend/ratelimit"
+	"github.com/antimatter007/go-backend/requestid"
 	"github.com/antimatter007/go-backend/token"
 )
 
@@ -14,8 +18,27 @@ const (
 	authorizationHeaderKey  = "authorization"
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authorization_payload"
+	apiKeyHeaderKey         = "X-API-Key"
+	retryAfterHeaderKey     = "Retry-After"
 )
 
+// requestIDMiddleware creates a gin middleware that assigns every request an id. The id sent by the client
+// is kept when it is valid. The id is echoed in the response header and carried in the request context,
+// so that logs and problems can refer to it.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		ctx.Request.Header.Set(requestid.Header, id)
+		ctx.Header(requestid.Header, id)
+		ctx.Request = ctx.Request.WithContext(requestid.NewContext(ctx.Request.Context(), id))
+		ctx.Next()
+	}
+}
+
 // AuthMiddleware creates a gin middleware for authorization
 func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -23,28 +46,28 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 
 		if len(authorizationHeader) == 0 {
 			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
 		if len(fields) < 2 {
 			err := errors.New("invalid authorization header format")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
 		authorizationType := strings.ToLower(fields[0])
 		if authorizationType != authorizationTypeBearer {
 			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
 		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -52,3 +75,35 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// rateLimitMiddleware creates a gin middleware that answers 429 to the clients exceeding the limit of method.
+// Methods share their names and limits with the gRPC API. On authenticated routes it must run after
+// authMiddleware, so that requests can be counted by user. Requests are counted by API key only when it is one of apiKeys.
+func rateLimitMiddleware(limiter *ratelimit.Limiter, apiKeys ratelimit.APIKeys, method string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		identity := ratelimit.Identity{
+			ClientIP: ctx.ClientIP(),
+			APIKey:   apiKeys.Authenticate(ctx.GetHeader(apiKeyHeaderKey)),
+		}
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			identity.Username = payload.(*token.Payload).Username
+		}
+
+		result, err := limiter.Allow(ctx.Request.Context(), method, identity)
+		if err != nil {
+			requestid.Logger(ctx.Request.Context()).Error().Err(err).Msg("cannot apply rate limit")
+			ctx.Next()
+			return
+		}
+
+		if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			ctx.Header(retryAfterHeaderKey, strconv.Itoa(retryAfter))
+			err := fmt.Errorf("rate limit exceeded: retry in %ds", retryAfter)
+			abortWithError(ctx, http.StatusTooManyRequests, err)
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -9,7 +9,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
+	"github.com/antimatter007/go-backend/problems"
+	"github.com/antimatter007/go-backend/ratelimit"
+	"github.com/antimatter007/go-backend/requestid"
 	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
 )
 
 func addAuthorization(
@@ -18,9 +22,10 @@ func addAuthorization(
 	tokenMaker token.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(username, duration)
+	token, payload, err := tokenMaker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -37,7 +42,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -54,7 +59,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -63,7 +68,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorizationFormat",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -72,7 +77,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", -time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, -time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -84,7 +89,7 @@ func TestAuthMiddleware(t *testing.T) {
 		tc := testCases[i]
 
 		t.Run(tc.name, func(t *testing.T) {
-			server := newTestServer(t, nil)
+			server := newTestServer(t, nil, nil)
 			authPath := "/auth"
 			server.router.GET(
 				authPath,
@@ -104,3 +109,154 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimitMiddleware(t *testing.T) {
+	server := newTestServer(t, nil, nil)
+	limiter := ratelimit.NewLimiter(
+		ratelimit.NewLocalStore(),
+		ratelimit.Rule{Limit: ratelimit.Limit{Requests: 1, Per: time.Minute}, Key: ratelimit.KeyUser},
+		nil,
+	)
+
+	publicPath := "/public"
+	server.router.GET(
+		publicPath,
+		rateLimitMiddleware(limiter, nil, "Public"),
+		func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		},
+	)
+	authPath := "/auth"
+	server.router.GET(
+		authPath,
+		authMiddleware(server.tokenMaker),
+		rateLimitMiddleware(limiter, nil, "Auth"),
+		func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		},
+	)
+
+	serve := func(path string, username string, forwardedFor string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		request.RemoteAddr = "10.0.0.1:5000"
+		if forwardedFor != "" {
+			request.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+
+		if username != "" {
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, util.DepositorRole, time.Minute)
+		}
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// anonymous requests are counted by client IP
+	require.Equal(t, http.StatusOK, serve(publicPath, "", "").Code)
+	recorder := serve(publicPath, "", "")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get(retryAfterHeaderKey))
+
+	// the client is not a trusted proxy, a forwarded address does not give it a bucket of its own
+	require.Equal(t, http.StatusTooManyRequests, serve(publicPath, "", "203.0.113.7").Code)
+	require.Equal(t, http.StatusTooManyRequests, serve(publicPath, "", "203.0.113.8, 10.0.0.1").Code)
+
+	// authenticated requests are counted by user
+	require.Equal(t, http.StatusOK, serve(authPath, "alice", "").Code)
+	require.Equal(t, http.StatusOK, serve(authPath, "bob", "").Code)
+	require.Equal(t, http.StatusTooManyRequests, serve(authPath, "alice", "").Code)
+}
+
+func TestRateLimitMiddlewareTrustedProxy(t *testing.T) {
+	server := newTestServer(t, nil, nil)
+	config := server.config
+	config.TrustedProxies = []string{"10.0.0.0/8"}
+
+	limiter := ratelimit.NewLimiter(
+		ratelimit.NewLocalStore(),
+		ratelimit.Rule{Limit: ratelimit.Limit{Requests: 1, Per: time.Minute}, Key: ratelimit.KeyIP},
+		nil,
+	)
+	server, err := NewServer(config, nil, nil, server.rateProvider, limiter)
+	require.NoError(t, err)
+
+	serve := func(forwardedFor string) int {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/users/login", nil)
+		require.NoError(t, err)
+		request.RemoteAddr = "10.0.0.1:5000"
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// the proxy forwards the requests of two clients, each one has its bucket
+	require.NotEqual(t, http.StatusTooManyRequests, serve("203.0.113.7"))
+	require.NotEqual(t, http.StatusTooManyRequests, serve("203.0.113.8"))
+	require.Equal(t, http.StatusTooManyRequests, serve("203.0.113.7"))
+
+	// hops added by the client before the proxy are not trusted
+	require.Equal(t, http.StatusTooManyRequests, serve("198.51.100.1, 203.0.113.7"))
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	clientID := requestid.New()
+
+	testCases := []struct {
+		name          string
+		setupHeader   func(request *http.Request)
+		checkResponse func(t *testing.T, id string)
+	}{
+		{
+			name: "ClientID",
+			setupHeader: func(request *http.Request) {
+				request.Header.Set(requestid.Header, clientID)
+			},
+			checkResponse: func(t *testing.T, id string) {
+				require.Equal(t, clientID, id)
+			},
+		},
+		{
+			name: "NoID",
+			setupHeader: func(request *http.Request) {
+			},
+			checkResponse: func(t *testing.T, id string) {
+				require.True(t, requestid.Valid(id))
+			},
+		},
+		{
+			name: "InvalidID",
+			setupHeader: func(request *http.Request) {
+				request.Header.Set(requestid.Header, "bad id!")
+			},
+			checkResponse: func(t *testing.T, id string) {
+				require.True(t, requestid.Valid(id))
+				require.NotEqual(t, "bad id!", id)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil, nil)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/accounts", nil)
+			require.NoError(t, err)
+
+			tc.setupHeader(request)
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			id := recorder.Header().Get(requestid.Header)
+			tc.checkResponse(t, id)
+
+			p := requireProblem(t, recorder.Body, problems.CodeUnauthenticated)
+			require.Equal(t, id, p.RequestID)
+		})
+	}
+}
@@ -2,59 +2,105 @@ package api
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/fx"
+	"github.com/antimatter007/go-backend/problems"
+	"github.com/antimatter007/go-backend/ratelimit"
+	"github.com/antimatter007/go-backend/requestid"
 	"github.com/antimatter007/go-backend/token"
 	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/worker"
 )
 
 // Server serves HTTP requests for our banking service.
 type Server struct {
-	config     util.Config
-	store      db.Store
-	tokenMaker token.Maker
-	router     *gin.Engine
+	config          util.Config
+	store           db.Store
+	tokenMaker      token.Maker
+	taskDistributor worker.TaskDistributor
+	rateProvider    fx.RateProvider
+	limiter         *ratelimit.Limiter
+	apiKeys         ratelimit.APIKeys
+	router          *gin.Engine
 }
 
 // NewServer creates a new HTTP server and set up routing.
-func NewServer(config util.Config, store db.Store) (*Server, error) {
+// Requests are not rate limited when limiter is nil.
+func NewServer(
+	config util.Config,
+	store db.Store,
+	taskDistributor worker.TaskDistributor,
+	rateProvider fx.RateProvider,
+	limiter *ratelimit.Limiter,
+) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
+		config:          config,
+		store:           store,
+		tokenMaker:      tokenMaker,
+		taskDistributor: taskDistributor,
+		rateProvider:    rateProvider,
+		limiter:         limiter,
+		apiKeys:         ratelimit.NewAPIKeys(config.APIKeys),
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterTagNameFunc(jsonFieldName)
 	}
 
-	server.setupRouter()
+	err = server.setupRouter()
+	if err != nil {
+		return nil, err
+	}
 	return server, nil
 }
 
-func (server *Server) setupRouter() {
+func (server *Server) setupRouter() error {
 	router := gin.Default()
+	// ClientIP only reads X-Forwarded-For from these proxies, so that clients cannot pick their rate limit bucket
+	err := router.SetTrustedProxies(server.config.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("cannot set trusted proxies: %w", err)
+	}
+	router.Use(requestIDMiddleware())
 
-	router.POST("/users", server.createUser)
-	router.POST("/users/login", server.loginUser)
-	router.POST("/tokens/renew_access", server.renewAccessToken)
+	router.POST("/users", server.rateLimit("CreateUser"), server.createUser)
+	router.POST("/users/login", server.rateLimit("LoginUser"), server.loginUser)
+	router.POST("/tokens/renew_access", server.rateLimit("RenewAccessToken"), server.renewAccessToken)
 
 	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
-	authRoutes.POST("/accounts", server.createAccount)
-	authRoutes.GET("/accounts/:id", server.getAccount)
-	authRoutes.GET("/accounts", server.listAccounts)
+	authRoutes.POST("/accounts", server.rateLimit("CreateAccount"), server.createAccount)
+	authRoutes.GET("/accounts/:id", server.rateLimit("GetAccount"), server.getAccount)
+	authRoutes.GET("/accounts/:id/transfer_limits", server.rateLimit("GetTransferAllowance"), server.getTransferAllowance)
+	authRoutes.GET("/accounts", server.rateLimit("ListAccounts"), server.listAccounts)
 
-	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.POST("/transfers", server.rateLimit("CreateTransfer"), server.createTransfer)
+	authRoutes.POST("/transfers/batch", server.rateLimit("CreateBatchTransfer"), server.createBatchTransfer)
+	authRoutes.POST("/fx_quotes", server.rateLimit("CreateFxQuote"), server.createFxQuote)
 
 	server.router = router
+	return nil
+}
+
+// rateLimit limits the requests of a route as method, when the server has a limiter
+func (server *Server) rateLimit(method string) gin.HandlerFunc {
+	if server.limiter == nil {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+	return rateLimitMiddleware(server.limiter, server.apiKeys, method)
 }
 
 // Start runs the HTTP server on a specific address.
@@ -62,6 +108,39 @@ func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// abortWithError ends the request with err as a problem. The detail of server errors is
+// logged instead of being returned.
+func abortWithError(ctx *gin.Context, status int, err error) {
+	p := newProblem(ctx, status, err)
+	p.Instance = ctx.Request.URL.Path
+	p.RequestID = requestid.FromContext(ctx.Request.Context())
+
+	p.Write(ctx.Writer)
+	ctx.Abort()
+}
+
+// newProblem maps err to a problem. Database and validation errors have their own status and code,
+// other errors get the status chosen by the handler. Server errors are logged, since their detail is never returned.
+func newProblem(ctx *gin.Context, status int, err error) *problems.Problem {
+	p, ok := problems.FromError(err)
+	if !ok {
+		p = problems.New(problems.CodeForStatus(status), err.Error())
+		p.Status = status
+	}
+
+	if p.Internal() {
+		requestid.Logger(ctx.Request.Context()).Error().Err(err).Int("status", p.Status).Msg("request failed")
+	}
+	return p
+}
+
+// jsonFieldName names the fields of validation errors as they appear in requests
+func jsonFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "uri", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
 }
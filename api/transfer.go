@@ -6,8 +6,15 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/problems"
 	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/webhook"
+	"github.com/antimatter007/go-backend/worker"
+	"github.com/rs/zerolog/log"
 )
 
 type transferRequest struct {
@@ -15,12 +22,34 @@ type transferRequest struct {
 	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
 	Amount        int64  `json:"amount" binding:"required,gt=0"`
 	Currency      string `json:"currency" binding:"required,currency"`
+	// QuoteID converts the amount at a quoted rate when the destination account uses another currency
+	QuoteID string `json:"quote_id" binding:"omitempty,uuid"`
+}
+
+// transferResponse adds the debited and credited amounts as decimal strings in major units of their currencies
+type transferResponse struct {
+	db.TransferTxResult
+	AmountDecimal   string `json:"amount_decimal"`
+	ToAmountDecimal string `json:"to_amount_decimal"`
+}
+
+func newTransferResponse(result db.TransferTxResult) transferResponse {
+	toAmount := result.Transfer.Amount
+	if result.Transfer.ToAmount.Valid {
+		toAmount = result.Transfer.ToAmount.Int64
+	}
+
+	return transferResponse{
+		TransferTxResult: result,
+		AmountDecimal:    util.FormatAmount(result.Transfer.Amount, result.FromAccount.Currency),
+		ToAmountDecimal:  util.FormatAmount(toAmount, result.ToAccount.Currency),
+	}
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
 	var req transferRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -32,11 +61,16 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if fromAccount.Owner != authPayload.Username {
 		err := errors.New("from account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		abortWithError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	if req.QuoteID != "" {
+		server.createConvertedTransfer(ctx, req, authPayload.Username)
+		return
+	}
+
+	toAccount, valid := server.validAccount(ctx, req.ToAccountID, req.Currency)
 	if !valid {
 		return
 	}
@@ -49,30 +83,278 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 
 	result, err := server.store.TransferTx(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		abortWithError(ctx, transferErrorCode(err), err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	server.announceTransfer(ctx, result, fromAccount.Owner, toAccount.Owner)
+	ctx.JSON(http.StatusOK, newTransferResponse(result))
 }
 
-func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
-	account, err := server.store.GetAccount(ctx, accountID)
+// createConvertedTransfer transfers to an account of another currency at the rate locked by the quote.
+// The quote itself is checked against both accounts within the transaction.
+func (server *Server) createConvertedTransfer(ctx *gin.Context, req transferRequest, username string) {
+	quoteID, err := uuid.Parse(req.QuoteID)
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return account, false
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	toAccount, valid := server.existingAccount(ctx, req.ToAccountID)
+	if !valid {
+		return
+	}
+
+	arg := db.ConvertTransferTxParams{
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		QuoteID:       quoteID,
+		Owner:         username,
+	}
+
+	result, err := server.store.ConvertTransferTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			abortWithError(ctx, http.StatusNotFound, err)
+		case errors.Is(err, db.ErrFxQuoteExpired),
+			errors.Is(err, db.ErrFxQuoteUsed),
+			errors.Is(err, db.ErrFxQuoteMismatch),
+			errors.Is(err, db.ErrInvalidConversion):
+			abortWithError(ctx, http.StatusBadRequest, err)
+		default:
+			abortWithError(ctx, transferErrorCode(err), err)
 		}
+		return
+	}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	server.announceTransfer(ctx, result, username, toAccount.Owner)
+	ctx.JSON(http.StatusOK, newTransferResponse(result))
+}
+
+// transferErrorCode maps an error of a transfer transaction to its HTTP status code
+func transferErrorCode(err error) int {
+	switch {
+	case errors.Is(err, db.ErrInsufficientFunds):
+		return http.StatusBadRequest
+	case errors.Is(err, db.ErrAccountFrozen),
+		errors.Is(err, db.ErrAccountClosed),
+		errors.Is(err, db.ErrTransferLimitExceeded):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// announceTransfer lets both account owners know about a committed transfer
+func (server *Server) announceTransfer(ctx *gin.Context, result db.TransferTxResult, fromOwner string, toOwner string) {
+	server.notifyTransfer(ctx, result.Transfer)
+	server.publishWebhookEvent(ctx, webhook.EventTransferCreated, []string{fromOwner, toOwner}, result.Transfer)
+}
+
+// notifyTransfer enqueues the notifications to both account owners.
+// The transfer is already committed, so a failure here is only logged.
+func (server *Server) notifyTransfer(ctx *gin.Context, transfer db.Transfer) {
+	taskPayload := &worker.PayloadSendTransferNotification{
+		TransferID: transfer.ID,
+	}
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueDefault),
+	}
+
+	err := server.taskDistributor.DistributeTaskSendTransferNotification(ctx, taskPayload, opts...)
+	if err != nil {
+		log.Error().Err(err).Int64("transfer_id", transfer.ID).Msg("failed to enqueue transfer notification")
+	}
+}
+
+func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
+	account, valid := server.existingAccount(ctx, accountID)
+	if !valid {
 		return account, false
 	}
 
 	if account.Currency != currency {
 		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return account, false
+	}
+
+	return account, true
+}
+
+func (server *Server) existingAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			abortWithError(ctx, http.StatusNotFound, err)
+			return account, false
+		}
+
+		abortWithError(ctx, http.StatusInternalServerError, err)
 		return account, false
 	}
 
 	return account, true
 }
+
+type batchTransferLegRequest struct {
+	ToAccountID int64 `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64 `json:"amount" binding:"required,gt=0"`
+}
+
+type batchTransferRequest struct {
+	FromAccountID int64                     `json:"from_account_id" binding:"required,min=1"`
+	Currency      string                    `json:"currency" binding:"required,currency"`
+	Legs          []batchTransferLegRequest `json:"legs" binding:"required,min=1,max=500,dive"`
+	// BestEffort commits the legs that succeed instead of rejecting the whole batch on the first failure
+	BestEffort bool `json:"best_effort"`
+}
+
+// batchTransferLegResponse is the outcome of one leg, in the order of the request.
+// A failed leg has the problem code and detail that the error of a single transfer would get.
+type batchTransferLegResponse struct {
+	Index    int               `json:"index"`
+	Status   string            `json:"status"`
+	Transfer *transferResponse `json:"transfer,omitempty"`
+	Code     problems.Code     `json:"code,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// fail records the failure of the leg, without the detail of server errors
+func (legRsp *batchTransferLegResponse) fail(ctx *gin.Context, status int, err error) {
+	p := newProblem(ctx, status, err)
+	legRsp.Status = batchLegFailed
+	legRsp.Code = p.Code
+	legRsp.Error = p.SafeDetail()
+}
+
+type batchTransferResponse struct {
+	Succeeded int                        `json:"succeeded"`
+	Failed    int                        `json:"failed"`
+	Legs      []batchTransferLegResponse `json:"legs"`
+}
+
+// Statuses of a batch transfer leg
+const (
+	batchLegSucceeded = "succeeded"
+	batchLegFailed    = "failed"
+)
+
+// createBatchTransfer sends many transfers from one account, e.g. a payroll run.
+// All legs are validated before any money moves. By default the batch is all or nothing,
+// in best effort mode the invalid and failing legs are reported while the others are committed.
+func (server *Server) createBatchTransfer(ctx *gin.Context) {
+	var req batchTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != authPayload.Username {
+		err := errors.New("from account doesn't belong to the authenticated user")
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	rsp := batchTransferResponse{
+		Legs: make([]batchTransferLegResponse, len(req.Legs)),
+	}
+	toAccounts := map[int64]db.Account{}
+	arg := db.BatchTransferTxParams{
+		FromAccountID: req.FromAccountID,
+		BestEffort:    req.BestEffort,
+	}
+	// legIndexes maps the legs sent to the store back to the legs of the request
+	var legIndexes []int
+
+	for i, leg := range req.Legs {
+		rsp.Legs[i].Index = i
+
+		code, err := server.validateBatchLeg(ctx, req, leg, toAccounts)
+		if err != nil {
+			if !req.BestEffort || code == http.StatusInternalServerError {
+				abortWithError(ctx, code, fmt.Errorf("leg %d: %w", i, err))
+				return
+			}
+			rsp.Legs[i].fail(ctx, code, err)
+			continue
+		}
+
+		arg.Legs = append(arg.Legs, db.BatchTransferLeg{
+			ToAccountID: leg.ToAccountID,
+			Amount:      leg.Amount,
+		})
+		legIndexes = append(legIndexes, i)
+	}
+
+	if len(arg.Legs) > 0 {
+		result, err := server.store.BatchTransferTx(ctx, arg)
+		if err != nil {
+			var legErr *db.BatchLegError
+			if errors.As(err, &legErr) {
+				err = fmt.Errorf("leg %d: %w", legIndexes[legErr.Index], legErr.Err)
+			}
+			abortWithError(ctx, transferErrorCode(err), err)
+			return
+		}
+
+		for j, legResult := range result.Legs {
+			legRsp := &rsp.Legs[legIndexes[j]]
+			if legResult.Err != nil {
+				legRsp.fail(ctx, transferErrorCode(legResult.Err), legResult.Err)
+				continue
+			}
+
+			transfer := newTransferResponse(legResult.Result)
+			legRsp.Status = batchLegSucceeded
+			legRsp.Transfer = &transfer
+			server.announceTransfer(ctx, legResult.Result, fromAccount.Owner, toAccounts[legResult.Result.Transfer.ToAccountID].Owner)
+		}
+	}
+
+	for _, leg := range rsp.Legs {
+		if leg.Status == batchLegSucceeded {
+			rsp.Succeeded++
+		} else {
+			rsp.Failed++
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// validateBatchLeg checks the destination account of a leg without writing a response,
+// and returns the status code that the failure maps to. Looked up accounts are cached in toAccounts.
+func (server *Server) validateBatchLeg(ctx *gin.Context, req batchTransferRequest, leg batchTransferLegRequest, toAccounts map[int64]db.Account) (int, error) {
+	if leg.ToAccountID == req.FromAccountID {
+		return http.StatusBadRequest, errors.New("cannot transfer to the source account")
+	}
+
+	account, ok := toAccounts[leg.ToAccountID]
+	if !ok {
+		var err error
+		account, err = server.store.GetAccount(ctx, leg.ToAccountID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return http.StatusNotFound, fmt.Errorf("account [%d] not found", leg.ToAccountID)
+			}
+			return http.StatusInternalServerError, err
+		}
+		toAccounts[leg.ToAccountID] = account
+	}
+
+	if account.Currency != req.Currency {
+		return http.StatusBadRequest, fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, req.Currency)
+	}
+
+	return http.StatusOK, nil
+}
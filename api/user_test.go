@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 	mockdb "github.com/antimatter007/go-backend/db/mock"
 	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/problems"
 	"github.com/antimatter007/go-backend/util"
 )
 
@@ -113,7 +114,8 @@ func TestCreateUserAPI(t *testing.T) {
 					Return(db.User{}, db.ErrUniqueViolation)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireProblem(t, recorder.Body, problems.CodeAlreadyExists)
 			},
 		},
 		{
@@ -131,6 +133,10 @@ func TestCreateUserAPI(t *testing.T) {
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				p := requireProblem(t, recorder.Body, problems.CodeInvalidArgument)
+				require.Equal(t, []problems.InvalidParam{
+					{Name: "username", Reason: "must satisfy alphanum"},
+				}, p.InvalidParams)
 			},
 		},
 		{
@@ -179,7 +185,7 @@ func TestCreateUserAPI(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
 
-			server := newTestServer(t, store)
+			server := newTestServer(t, store, nil)
 			recorder := httptest.NewRecorder()
 
 			// Marshal body data to JSON
@@ -299,7 +305,7 @@ func TestLoginUserAPI(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
 
-			server := newTestServer(t, store)
+			server := newTestServer(t, store, nil)
 			recorder := httptest.NewRecorder()
 
 			// Marshal body data to JSON
@@ -326,6 +332,7 @@ func randomUser(t *testing.T) (user db.User, password string) {
 		HashedPassword: hashedPassword,
 		FullName:       util.RandomOwner(),
 		Email:          util.RandomEmail(),
+		Role:           util.DepositorRole,
 	}
 	return
 }
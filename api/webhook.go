@@ -0,0 +1,28 @@
+package api
+
+import (
+	"github.com/antimatter007/go-backend/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// publishWebhookEvent enqueues an event for the webhook endpoints of the given users.
+// The change it describes is already committed, so a failure here is only logged.
+func (server *Server) publishWebhookEvent(ctx *gin.Context, event string, usernames []string, data interface{}) {
+	taskPayload, err := worker.NewPayloadPublishWebhookEvent(event, usernames, data)
+	if err != nil {
+		log.Error().Err(err).Str("event", event).Msg("failed to create webhook event")
+		return
+	}
+
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueDefault),
+	}
+
+	err = server.taskDistributor.DistributeTaskPublishWebhookEvent(ctx, taskPayload, opts...)
+	if err != nil {
+		log.Error().Err(err).Str("event", event).Msg("failed to enqueue webhook event")
+	}
+}
@@ -0,0 +1,3 @@
+CREATE INDEX "sessions_expires_at_idx" ON "sessions" ("expires_at");
+
+CREATE INDEX "verify_emails_expired_at_idx" ON "verify_emails" ("expired_at");
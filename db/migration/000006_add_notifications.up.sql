@@ -0,0 +1,25 @@
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "event" varchar NOT NULL,
+  "title" varchar NOT NULL,
+  "content" varchar NOT NULL,
+  "is_read" bool NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "read_at" timestamptz
+);
+
+CREATE TABLE "notification_preferences" (
+  "username" varchar NOT NULL,
+  "event" varchar NOT NULL,
+  "channel" varchar NOT NULL,
+  "enabled" bool NOT NULL DEFAULT true,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "event", "channel")
+);
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE INDEX ON "notifications" ("username", "id");
@@ -0,0 +1,18 @@
+CREATE TABLE "failed_tasks" (
+  "id" bigserial PRIMARY KEY,
+  "task_id" varchar NOT NULL,
+  "queue" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "payload" bytea NOT NULL,
+  "last_error" varchar NOT NULL,
+  "retried" int NOT NULL,
+  "failure_count" int NOT NULL DEFAULT 1,
+  "resolution" varchar NOT NULL DEFAULT 'archived',
+  "resolved_by" varchar NOT NULL DEFAULT '',
+  "failed_at" timestamptz NOT NULL DEFAULT (now()),
+  "resolved_at" timestamptz
+);
+
+CREATE UNIQUE INDEX ON "failed_tasks" ("queue", "task_id");
+
+CREATE INDEX ON "failed_tasks" ("type", "failed_at");
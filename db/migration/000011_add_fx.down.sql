@@ -0,0 +1,9 @@
+ALTER TABLE "transfers" DROP CONSTRAINT IF EXISTS "transfers_conversion_check";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "spread_bps";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "exchange_rate";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "to_amount";
+
+DROP TABLE IF EXISTS "fx_quotes";
@@ -0,0 +1,33 @@
+CREATE TABLE "fx_quotes" (
+  "id" uuid PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" bigint NOT NULL,
+  "spread_bps" int NOT NULL,
+  "source" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "fx_quotes" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+CREATE INDEX ON "fx_quotes" ("owner");
+
+COMMENT ON COLUMN "fx_quotes"."rate" IS 'mid-market rate, fixed point with 8 decimals';
+
+ALTER TABLE "transfers" ADD COLUMN "to_amount" bigint;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" bigint;
+
+ALTER TABLE "transfers" ADD COLUMN "spread_bps" int;
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_conversion_check" CHECK (
+  ("to_amount" IS NULL) = ("exchange_rate" IS NULL) AND
+  ("to_amount" IS NULL) = ("spread_bps" IS NULL)
+);
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'amount credited in the currency of the destination account, null when both accounts share a currency';
+
+COMMENT ON COLUMN "transfers"."exchange_rate" IS 'mid-market rate, fixed point with 8 decimals';
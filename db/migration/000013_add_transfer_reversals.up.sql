@@ -0,0 +1,7 @@
+ALTER TABLE "transfers" ADD COLUMN "reversed_transfer_id" bigint;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversed_transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "transfers" ("reversed_transfer_id");
+
+COMMENT ON COLUMN "transfers"."reversed_transfer_id" IS 'transfer that this one reverses, in full or in part';
@@ -0,0 +1,19 @@
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'system');
+
+DELETE FROM "accounts" WHERE "owner" = 'system';
+
+DELETE FROM "users" WHERE "username" = 'system';
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_kind_key";
+
+ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "kind";
+
+DROP TRIGGER IF EXISTS "journal_entry_balanced" ON "entries";
+
+DROP FUNCTION IF EXISTS "check_journal_entry_balanced";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "journal_entry_id";
+
+DROP TABLE IF EXISTS "journal_entries";
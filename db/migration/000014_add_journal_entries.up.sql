@@ -0,0 +1,56 @@
+CREATE TABLE "journal_entries" (
+  "id" bigserial PRIMARY KEY,
+  "kind" varchar NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "journal_entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "journal_entries" ("transfer_id");
+
+ALTER TABLE "entries" ADD COLUMN "journal_entry_id" bigint;
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("journal_entry_id") REFERENCES "journal_entries" ("id");
+
+CREATE INDEX ON "entries" ("journal_entry_id");
+
+COMMENT ON COLUMN "entries"."journal_entry_id" IS 'posting that the entry is a leg of, null for entries older than the journal';
+
+-- the entries of a journal entry must sum to zero in every currency,
+-- checked at commit time so that all legs can be inserted first
+CREATE FUNCTION "check_journal_entry_balanced"() RETURNS trigger AS $$
+BEGIN
+  IF EXISTS (
+    SELECT 1
+    FROM "entries" e
+    JOIN "accounts" a ON a."id" = e."account_id"
+    WHERE e."journal_entry_id" = NEW."journal_entry_id"
+    GROUP BY a."currency"
+    HAVING SUM(e."amount") <> 0
+  ) THEN
+    RAISE EXCEPTION 'journal entry % does not balance', NEW."journal_entry_id"
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'journal_entry_balanced';
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER "journal_entry_balanced"
+  AFTER INSERT OR UPDATE ON "entries"
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW
+  WHEN (NEW."journal_entry_id" IS NOT NULL)
+  EXECUTE FUNCTION "check_journal_entry_balanced"();
+
+-- system accounts hold the other side of postings that don't move money between customers
+ALTER TABLE "accounts" ADD COLUMN "kind" varchar NOT NULL DEFAULT 'customer';
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_kind_key" UNIQUE ("owner", "currency", "kind");
+
+COMMENT ON COLUMN "accounts"."kind" IS 'customer, or the purpose of a system account: fees, external_cash or fx';
+
+INSERT INTO "users" ("username", "role", "hashed_password", "full_name", "email", "is_email_verified")
+VALUES ('system', 'banker', '', 'System', 'system@simplebank.invalid', true);
@@ -0,0 +1,32 @@
+CREATE TABLE "reconciliation_reports" (
+  "id" bigserial PRIMARY KEY,
+  "status" varchar NOT NULL DEFAULT 'running',
+  "accounts_checked" bigint NOT NULL DEFAULT 0,
+  "discrepancy_count" bigint NOT NULL DEFAULT 0,
+  "error" varchar NOT NULL DEFAULT '',
+  "started_at" timestamptz NOT NULL DEFAULT (now()),
+  "finished_at" timestamptz
+);
+
+CREATE INDEX ON "reconciliation_reports" ("started_at");
+
+COMMENT ON COLUMN "reconciliation_reports"."status" IS 'running, completed or failed';
+
+CREATE TABLE "reconciliation_discrepancies" (
+  "id" bigserial PRIMARY KEY,
+  "report_id" bigint NOT NULL,
+  "account_id" bigint NOT NULL,
+  "stored_balance" bigint NOT NULL,
+  "ledger_balance" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "reconciliation_discrepancies" ADD FOREIGN KEY ("report_id") REFERENCES "reconciliation_reports" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "reconciliation_discrepancies" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "reconciliation_discrepancies" ("report_id");
+
+COMMENT ON COLUMN "reconciliation_discrepancies"."stored_balance" IS 'accounts.balance when the account was checked';
+
+COMMENT ON COLUMN "reconciliation_discrepancies"."ledger_balance" IS 'sum of the entries of the account when it was checked';
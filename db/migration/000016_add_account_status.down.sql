@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "accounts_owner_currency_kind_key";
+
+ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "owner_currency_kind_key" UNIQUE ("owner", "currency", "kind");
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status_changed_at";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status_reason";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,12 @@
+ALTER TABLE "accounts" ADD COLUMN "status" varchar NOT NULL DEFAULT 'active';
+
+ALTER TABLE "accounts" ADD COLUMN "status_reason" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD COLUMN "status_changed_at" timestamptz;
+
+COMMENT ON COLUMN "accounts"."status" IS 'active, frozen or closed';
+
+-- a closed account no longer blocks opening a new one in the same currency
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_kind_key";
+
+CREATE UNIQUE INDEX "accounts_owner_currency_kind_key" ON "accounts" ("owner", "currency", "kind") WHERE "status" <> 'closed';
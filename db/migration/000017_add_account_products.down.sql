@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "interest_accruals";
+
+COMMENT ON COLUMN "accounts"."kind" IS 'customer, or the purpose of a system account: fees, external_cash or fx';
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "interest_carry";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "product_id";
+
+DROP TABLE IF EXISTS "account_products";
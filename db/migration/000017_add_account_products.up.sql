@@ -0,0 +1,49 @@
+CREATE TABLE "account_products" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar UNIQUE NOT NULL,
+  "interest_rate_bps" int NOT NULL DEFAULT 0,
+  "transfer_fee_flat" bigint NOT NULL DEFAULT 0,
+  "transfer_fee_bps" int NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("interest_rate_bps" >= 0 AND "transfer_fee_flat" >= 0 AND "transfer_fee_bps" BETWEEN 0 AND 10000)
+);
+
+COMMENT ON COLUMN "account_products"."interest_rate_bps" IS 'annual interest rate in basis points, accrued daily on a 365-day year';
+
+COMMENT ON COLUMN "account_products"."transfer_fee_flat" IS 'fee charged on every outgoing transfer, in minor units of the account currency';
+
+COMMENT ON COLUMN "account_products"."transfer_fee_bps" IS 'fee charged on every outgoing transfer, in basis points of its amount';
+
+ALTER TABLE "accounts" ADD COLUMN "product_id" bigint;
+
+ALTER TABLE "accounts" ADD COLUMN "interest_carry" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("product_id") REFERENCES "account_products" ("id");
+
+COMMENT ON COLUMN "accounts"."kind" IS 'customer, or the purpose of a system account: fees, external_cash, fx or interest';
+
+COMMENT ON COLUMN "accounts"."interest_carry" IS 'accrued interest left over from the last posting, in 1/3650000 of a minor unit';
+
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "interest_rate_bps" int NOT NULL,
+  "accrued" bigint NOT NULL,
+  "journal_entry_id" bigint,
+  "posted_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("journal_entry_id") REFERENCES "journal_entries" ("id");
+
+CREATE UNIQUE INDEX ON "interest_accruals" ("account_id", "accrual_date");
+
+CREATE INDEX ON "interest_accruals" ("account_id") WHERE "posted_at" IS NULL;
+
+COMMENT ON COLUMN "interest_accruals"."accrued" IS 'interest of the day in 1/3650000 of a minor unit: balance times interest_rate_bps';
+
+COMMENT ON COLUMN "interest_accruals"."journal_entry_id" IS 'posting that paid the interest out, null until posted or when it rounded to zero';
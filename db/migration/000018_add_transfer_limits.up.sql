@@ -0,0 +1,40 @@
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar,
+  "currency" varchar NOT NULL,
+  "max_per_transfer" bigint,
+  "daily_per_account" bigint,
+  "monthly_per_account" bigint,
+  "daily_per_user" bigint,
+  "monthly_per_user" bigint,
+  "updated_by" varchar NOT NULL DEFAULT '',
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("currency") WHERE "username" IS NULL;
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("username", "currency") WHERE "username" IS NOT NULL;
+
+COMMENT ON TABLE "transfer_limits" IS 'limits on outgoing transfers in minor units, a null limit is unlimited on a default and inherits the default on an override';
+
+COMMENT ON COLUMN "transfer_limits"."username" IS 'user that the limits override the defaults for, null for the defaults of the currency';
+
+-- outgoing totals are summed per account and period
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+INSERT INTO "transfer_limits" (
+  "currency",
+  "max_per_transfer",
+  "daily_per_account",
+  "monthly_per_account",
+  "daily_per_user",
+  "monthly_per_user",
+  "updated_by"
+) VALUES
+  ('USD', 1000000, 2500000, 10000000, 5000000, 20000000, 'system'),
+  ('EUR', 1000000, 2500000, 10000000, 5000000, 20000000, 'system'),
+  ('CAD', 1000000, 2500000, 10000000, 5000000, 20000000, 'system'),
+  ('GBP', 1000000, 2500000, 10000000, 5000000, 20000000, 'system'),
+  ('JPY', 1500000, 4000000, 15000000, 8000000, 30000000, 'system');
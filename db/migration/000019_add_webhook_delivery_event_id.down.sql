@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "webhook_deliveries" DROP CONSTRAINT IF EXISTS "webhook_deliveries_endpoint_id_event_id_key";
+
+ALTER TABLE IF EXISTS "webhook_deliveries" DROP COLUMN IF EXISTS "event_id";
@@ -0,0 +1,15 @@
+ALTER TABLE "webhook_deliveries" ADD COLUMN "event_id" varchar;
+
+-- the envelope of earlier deliveries carries their event id
+UPDATE "webhook_deliveries" SET "event_id" = COALESCE("payload"->>'id', "id"::varchar);
+
+-- deliveries duplicated by retried publish tasks are kept, under an event id of their own
+UPDATE "webhook_deliveries" AS d SET "event_id" = d."event_id" || ':' || d."id"
+WHERE EXISTS (
+  SELECT 1 FROM "webhook_deliveries" AS o
+  WHERE o."endpoint_id" = d."endpoint_id" AND o."event_id" = d."event_id" AND o."id" < d."id"
+);
+
+ALTER TABLE "webhook_deliveries" ALTER COLUMN "event_id" SET NOT NULL;
+
+ALTER TABLE "webhook_deliveries" ADD CONSTRAINT "webhook_deliveries_endpoint_id_event_id_key" UNIQUE ("endpoint_id", "event_id");
@@ -2,9 +2,10 @@
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  product_id
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 ) RETURNING *;
 
 -- name: GetAccount :one
@@ -35,6 +36,33 @@ SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET
+  status = $2,
+  status_reason = $3,
+  status_changed_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAccountInterestCarry :one
+UPDATE accounts
+SET interest_carry = $2
+WHERE id = $1
+RETURNING *;
+
 -- name: DeleteAccount :exec
 DELETE FROM accounts
 WHERE id = $1;
+
+-- name: UpsertSystemAccount :one
+INSERT INTO accounts (
+  owner,
+  balance,
+  currency,
+  kind
+) VALUES (
+  'system', 0, $1, $2
+) ON CONFLICT (owner, currency, kind) WHERE status <> 'closed' DO UPDATE
+SET kind = EXCLUDED.kind
+RETURNING *;
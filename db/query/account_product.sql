@@ -0,0 +1,17 @@
+-- name: CreateAccountProduct :one
+INSERT INTO account_products (
+  name,
+  interest_rate_bps,
+  transfer_fee_flat,
+  transfer_fee_bps
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetAccountProduct :one
+SELECT * FROM account_products
+WHERE id = $1 LIMIT 1;
+
+-- name: GetAccountProductByName :one
+SELECT * FROM account_products
+WHERE name = $1 LIMIT 1;
@@ -1,9 +1,10 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  journal_entry_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 ) RETURNING *;
 
 -- name: GetEntry :one
@@ -16,3 +17,8 @@ WHERE account_id = $1
 ORDER BY id
 LIMIT $2
 OFFSET $3;
+
+-- name: ListJournalEntryLines :many
+SELECT * FROM entries
+WHERE journal_entry_id = $1
+ORDER BY id;
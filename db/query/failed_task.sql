@@ -0,0 +1,34 @@
+-- name: UpsertFailedTask :one
+INSERT INTO failed_tasks (
+  task_id,
+  queue,
+  type,
+  payload,
+  last_error,
+  retried
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (queue, task_id) DO UPDATE
+SET
+  type = EXCLUDED.type,
+  payload = EXCLUDED.payload,
+  last_error = EXCLUDED.last_error,
+  retried = EXCLUDED.retried,
+  failure_count = failed_tasks.failure_count + 1,
+  resolution = 'archived',
+  resolved_by = '',
+  failed_at = now(),
+  resolved_at = NULL
+RETURNING *;
+
+-- name: ResolveFailedTask :execrows
+UPDATE failed_tasks
+SET
+  resolution = sqlc.arg(resolution),
+  resolved_by = sqlc.arg(resolved_by),
+  resolved_at = now()
+WHERE
+  queue = sqlc.arg(queue)
+  AND task_id = sqlc.arg(task_id)
+  AND resolution = 'archived';
@@ -0,0 +1,24 @@
+-- name: CreateFxQuote :one
+INSERT INTO fx_quotes (
+  id,
+  owner,
+  from_currency,
+  to_currency,
+  rate,
+  spread_bps,
+  source,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetFxQuoteForUpdate :one
+SELECT * FROM fx_quotes
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: MarkFxQuoteUsed :one
+UPDATE fx_quotes
+SET used_at = now()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,61 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+  from_account_id,
+  to_account_id,
+  amount,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetHeldAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS held_amount
+FROM holds
+WHERE
+  from_account_id = $1
+  AND status = 'active'
+  AND expires_at > now();
+
+-- name: UpdateHold :one
+UPDATE holds
+SET
+  status = $2,
+  captured_amount = $3,
+  transfer_id = $4,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: VoidHold :one
+UPDATE holds
+SET
+  status = 'voided',
+  updated_at = now()
+WHERE
+  id = $1
+  AND status = 'active'
+RETURNING *;
+
+-- name: ExpireHolds :execrows
+UPDATE holds
+SET
+  status = 'expired',
+  updated_at = now()
+WHERE id IN (
+  SELECT id FROM holds
+  WHERE
+    status = 'active'
+    AND expires_at <= now()
+  ORDER BY expires_at
+  LIMIT $1
+  FOR UPDATE SKIP LOCKED
+);
@@ -0,0 +1,56 @@
+-- name: AccrueInterest :one
+WITH batch AS (
+  SELECT a.id, a.balance, p.interest_rate_bps
+  FROM accounts a
+  JOIN account_products p ON p.id = a.product_id
+  WHERE a.id > sqlc.arg(after_id)
+    AND a.status = 'active'
+  ORDER BY a.id
+  LIMIT sqlc.arg(limit)
+), accrued AS (
+  INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    balance,
+    interest_rate_bps,
+    accrued
+  )
+  SELECT id, sqlc.arg(accrual_date)::date, balance, interest_rate_bps, balance * interest_rate_bps
+  FROM batch
+  WHERE balance > 0 AND interest_rate_bps > 0
+  ON CONFLICT (account_id, accrual_date) DO NOTHING
+)
+SELECT
+  COUNT(*)::int AS accounts,
+  COALESCE(MAX(id), 0)::bigint AS last_account_id
+FROM batch;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date
+LIMIT $2
+OFFSET $3;
+
+-- name: ListUnpostedInterestAccounts :many
+SELECT DISTINCT account_id FROM interest_accruals
+WHERE posted_at IS NULL
+  AND accrual_date < sqlc.arg(before)::date
+ORDER BY account_id
+LIMIT sqlc.arg(limit);
+
+-- name: MarkInterestPosted :execrows
+UPDATE interest_accruals
+SET
+  journal_entry_id = sqlc.arg(journal_entry_id),
+  posted_at = now()
+WHERE account_id = sqlc.arg(account_id)
+  AND posted_at IS NULL
+  AND accrual_date < sqlc.arg(before)::date;
+
+-- name: SumUnpostedInterest :one
+SELECT COALESCE(SUM(accrued), 0)::bigint AS accrued
+FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+  AND posted_at IS NULL
+  AND accrual_date < sqlc.arg(before)::date;
@@ -0,0 +1,11 @@
+-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (
+  kind,
+  transfer_id
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetJournalEntry :one
+SELECT * FROM journal_entries
+WHERE id = $1 LIMIT 1;
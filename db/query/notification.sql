@@ -0,0 +1,48 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (
+  username,
+  event,
+  title,
+  content
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE
+  username = sqlc.arg(username)
+  AND (NOT sqlc.arg(unread_only)::bool OR is_read = FALSE)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET
+  is_read = TRUE,
+  read_at = COALESCE(read_at, now())
+WHERE
+  id = sqlc.arg(id)
+  AND username = sqlc.arg(username)
+RETURNING *;
+
+-- name: ListNotificationPreferences :many
+SELECT * FROM notification_preferences
+WHERE username = $1
+ORDER BY event, channel;
+
+-- name: UpsertNotificationPreference :one
+INSERT INTO notification_preferences (
+  username,
+  event,
+  channel,
+  enabled
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (username, event, channel) DO UPDATE
+SET
+  enabled = EXCLUDED.enabled,
+  updated_at = now()
+RETURNING *;
@@ -0,0 +1,47 @@
+-- name: CreateReconciliationReport :one
+INSERT INTO reconciliation_reports DEFAULT VALUES
+RETURNING *;
+
+-- name: FinishReconciliationReport :one
+UPDATE reconciliation_reports
+SET
+  status = sqlc.arg(status),
+  accounts_checked = sqlc.arg(accounts_checked),
+  discrepancy_count = sqlc.arg(discrepancy_count),
+  error = sqlc.arg(error),
+  finished_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetLatestReconciliationReport :one
+SELECT * FROM reconciliation_reports
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListAccountLedgerBalances :many
+SELECT
+  a.id,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::bigint AS ledger_balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id > sqlc.arg(after_id)
+GROUP BY a.id
+ORDER BY a.id
+LIMIT sqlc.arg('limit');
+
+-- name: CreateReconciliationDiscrepancy :one
+INSERT INTO reconciliation_discrepancies (
+  report_id,
+  account_id,
+  stored_balance,
+  ledger_balance
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListReconciliationDiscrepancies :many
+SELECT * FROM reconciliation_discrepancies
+WHERE report_id = $1
+ORDER BY account_id
+LIMIT $2;
@@ -0,0 +1,57 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+  owner,
+  from_account_id,
+  to_account_id,
+  amount,
+  recurrence,
+  first_run_at,
+  next_run_at,
+  end_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: ListDueScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE
+  status = 'active'
+  AND next_run_at <= now()
+ORDER BY next_run_at
+LIMIT $1;
+
+-- name: UpdateScheduledTransferSchedule :one
+UPDATE scheduled_transfers
+SET
+  status = $2,
+  next_run_at = $3,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+  scheduled_transfer_id,
+  scheduled_for,
+  status,
+  transfer_id,
+  error
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
@@ -14,3 +14,12 @@ INSERT INTO sessions (
 -- name: GetSession :one
 SELECT * FROM sessions
 WHERE id = $1 LIMIT 1;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions
+WHERE id IN (
+  SELECT id FROM sessions
+  WHERE expires_at < now() OR is_blocked = TRUE
+  ORDER BY expires_at
+  LIMIT $1
+);
@@ -7,10 +7,42 @@ INSERT INTO transfers (
   $1, $2, $3
 ) RETURNING *;
 
+-- name: CreateConvertedTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  to_amount,
+  exchange_rate,
+  spread_bps
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: CreateReversalTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  reversed_transfer_id
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS reversed_amount
+FROM transfers
+WHERE reversed_transfer_id = $1;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 WHERE 
@@ -19,3 +51,16 @@ WHERE
 ORDER BY id
 LIMIT $3
 OFFSET $4;
+
+-- name: GetOutgoingTransferTotals :one
+SELECT
+  COALESCE(SUM(t.amount) FILTER (WHERE t.from_account_id = sqlc.arg(account_id) AND t.created_at >= sqlc.arg(day_start)), 0)::bigint AS account_daily,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.from_account_id = sqlc.arg(account_id)), 0)::bigint AS account_monthly,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.created_at >= sqlc.arg(day_start)), 0)::bigint AS user_daily,
+  COALESCE(SUM(t.amount), 0)::bigint AS user_monthly
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE a.owner = sqlc.arg(owner)
+  AND a.currency = sqlc.arg(currency)
+  AND t.reversed_transfer_id IS NULL
+  AND t.created_at >= sqlc.arg(month_start);
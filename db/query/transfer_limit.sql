@@ -0,0 +1,28 @@
+-- name: ListTransferLimits :many
+SELECT * FROM transfer_limits
+WHERE currency = sqlc.arg(currency)
+  AND (username = sqlc.arg(username)::varchar OR username IS NULL)
+ORDER BY username NULLS FIRST;
+
+-- name: UpsertTransferLimits :one
+INSERT INTO transfer_limits (
+  username,
+  currency,
+  max_per_transfer,
+  daily_per_account,
+  monthly_per_account,
+  daily_per_user,
+  monthly_per_user,
+  updated_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) ON CONFLICT (username, currency) WHERE username IS NOT NULL DO UPDATE
+SET
+  max_per_transfer = EXCLUDED.max_per_transfer,
+  daily_per_account = EXCLUDED.daily_per_account,
+  monthly_per_account = EXCLUDED.monthly_per_account,
+  daily_per_user = EXCLUDED.daily_per_user,
+  monthly_per_user = EXCLUDED.monthly_per_user,
+  updated_by = EXCLUDED.updated_by,
+  updated_at = now()
+RETURNING *;
@@ -17,3 +17,12 @@ WHERE
     AND is_used = FALSE
     AND expired_at > now()
 RETURNING *;
+
+-- name: DeleteExpiredVerifyEmails :execrows
+DELETE FROM verify_emails
+WHERE id IN (
+    SELECT id FROM verify_emails
+    WHERE expired_at < now()
+    ORDER BY expired_at
+    LIMIT $1
+);
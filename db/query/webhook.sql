@@ -0,0 +1,65 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+  owner,
+  url,
+  secret,
+  events
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookEndpoints :many
+SELECT * FROM webhook_endpoints
+WHERE owner = $1
+ORDER BY id;
+
+-- name: ListWebhookEndpointsForEvent :many
+SELECT * FROM webhook_endpoints
+WHERE
+  owner = sqlc.arg(owner)
+  AND is_active = TRUE
+  AND sqlc.arg(event)::varchar = ANY(events)
+ORDER BY id;
+
+-- name: DeleteWebhookEndpoint :exec
+DELETE FROM webhook_endpoints
+WHERE id = $1;
+
+-- name: CreateWebhookDelivery :one
+-- An event already delivered to the endpoint returns its existing delivery.
+INSERT INTO webhook_deliveries (
+  endpoint_id,
+  event_id,
+  event,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (endpoint_id, event_id) DO UPDATE SET event_id = EXCLUDED.event_id
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE endpoint_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateWebhookDelivery :one
+UPDATE webhook_deliveries
+SET
+  status = $2,
+  attempts = attempts + 1,
+  response_status = $3,
+  last_error = $4,
+  delivered_at = $5
+WHERE id = $1
+RETURNING *;
@@ -7,13 +7,15 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, kind, status, status_reason, status_changed_at, product_id, interest_carry
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +32,12 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.ProductID,
+		&i.InterestCarry,
 	)
 	return i, err
 }
@@ -38,20 +46,27 @@ const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  product_id
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+  $1, $2, $3, $4
+) RETURNING id, owner, balance, currency, created_at, kind, status, status_reason, status_changed_at, product_id, interest_carry
 `
 
 type CreateAccountParams struct {
-	Owner    string `json:"owner"`
-	Balance  int64  `json:"balance"`
-	Currency string `json:"currency"`
+	Owner     string      `json:"owner"`
+	Balance   int64       `json:"balance"`
+	Currency  string      `json:"currency"`
+	ProductID pgtype.Int8 `json:"product_id"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRow(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRow(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.ProductID,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -59,6 +74,12 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.ProductID,
+		&i.InterestCarry,
 	)
 	return i, err
 }
@@ -74,7 +95,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, kind, status, status_reason, status_changed_at FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -87,12 +108,18 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.ProductID,
+		&i.InterestCarry,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, kind, status, status_reason, status_changed_at FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -106,12 +133,18 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.ProductID,
+		&i.InterestCarry,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, kind, status, status_reason, status_changed_at FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
@@ -139,6 +172,12 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.Kind,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.ProductID,
+			&i.InterestCarry,
 		); err != nil {
 			return nil, err
 		}
@@ -154,7 +193,7 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, kind, status, status_reason, status_changed_at, product_id, interest_carry
 `
 
 type UpdateAccountParams struct {
@@ -171,6 +210,115 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.ProductID,
+		&i.InterestCarry,
+	)
+	return i, err
+}
+
+const updateAccountInterestCarry = `-- name: UpdateAccountInterestCarry :one
+UPDATE accounts
+SET interest_carry = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, kind, status, status_reason, status_changed_at, product_id, interest_carry
+`
+
+type UpdateAccountInterestCarryParams struct {
+	ID            int64 `json:"id"`
+	InterestCarry int64 `json:"interest_carry"`
+}
+
+func (q *Queries) UpdateAccountInterestCarry(ctx context.Context, arg UpdateAccountInterestCarryParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountInterestCarry, arg.ID, arg.InterestCarry)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Kind,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.ProductID,
+		&i.InterestCarry,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET
+  status = $2,
+  status_reason = $3,
+  status_changed_at = now()
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, kind, status, status_reason, status_changed_at, product_id, interest_carry
+`
+
+type UpdateAccountStatusParams struct {
+	ID           int64  `json:"id"`
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountStatus, arg.ID, arg.Status, arg.StatusReason)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Kind,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.ProductID,
+		&i.InterestCarry,
+	)
+	return i, err
+}
+
+const upsertSystemAccount = `-- name: UpsertSystemAccount :one
+INSERT INTO accounts (
+  owner,
+  balance,
+  currency,
+  kind
+) VALUES (
+  'system', 0, $1, $2
+) ON CONFLICT (owner, currency, kind) WHERE status <> 'closed' DO UPDATE
+SET kind = EXCLUDED.kind
+RETURNING id, owner, balance, currency, created_at, kind, status, status_reason, status_changed_at, product_id, interest_carry
+`
+
+type UpsertSystemAccountParams struct {
+	Currency string `json:"currency"`
+	Kind     string `json:"kind"`
+}
+
+func (q *Queries) UpsertSystemAccount(ctx context.Context, arg UpsertSystemAccountParams) (Account, error) {
+	row := q.db.QueryRow(ctx, upsertSystemAccount, arg.Currency, arg.Kind)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Kind,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.ProductID,
+		&i.InterestCarry,
 	)
 	return i, err
 }
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"math/bits"
+)
+
+// InterestUnitsPerMinorUnit is the number of accrual units that make one minor unit of interest.
+// A day of interest is the balance times the annual rate in basis points, so that it is
+// exact in 1/(10000*365) of a minor unit and nothing is lost to rounding before posting.
+const InterestUnitsPerMinorUnit = 10000 * 365
+
+// transferFee computes the fee of the product on a transfer of amount, in minor units.
+// The percentage part is rounded half up. The schema caps the rate at 10000 basis points,
+// which keeps the 128-bit product of the amount and rate divisible without overflow.
+func transferFee(product AccountProduct, amount int64) int64 {
+	fee := product.TransferFeeFlat
+	if product.TransferFeeBps > 0 && amount > 0 {
+		hi, lo := bits.Mul64(uint64(amount), uint64(product.TransferFeeBps))
+		lo, carry := bits.Add64(lo, 5000, 0)
+		quo, _ := bits.Div64(hi+carry, lo, 10000)
+		fee += int64(quo)
+	}
+	return fee
+}
+
+// splitInterest splits the accrued interest and the carry left over from the last posting
+// into the whole minor units to pay out and the remainder to carry over to the next posting.
+func splitInterest(carry int64, accrued int64) (amount int64, remainder int64) {
+	units := carry + accrued
+	return units / InterestUnitsPerMinorUnit, units % InterestUnitsPerMinorUnit
+}
+
+// chargeTransferFee debits the fee of the source account's product on a posted transfer,
+// and credits it to the fees system account within the journal entry of the transfer.
+func chargeTransferFee(ctx context.Context, q *Queries, result *TransferTxResult) error {
+	if !result.FromAccount.ProductID.Valid {
+		return nil
+	}
+
+	product, err := q.GetAccountProduct(ctx, result.FromAccount.ProductID.Int64)
+	if err != nil {
+		return err
+	}
+
+	fee := transferFee(product, result.Transfer.Amount)
+	if fee == 0 {
+		return nil
+	}
+
+	result.FeeEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:      result.FromAccount.ID,
+		Amount:         -fee,
+		JournalEntryID: result.FromEntry.JournalEntryID,
+	})
+	if err != nil {
+		return err
+	}
+
+	result.FromAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     result.FromAccount.ID,
+		Amount: -fee,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = postToSystemAccounts(ctx, q, result.JournalEntry.ID,
+		systemPosting{Kind: AccountKindFees, Currency: result.FromAccount.Currency, Amount: fee},
+	)
+	if err != nil {
+		return err
+	}
+
+	result.Fee = fee
+	return nil
+}
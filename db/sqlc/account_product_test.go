@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/antimatter007/go-backend/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAccountProduct(t *testing.T, interestRateBps int32, transferFeeFlat int64, transferFeeBps int32) AccountProduct {
+	arg := CreateAccountProductParams{
+		Name:            util.RandomString(12),
+		InterestRateBps: interestRateBps,
+		TransferFeeFlat: transferFeeFlat,
+		TransferFeeBps:  transferFeeBps,
+	}
+
+	product, err := testStore.CreateAccountProduct(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, product.Name)
+	require.Equal(t, arg.InterestRateBps, product.InterestRateBps)
+	require.Equal(t, arg.TransferFeeFlat, product.TransferFeeFlat)
+	require.Equal(t, arg.TransferFeeBps, product.TransferFeeBps)
+	require.NotZero(t, product.CreatedAt)
+
+	return product
+}
+
+func createAccountWithProduct(t *testing.T, product AccountProduct, balance int64) Account {
+	user := createRandomUser(t)
+
+	account, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:     user.Username,
+		Balance:   balance,
+		Currency:  util.USD,
+		ProductID: pgtype.Int8{Int64: product.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, product.ID, account.ProductID.Int64)
+	require.Zero(t, account.InterestCarry)
+
+	return account
+}
+
+func TestGetAccountProductByName(t *testing.T) {
+	product1 := createRandomAccountProduct(t, 150, 0, 0)
+
+	product2, err := testStore.GetAccountProductByName(context.Background(), product1.Name)
+	require.NoError(t, err)
+	require.Equal(t, product1.ID, product2.ID)
+
+	_, err = testStore.CreateAccountProduct(context.Background(), CreateAccountProductParams{Name: product1.Name})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	_, err = testStore.GetAccountProductByName(context.Background(), util.RandomString(12))
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestTransferFee(t *testing.T) {
+	testCases := []struct {
+		name    string
+		product AccountProduct
+		amount  int64
+		fee     int64
+	}{
+		{name: "NoFee", product: AccountProduct{}, amount: 1000, fee: 0},
+		{name: "Flat", product: AccountProduct{TransferFeeFlat: 25}, amount: 1000, fee: 25},
+		{name: "Percentage", product: AccountProduct{TransferFeeBps: 150}, amount: 1000, fee: 15},
+		{name: "RoundDown", product: AccountProduct{TransferFeeBps: 10}, amount: 1499, fee: 1},
+		{name: "RoundHalfUp", product: AccountProduct{TransferFeeBps: 10}, amount: 1500, fee: 2},
+		{name: "FlatAndPercentage", product: AccountProduct{TransferFeeFlat: 30, TransferFeeBps: 290}, amount: 2000, fee: 88},
+		{name: "NoOverflow", product: AccountProduct{TransferFeeBps: 10000}, amount: math.MaxInt64, fee: math.MaxInt64},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.fee, transferFee(tc.product, tc.amount))
+		})
+	}
+}
+
+func TestSplitInterest(t *testing.T) {
+	// 100.00 at 2% earns less than a cent a day
+	amount, carry := splitInterest(0, 10000*200)
+	require.Zero(t, amount)
+	require.Equal(t, int64(10000*200), carry)
+
+	// a year of the same accruals pays exactly 2.00
+	var paid int64
+	carry = 0
+	for day := 0; day < 365; day++ {
+		var amount int64
+		amount, carry = splitInterest(carry, 10000*200)
+		paid += amount
+	}
+	require.Equal(t, int64(200), paid)
+	require.Zero(t, carry)
+}
+
+func TestTransferTxFee(t *testing.T) {
+	product := createRandomAccountProduct(t, 0, 30, 100)
+	account1 := createAccountWithProduct(t, product, 1000)
+	account2 := createAccountWithCurrency(t, util.USD)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        500,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(35), result.Fee)
+	require.Equal(t, int64(-35), result.FeeEntry.Amount)
+	require.Equal(t, result.JournalEntry.ID, result.FeeEntry.JournalEntryID.Int64)
+	require.Equal(t, int64(1000-500-35), result.FromAccount.Balance)
+
+	lines := requireJournalEntryBalanced(t, result.JournalEntry)
+	require.Len(t, lines, 4)
+
+	// the fee counts against the available balance
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        result.FromAccount.Balance,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// the fee follows the product of the source account only
+	result, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Fee)
+	require.Zero(t, result.FeeEntry.ID)
+}
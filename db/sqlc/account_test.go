@@ -25,6 +25,8 @@ func createRandomAccount(t *testing.T) Account {
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, AccountKindCustomer, account.Kind)
+	require.Equal(t, AccountActive, account.Status)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -32,6 +34,21 @@ func createRandomAccount(t *testing.T) Account {
 	return account
 }
 
+// createAccountWithCurrency creates an account in currency, so that transfers with other accounts
+// of the same currency balance in the journal
+func createAccountWithCurrency(t *testing.T, currency string) Account {
+	user := createRandomUser(t)
+
+	account, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  util.RandomMoney(),
+		Currency: currency,
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
 func TestCreateAccount(t *testing.T) {
 	createRandomAccount(t)
 }
@@ -10,6 +10,7 @@ import (
 const (
 	ForeignKeyViolation = "23503"
 	UniqueViolation     = "23505"
+	CheckViolation      = "23514"
 )
 
 var ErrRecordNotFound = pgx.ErrNoRows
@@ -2,7 +2,11 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/jackc/pgx/v5"
 )
 
 // ExecTx executes a function within a database transaction
@@ -16,8 +20,38 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	err = fn(q)
 	if err != nil {
 		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			requestid.Logger(ctx).Error().Err(rbErr).AnErr("tx_error", err).Msg("failed to roll back transaction")
 			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
 		}
+		requestid.Logger(ctx).Debug().Err(err).Msg("transaction rolled back")
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		requestid.Logger(ctx).Error().Err(err).Msg("failed to commit transaction")
+	}
+	return err
+}
+
+// execSavepoint executes a function within a savepoint of the transaction that q runs in,
+// so that a failure of fn only rolls back its own statements.
+func execSavepoint(ctx context.Context, q *Queries, fn func(*Queries) error) error {
+	parent, ok := q.db.(pgx.Tx)
+	if !ok {
+		return errors.New("savepoint requires a database transaction")
+	}
+
+	tx, err := parent.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(q.WithTx(tx))
+	if err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("savepoint err: %v, rb err: %v", err, rbErr)
+		}
 		return err
 	}
 
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SystemAccountOwner is the user that owns all system accounts
+const SystemAccountOwner = "system"
+
+// Constants for all kinds of accounts
+const (
+	AccountKindCustomer     = "customer"
+	AccountKindFees         = "fees"
+	AccountKindExternalCash = "external_cash"
+	AccountKindFx           = "fx"
+	AccountKindInterest     = "interest"
+)
+
+// Constants for all kinds of journal entries
+const (
+	JournalTransfer   = "transfer"
+	JournalConversion = "conversion"
+	JournalReversal   = "reversal"
+	JournalInterest   = "interest"
+)
+
+// systemPosting is the leg of a journal entry that goes to a system account
+type systemPosting struct {
+	Kind     string
+	Currency string
+	Amount   int64
+}
+
+// postToSystemAccounts adds the entries of a journal entry to system accounts and updates their balance.
+// System accounts are created on first use. They are always locked after the customer accounts
+// of the posting, and in currency order, so that concurrent postings can't deadlock.
+func postToSystemAccounts(ctx context.Context, q *Queries, journalEntryID int64, postings ...systemPosting) ([]Entry, error) {
+	sort.SliceStable(postings, func(i, j int) bool {
+		if postings[i].Currency != postings[j].Currency {
+			return postings[i].Currency < postings[j].Currency
+		}
+		return postings[i].Kind < postings[j].Kind
+	})
+
+	entries := make([]Entry, 0, len(postings))
+	for _, posting := range postings {
+		account, err := q.UpsertSystemAccount(ctx, UpsertSystemAccountParams{
+			Currency: posting.Currency,
+			Kind:     posting.Kind,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:      account.ID,
+			Amount:         posting.Amount,
+			JournalEntryID: pgtype.Int8{Int64: journalEntryID, Valid: true},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: posting.Amount,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// journalKind tells what kind of journal entry posts a transfer
+func journalKind(transfer Transfer) string {
+	switch {
+	case transfer.ReversedTransferID.Valid:
+		return JournalReversal
+	case transfer.ToAmount.Valid:
+		return JournalConversion
+	default:
+		return JournalTransfer
+	}
+}
+
+// Constants for all statuses of a reconciliation report
+const (
+	ReconciliationRunning   = "running"
+	ReconciliationCompleted = "completed"
+	ReconciliationFailed    = "failed"
+)
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antimatter007/go-backend/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// requireJournalEntryBalanced checks that the lines of a journal entry sum to zero in every currency
+func requireJournalEntryBalanced(t *testing.T, journalEntry JournalEntry) []Entry {
+	lines, err := testStore.ListJournalEntryLines(context.Background(), pgtype.Int8{Int64: journalEntry.ID, Valid: true})
+	require.NoError(t, err)
+	require.NotEmpty(t, lines)
+
+	sums := map[string]int64{}
+	for _, line := range lines {
+		account, err := testStore.GetAccount(context.Background(), line.AccountID)
+		require.NoError(t, err)
+		sums[account.Currency] += line.Amount
+	}
+	for currency, sum := range sums {
+		require.Zero(t, sum, currency)
+	}
+
+	return lines
+}
+
+func TestTransferJournalEntry(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	journalEntry, err := testStore.GetJournalEntry(context.Background(), result.JournalEntry.ID)
+	require.NoError(t, err)
+	require.Equal(t, JournalTransfer, journalEntry.Kind)
+	require.Equal(t, result.Transfer.ID, journalEntry.TransferID.Int64)
+
+	lines := requireJournalEntryBalanced(t, journalEntry)
+	require.Len(t, lines, 2)
+	require.Equal(t, result.FromEntry.ID, lines[0].ID)
+	require.Equal(t, result.ToEntry.ID, lines[1].ID)
+}
+
+func TestConversionJournalEntry(t *testing.T) {
+	account1 := createAccountWithCurrency(t, util.USD)
+	account2 := createAccountWithCurrency(t, util.EUR)
+	quote := createTestFxQuote(t, account1.Owner, util.USD, util.EUR, time.Minute)
+
+	fxUSD, err := testStore.UpsertSystemAccount(context.Background(), UpsertSystemAccountParams{Currency: util.USD, Kind: AccountKindFx})
+	require.NoError(t, err)
+	require.Equal(t, SystemAccountOwner, fxUSD.Owner)
+
+	result, err := testStore.ConvertTransferTx(context.Background(), ConvertTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		QuoteID:       quote.ID,
+		Owner:         account1.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, JournalConversion, result.JournalEntry.Kind)
+
+	// the fx accounts of both currencies take the other side of each leg
+	lines := requireJournalEntryBalanced(t, result.JournalEntry)
+	require.Len(t, lines, 4)
+
+	updatedFxUSD, err := testStore.GetAccount(context.Background(), fxUSD.ID)
+	require.NoError(t, err)
+	require.Equal(t, fxUSD.Balance+100, updatedFxUSD.Balance)
+}
+
+func TestUnbalancedJournalEntry(t *testing.T) {
+	account := createRandomAccount(t)
+	store := testStore.(*SQLStore)
+
+	err := store.execTx(context.Background(), func(q *Queries) error {
+		journalEntry, err := q.CreateJournalEntry(context.Background(), CreateJournalEntryParams{Kind: JournalTransfer})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID:      account.ID,
+			Amount:         10,
+			JournalEntryID: pgtype.Int8{Int64: journalEntry.ID, Valid: true},
+		})
+		return err
+	})
+	require.Error(t, err)
+	require.Equal(t, CheckViolation, ErrorCode(err))
+}
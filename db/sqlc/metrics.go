@@ -0,0 +1,116 @@
+package db
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Kinds of committed transfers, as recorded by the transfer metrics
+const (
+	transferMetricTransfer    = "transfer"
+	transferMetricBatch       = "batch"
+	transferMetricConversion  = "conversion"
+	transferMetricHoldCapture = "hold_capture"
+	transferMetricReversal    = "reversal"
+	transferMetricScheduled   = "scheduled"
+)
+
+var transfersCreated = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "transfers",
+		Name:      "created_total",
+		Help:      "Number of committed transfers, by kind and source currency.",
+	},
+	[]string{"kind", "currency"},
+)
+
+var transferVolume = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "transfers",
+		Name:      "volume_total",
+		Help:      "Amount moved by committed transfers, in minor units of the source currency, by kind and currency.",
+	},
+	[]string{"kind", "currency"},
+)
+
+// recordTransfer adds a committed transfer to the metrics.
+// It must only be called once the transaction has committed, as rolled back transfers never happened.
+// Currencies are bounded by the supported currencies of accounts.
+func recordTransfer(kind string, result TransferTxResult) {
+	currency := result.FromAccount.Currency
+	transfersCreated.WithLabelValues(kind, currency).Inc()
+	transferVolume.WithLabelValues(kind, currency).Add(float64(result.Transfer.Amount))
+}
+
+// poolStatsCollector exports the statistics of a pgx connection pool
+type poolStatsCollector struct {
+	connPool *pgxpool.Pool
+
+	acquiredConns       *prometheus.Desc
+	idleConns           *prometheus.Desc
+	constructingConns   *prometheus.Desc
+	totalConns          *prometheus.Desc
+	maxConns            *prometheus.Desc
+	acquires            *prometheus.Desc
+	acquireDuration     *prometheus.Desc
+	emptyAcquires       *prometheus.Desc
+	canceledAcquires    *prometheus.Desc
+	newConns            *prometheus.Desc
+	maxLifetimeDestroys *prometheus.Desc
+	maxIdleDestroys     *prometheus.Desc
+}
+
+// NewPoolStatsCollector creates a collector of the connection pool statistics,
+// to be registered once with the Prometheus registry.
+func NewPoolStatsCollector(connPool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("simple_bank", "db_pool", name), help, nil, nil)
+	}
+
+	return &poolStatsCollector{
+		connPool:            connPool,
+		acquiredConns:       desc("acquired_conns", "Number of connections currently in use."),
+		idleConns:           desc("idle_conns", "Number of idle connections in the pool."),
+		constructingConns:   desc("constructing_conns", "Number of connections being established."),
+		totalConns:          desc("total_conns", "Total number of connections in the pool."),
+		maxConns:            desc("max_conns", "Maximum size of the pool."),
+		acquires:            desc("acquires_total", "Number of successful connection acquires."),
+		acquireDuration:     desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+		emptyAcquires:       desc("empty_acquires_total", "Number of acquires that had to wait for a connection."),
+		canceledAcquires:    desc("canceled_acquires_total", "Number of acquires canceled by their context."),
+		newConns:            desc("new_conns_total", "Number of connections opened."),
+		maxLifetimeDestroys: desc("max_lifetime_destroys_total", "Number of connections closed for exceeding their maximum lifetime."),
+		maxIdleDestroys:     desc("max_idle_destroys_total", "Number of connections closed for exceeding their maximum idle time."),
+	}
+}
+
+func (collector *poolStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(collector, ch)
+}
+
+func (collector *poolStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := collector.connPool.Stat()
+
+	gauge := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value)
+	}
+	counter := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value)
+	}
+
+	gauge(collector.acquiredConns, float64(stat.AcquiredConns()))
+	gauge(collector.idleConns, float64(stat.IdleConns()))
+	gauge(collector.constructingConns, float64(stat.ConstructingConns()))
+	gauge(collector.totalConns, float64(stat.TotalConns()))
+	gauge(collector.maxConns, float64(stat.MaxConns()))
+	counter(collector.acquires, float64(stat.AcquireCount()))
+	counter(collector.acquireDuration, stat.AcquireDuration().Seconds())
+	counter(collector.emptyAcquires, float64(stat.EmptyAcquireCount()))
+	counter(collector.canceledAcquires, float64(stat.CanceledAcquireCount()))
+	counter(collector.newConns, float64(stat.NewConnsCount()))
+	counter(collector.maxLifetimeDestroys, float64(stat.MaxLifetimeDestroyCount()))
+	counter(collector.maxIdleDestroys, float64(stat.MaxIdleDestroyCount()))
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Account struct {
@@ -16,6 +17,27 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// customer, or the purpose of a system account: fees, external_cash, fx or interest
+	Kind string `json:"kind"`
+	// active, frozen or closed
+	Status          string             `json:"status"`
+	StatusReason    string             `json:"status_reason"`
+	StatusChangedAt pgtype.Timestamptz `json:"status_changed_at"`
+	ProductID       pgtype.Int8        `json:"product_id"`
+	// accrued interest left over from the last posting, in 1/3650000 of a minor unit
+	InterestCarry int64 `json:"interest_carry"`
+}
+
+type AccountProduct struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// annual interest rate in basis points, accrued daily on a 365-day year
+	InterestRateBps int32 `json:"interest_rate_bps"`
+	// fee charged on every outgoing transfer, in minor units of the account currency
+	TransferFeeFlat int64 `json:"transfer_fee_flat"`
+	// fee charged on every outgoing transfer, in basis points of its amount
+	TransferFeeBps int32     `json:"transfer_fee_bps"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type Entry struct {
@@ -24,6 +46,141 @@ type Entry struct {
 	// can be negative or positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// posting that the entry is a leg of, null for entries older than the journal
+	JournalEntryID pgtype.Int8 `json:"journal_entry_id"`
+}
+
+type FailedTask struct {
+	ID           int64              `json:"id"`
+	TaskID       string             `json:"task_id"`
+	Queue        string             `json:"queue"`
+	Type         string             `json:"type"`
+	Payload      []byte             `json:"payload"`
+	LastError    string             `json:"last_error"`
+	Retried      int32              `json:"retried"`
+	FailureCount int32              `json:"failure_count"`
+	Resolution   string             `json:"resolution"`
+	ResolvedBy   string             `json:"resolved_by"`
+	FailedAt     time.Time          `json:"failed_at"`
+	ResolvedAt   pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type FxQuote struct {
+	ID           uuid.UUID `json:"id"`
+	Owner        string    `json:"owner"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	// mid-market rate, fixed point with 8 decimals
+	Rate      int64              `json:"rate"`
+	SpreadBps int32              `json:"spread_bps"`
+	Source    string             `json:"source"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type Hold struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	// must be positive
+	Amount         int64 `json:"amount"`
+	CapturedAmount int64 `json:"captured_amount"`
+	// active, captured, voided or expired
+	Status     string      `json:"status"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+type InterestAccrual struct {
+	ID              int64       `json:"id"`
+	AccountID       int64       `json:"account_id"`
+	AccrualDate     pgtype.Date `json:"accrual_date"`
+	Balance         int64       `json:"balance"`
+	InterestRateBps int32       `json:"interest_rate_bps"`
+	// interest of the day in 1/3650000 of a minor unit: balance times interest_rate_bps
+	Accrued int64 `json:"accrued"`
+	// posting that paid the interest out, null until posted or when it rounded to zero
+	JournalEntryID pgtype.Int8        `json:"journal_entry_id"`
+	PostedAt       pgtype.Timestamptz `json:"posted_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type JournalEntry struct {
+	ID         int64       `json:"id"`
+	Kind       string      `json:"kind"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type Notification struct {
+	ID        int64              `json:"id"`
+	Username  string             `json:"username"`
+	Event     string             `json:"event"`
+	Title     string             `json:"title"`
+	Content   string             `json:"content"`
+	IsRead    bool               `json:"is_read"`
+	CreatedAt time.Time          `json:"created_at"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+}
+
+type NotificationPreference struct {
+	Username  string    `json:"username"`
+	Event     string    `json:"event"`
+	Channel   string    `json:"channel"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ReconciliationDiscrepancy struct {
+	ID        int64 `json:"id"`
+	ReportID  int64 `json:"report_id"`
+	AccountID int64 `json:"account_id"`
+	// accounts.balance when the account was checked
+	StoredBalance int64 `json:"stored_balance"`
+	// sum of the entries of the account when it was checked
+	LedgerBalance int64     `json:"ledger_balance"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ReconciliationReport struct {
+	ID int64 `json:"id"`
+	// running, completed or failed
+	Status           string             `json:"status"`
+	AccountsChecked  int64              `json:"accounts_checked"`
+	DiscrepancyCount int64              `json:"discrepancy_count"`
+	Error            string             `json:"error"`
+	StartedAt        time.Time          `json:"started_at"`
+	FinishedAt       pgtype.Timestamptz `json:"finished_at"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	// once, daily, weekly or monthly
+	Recurrence string    `json:"recurrence"`
+	Status     string    `json:"status"`
+	FirstRunAt time.Time `json:"first_run_at"`
+	// null once there are no runs left
+	NextRunAt pgtype.Timestamptz `json:"next_run_at"`
+	EndAt     pgtype.Timestamptz `json:"end_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64       `json:"id"`
+	ScheduledTransferID int64       `json:"scheduled_transfer_id"`
+	ScheduledFor        time.Time   `json:"scheduled_for"`
+	Status              string      `json:"status"`
+	TransferID          pgtype.Int8 `json:"transfer_id"`
+	Error               string      `json:"error"`
+	CreatedAt           time.Time   `json:"created_at"`
 }
 
 type Session struct {
@@ -44,6 +201,28 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// amount credited in the currency of the destination account, null when both accounts share a currency
+	ToAmount pgtype.Int8 `json:"to_amount"`
+	// mid-market rate, fixed point with 8 decimals
+	ExchangeRate pgtype.Int8 `json:"exchange_rate"`
+	SpreadBps    pgtype.Int4 `json:"spread_bps"`
+	// transfer that this one reverses, in full or in part
+	ReversedTransferID pgtype.Int8 `json:"reversed_transfer_id"`
+}
+
+// limits on outgoing transfers in minor units, a null limit is unlimited on a default and inherits the default on an override
+type TransferLimit struct {
+	ID int64 `json:"id"`
+	// user that the limits override the defaults for, null for the defaults of the currency
+	Username          pgtype.Text `json:"username"`
+	Currency          string      `json:"currency"`
+	MaxPerTransfer    pgtype.Int8 `json:"max_per_transfer"`
+	DailyPerAccount   pgtype.Int8 `json:"daily_per_account"`
+	MonthlyPerAccount pgtype.Int8 `json:"monthly_per_account"`
+	DailyPerUser      pgtype.Int8 `json:"daily_per_user"`
+	MonthlyPerUser    pgtype.Int8 `json:"monthly_per_user"`
+	UpdatedBy         string      `json:"updated_by"`
+	UpdatedAt         time.Time   `json:"updated_at"`
 }
 
 type User struct {
@@ -54,6 +233,7 @@ type User struct {
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
 	IsEmailVerified   bool      `json:"is_email_verified"`
+	Role              string    `json:"role"`
 }
 
 type VerifyEmail struct {
@@ -65,3 +245,27 @@ type VerifyEmail struct {
 	CreatedAt  time.Time `json:"created_at"`
 	ExpiredAt  time.Time `json:"expired_at"`
 }
+
+type WebhookDelivery struct {
+	ID             int64              `json:"id"`
+	EndpointID     int64              `json:"endpoint_id"`
+	Event          string             `json:"event"`
+	Payload        []byte             `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	ResponseStatus int32              `json:"response_status"`
+	LastError      string             `json:"last_error"`
+	CreatedAt      time.Time          `json:"created_at"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+	EventID        string             `json:"event_id"`
+}
+
+type WebhookEndpoint struct {
+	ID        int64     `json:"id"`
+	Owner     string    `json:"owner"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -8,29 +8,93 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AccrueInterest(ctx context.Context, arg AccrueInterestParams) (AccrueInterestRow, error)
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountProduct(ctx context.Context, arg CreateAccountProductParams) (AccountProduct, error)
+	CreateConvertedTransfer(ctx context.Context, arg CreateConvertedTransferParams) (Transfer, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateFxQuote(ctx context.Context, arg CreateFxQuoteParams) (FxQuote, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreateReconciliationDiscrepancy(ctx context.Context, arg CreateReconciliationDiscrepancyParams) (ReconciliationDiscrepancy, error)
+	CreateReconciliationReport(ctx context.Context) (ReconciliationReport, error)
+	CreateReversalTransfer(ctx context.Context, arg CreateReversalTransferParams) (Transfer, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteExpiredSessions(ctx context.Context, limit int32) (int64, error)
+	DeleteExpiredVerifyEmails(ctx context.Context, limit int32) (int64, error)
+	DeleteWebhookEndpoint(ctx context.Context, id int64) error
+	ExpireHolds(ctx context.Context, limit int32) (int64, error)
+	FinishReconciliationReport(ctx context.Context, arg FinishReconciliationReportParams) (ReconciliationReport, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountProduct(ctx context.Context, id int64) (AccountProduct, error)
+	GetAccountProductByName(ctx context.Context, name string) (AccountProduct, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetFxQuoteForUpdate(ctx context.Context, id uuid.UUID) (FxQuote, error)
+	GetHeldAmount(ctx context.Context, fromAccountID int64) (int64, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetJournalEntry(ctx context.Context, id int64) (JournalEntry, error)
+	GetLatestReconciliationReport(ctx context.Context) (ReconciliationReport, error)
+	GetOutgoingTransferTotals(ctx context.Context, arg GetOutgoingTransferTotalsParams) (GetOutgoingTransferTotalsRow, error)
+	GetReversedAmount(ctx context.Context, reversedTransferID pgtype.Int8) (int64, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
+	ListAccountLedgerBalances(ctx context.Context, arg ListAccountLedgerBalancesParams) ([]ListAccountLedgerBalancesRow, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListDueScheduledTransfers(ctx context.Context, limit int32) ([]ScheduledTransfer, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListInterestAccruals(ctx context.Context, arg ListInterestAccrualsParams) ([]InterestAccrual, error)
+	ListJournalEntryLines(ctx context.Context, journalEntryID pgtype.Int8) ([]Entry, error)
+	ListNotificationPreferences(ctx context.Context, username string) ([]NotificationPreference, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListReconciliationDiscrepancies(ctx context.Context, arg ListReconciliationDiscrepanciesParams) ([]ReconciliationDiscrepancy, error)
+	ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ListTransferLimits(ctx context.Context, arg ListTransferLimitsParams) ([]TransferLimit, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListUnpostedInterestAccounts(ctx context.Context, arg ListUnpostedInterestAccountsParams) ([]int64, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookEndpoints(ctx context.Context, owner string) ([]WebhookEndpoint, error)
+	ListWebhookEndpointsForEvent(ctx context.Context, arg ListWebhookEndpointsForEventParams) ([]WebhookEndpoint, error)
+	MarkFxQuoteUsed(ctx context.Context, id uuid.UUID) (FxQuote, error)
+	MarkInterestPosted(ctx context.Context, arg MarkInterestPostedParams) (int64, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
+	ResolveFailedTask(ctx context.Context, arg ResolveFailedTaskParams) (int64, error)
+	SumUnpostedInterest(ctx context.Context, arg SumUnpostedInterestParams) (int64, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountInterestCarry(ctx context.Context, arg UpdateAccountInterestCarryParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateHold(ctx context.Context, arg UpdateHoldParams) (Hold, error)
+	UpdateScheduledTransferSchedule(ctx context.Context, arg UpdateScheduledTransferScheduleParams) (ScheduledTransfer, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmail, error)
+	UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) (WebhookDelivery, error)
+	UpsertFailedTask(ctx context.Context, arg UpsertFailedTaskParams) (FailedTask, error)
+	UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error)
+	UpsertSystemAccount(ctx context.Context, arg UpsertSystemAccountParams) (Account, error)
+	UpsertTransferLimits(ctx context.Context, arg UpsertTransferLimitsParams) (TransferLimit, error)
+	VoidHold(ctx context.Context, id int64) (Hold, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccountLedgerBalances(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	balances, err := testStore.ListAccountLedgerBalances(context.Background(), ListAccountLedgerBalancesParams{
+		AfterID: account1.ID - 1,
+		Limit:   1,
+	})
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+
+	// the opening balance of a test account has no entry
+	require.Equal(t, account1.ID, balances[0].ID)
+	require.Equal(t, account1.Balance-10, balances[0].Balance)
+	require.Equal(t, int64(-10), balances[0].LedgerBalance)
+}
+
+func TestReconciliationReport(t *testing.T) {
+	account := createRandomAccount(t)
+
+	report, err := testStore.CreateReconciliationReport(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, ReconciliationRunning, report.Status)
+	require.False(t, report.FinishedAt.Valid)
+
+	discrepancy, err := testStore.CreateReconciliationDiscrepancy(context.Background(), CreateReconciliationDiscrepancyParams{
+		ReportID:      report.ID,
+		AccountID:     account.ID,
+		StoredBalance: account.Balance,
+	})
+	require.NoError(t, err)
+
+	report, err = testStore.FinishReconciliationReport(context.Background(), FinishReconciliationReportParams{
+		ID:               report.ID,
+		Status:           ReconciliationCompleted,
+		AccountsChecked:  1,
+		DiscrepancyCount: 1,
+	})
+	require.NoError(t, err)
+	require.True(t, report.FinishedAt.Valid)
+
+	latest, err := testStore.GetLatestReconciliationReport(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, report.ID, latest.ID)
+	require.Equal(t, ReconciliationCompleted, latest.Status)
+
+	discrepancies, err := testStore.ListReconciliationDiscrepancies(context.Background(), ListReconciliationDiscrepanciesParams{
+		ReportID: report.ID,
+		Limit:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, discrepancies, 1)
+	require.Equal(t, discrepancy, discrepancies[0])
+}
@@ -10,7 +10,7 @@ import (
 
 func TestTransferTx(t *testing.T) {
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	n := 5
@@ -115,7 +115,7 @@ func TestTransferTx(t *testing.T) {
 
 func TestTransferTxDeadlock(t *testing.T) {
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	n := 10
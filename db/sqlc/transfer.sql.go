@@ -7,8 +7,97 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createConvertedTransfer = `-- name: CreateConvertedTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  to_amount,
+  exchange_rate,
+  spread_bps
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversed_transfer_id
+`
+
+type CreateConvertedTransferParams struct {
+	FromAccountID int64       `json:"from_account_id"`
+	ToAccountID   int64       `json:"to_account_id"`
+	Amount        int64       `json:"amount"`
+	ToAmount      pgtype.Int8 `json:"to_amount"`
+	ExchangeRate  pgtype.Int8 `json:"exchange_rate"`
+	SpreadBps     pgtype.Int4 `json:"spread_bps"`
+}
+
+func (q *Queries) CreateConvertedTransfer(ctx context.Context, arg CreateConvertedTransferParams) (Transfer, error) {
+	row := q.db.QueryRow(ctx, createConvertedTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.SpreadBps,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.SpreadBps,
+		&i.ReversedTransferID,
+	)
+	return i, err
+}
+
+const createReversalTransfer = `-- name: CreateReversalTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  reversed_transfer_id
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversed_transfer_id
+`
+
+type CreateReversalTransferParams struct {
+	FromAccountID      int64       `json:"from_account_id"`
+	ToAccountID        int64       `json:"to_account_id"`
+	Amount             int64       `json:"amount"`
+	ReversedTransferID pgtype.Int8 `json:"reversed_transfer_id"`
+}
+
+func (q *Queries) CreateReversalTransfer(ctx context.Context, arg CreateReversalTransferParams) (Transfer, error) {
+	row := q.db.QueryRow(ctx, createReversalTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ReversedTransferID,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.SpreadBps,
+		&i.ReversedTransferID,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
@@ -16,7 +105,7 @@ INSERT INTO transfers (
   amount
 ) VALUES (
   $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+) RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversed_transfer_id
 `
 
 type CreateTransferParams struct {
@@ -34,12 +123,76 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.SpreadBps,
+		&i.ReversedTransferID,
+	)
+	return i, err
+}
+
+const getOutgoingTransferTotals = `-- name: GetOutgoingTransferTotals :one
+SELECT
+  COALESCE(SUM(t.amount) FILTER (WHERE t.from_account_id = $1 AND t.created_at >= $2), 0)::bigint AS account_daily,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.from_account_id = $1), 0)::bigint AS account_monthly,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.created_at >= $2), 0)::bigint AS user_daily,
+  COALESCE(SUM(t.amount), 0)::bigint AS user_monthly
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE a.owner = $3
+  AND a.currency = $4
+  AND t.reversed_transfer_id IS NULL
+  AND t.created_at >= $5
+`
+
+type GetOutgoingTransferTotalsParams struct {
+	AccountID  int64     `json:"account_id"`
+	DayStart   time.Time `json:"day_start"`
+	Owner      string    `json:"owner"`
+	Currency   string    `json:"currency"`
+	MonthStart time.Time `json:"month_start"`
+}
+
+type GetOutgoingTransferTotalsRow struct {
+	AccountDaily   int64 `json:"account_daily"`
+	AccountMonthly int64 `json:"account_monthly"`
+	UserDaily      int64 `json:"user_daily"`
+	UserMonthly    int64 `json:"user_monthly"`
+}
+
+func (q *Queries) GetOutgoingTransferTotals(ctx context.Context, arg GetOutgoingTransferTotalsParams) (GetOutgoingTransferTotalsRow, error) {
+	row := q.db.QueryRow(ctx, getOutgoingTransferTotals,
+		arg.AccountID,
+		arg.DayStart,
+		arg.Owner,
+		arg.Currency,
+		arg.MonthStart,
+	)
+	var i GetOutgoingTransferTotalsRow
+	err := row.Scan(
+		&i.AccountDaily,
+		&i.AccountMonthly,
+		&i.UserDaily,
+		&i.UserMonthly,
 	)
 	return i, err
 }
 
+const getReversedAmount = `-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS reversed_amount
+FROM transfers
+WHERE reversed_transfer_id = $1
+`
+
+func (q *Queries) GetReversedAmount(ctx context.Context, reversedTransferID pgtype.Int8) (int64, error) {
+	row := q.db.QueryRow(ctx, getReversedAmount, reversedTransferID)
+	var reversed_amount int64
+	err := row.Scan(&reversed_amount)
+	return reversed_amount, err
+}
+
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversed_transfer_id FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -52,12 +205,39 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.SpreadBps,
+		&i.ReversedTransferID,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversed_transfer_id FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.SpreadBps,
+		&i.ReversedTransferID,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversed_transfer_id FROM transfers
 WHERE 
     from_account_id = $1 OR
     to_account_id = $2
@@ -93,6 +273,10 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.SpreadBps,
+			&i.ReversedTransferID,
 		); err != nil {
 			return nil, err
 		}
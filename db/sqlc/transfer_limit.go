@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrTransferLimitExceeded is returned when a transfer goes over one of the transfer limits of its source account
+var ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+// TransferLimitWindow is the outgoing total of a period against its limit.
+// Limit and Remaining are null when the period is unlimited.
+type TransferLimitWindow struct {
+	Limit     pgtype.Int8 `json:"limit"`
+	Used      int64       `json:"used"`
+	Remaining pgtype.Int8 `json:"remaining"`
+}
+
+func newTransferLimitWindow(limit pgtype.Int8, used int64) TransferLimitWindow {
+	window := TransferLimitWindow{
+		Limit: limit,
+		Used:  used,
+	}
+	if limit.Valid {
+		window.Remaining = pgtype.Int8{Int64: max(limit.Int64-used, 0), Valid: true}
+	}
+	return window
+}
+
+// TransferAllowance tells how much an account may still send in the current UTC day and month.
+// Periods per user add up the outgoing transfers of all accounts of the owner in the same currency,
+// so that closing an account and opening a new one doesn't reset them. Reversals don't count against any limit.
+type TransferAllowance struct {
+	Currency          string              `json:"currency"`
+	MaxPerTransfer    pgtype.Int8         `json:"max_per_transfer"`
+	DailyPerAccount   TransferLimitWindow `json:"daily_per_account"`
+	MonthlyPerAccount TransferLimitWindow `json:"monthly_per_account"`
+	DailyPerUser      TransferLimitWindow `json:"daily_per_user"`
+	MonthlyPerUser    TransferLimitWindow `json:"monthly_per_user"`
+	// Remaining is the largest transfer that the account may make now, null when unlimited
+	Remaining pgtype.Int8 `json:"remaining"`
+}
+
+// namedWindow is a period of an allowance with its name for error messages
+type namedWindow struct {
+	name   string
+	window TransferLimitWindow
+}
+
+func (allowance TransferAllowance) windows() []namedWindow {
+	return []namedWindow{
+		{"daily account", allowance.DailyPerAccount},
+		{"monthly account", allowance.MonthlyPerAccount},
+		{"daily user", allowance.DailyPerUser},
+		{"monthly user", allowance.MonthlyPerUser},
+	}
+}
+
+// effectiveTransferLimits merges the default limits of a currency with the override of a user.
+// A limit set on the override replaces the default one.
+func effectiveTransferLimits(limits []TransferLimit) TransferLimit {
+	var effective TransferLimit
+	for _, limit := range limits {
+		effective.MaxPerTransfer = overrideLimit(effective.MaxPerTransfer, limit.MaxPerTransfer)
+		effective.DailyPerAccount = overrideLimit(effective.DailyPerAccount, limit.DailyPerAccount)
+		effective.MonthlyPerAccount = overrideLimit(effective.MonthlyPerAccount, limit.MonthlyPerAccount)
+		effective.DailyPerUser = overrideLimit(effective.DailyPerUser, limit.DailyPerUser)
+		effective.MonthlyPerUser = overrideLimit(effective.MonthlyPerUser, limit.MonthlyPerUser)
+	}
+	return effective
+}
+
+func overrideLimit(limit pgtype.Int8, override pgtype.Int8) pgtype.Int8 {
+	if override.Valid {
+		return override
+	}
+	return limit
+}
+
+// transferAllowance computes the allowance of an account for the day and month of now
+func transferAllowance(ctx context.Context, q *Queries, account Account, now time.Time) (TransferAllowance, error) {
+	limits, err := q.ListTransferLimits(ctx, ListTransferLimitsParams{
+		Currency: account.Currency,
+		Username: account.Owner,
+	})
+	if err != nil {
+		return TransferAllowance{}, err
+	}
+	limit := effectiveTransferLimits(limits)
+
+	now = now.UTC()
+	totals, err := q.GetOutgoingTransferTotals(ctx, GetOutgoingTransferTotalsParams{
+		AccountID:  account.ID,
+		DayStart:   time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		Owner:      account.Owner,
+		Currency:   account.Currency,
+		MonthStart: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return TransferAllowance{}, err
+	}
+
+	allowance := TransferAllowance{
+		Currency:          account.Currency,
+		MaxPerTransfer:    limit.MaxPerTransfer,
+		DailyPerAccount:   newTransferLimitWindow(limit.DailyPerAccount, totals.AccountDaily),
+		MonthlyPerAccount: newTransferLimitWindow(limit.MonthlyPerAccount, totals.AccountMonthly),
+		DailyPerUser:      newTransferLimitWindow(limit.DailyPerUser, totals.UserDaily),
+		MonthlyPerUser:    newTransferLimitWindow(limit.MonthlyPerUser, totals.UserMonthly),
+		Remaining:         limit.MaxPerTransfer,
+	}
+	for _, w := range allowance.windows() {
+		if w.window.Remaining.Valid && (!allowance.Remaining.Valid || w.window.Remaining.Int64 < allowance.Remaining.Int64) {
+			allowance.Remaining = w.window.Remaining
+		}
+	}
+
+	return allowance, nil
+}
+
+// GetTransferAllowance returns how much the account may still send today and this month
+func (store *SQLStore) GetTransferAllowance(ctx context.Context, account Account) (TransferAllowance, error) {
+	return transferAllowance(ctx, store.Queries, account, time.Now())
+}
+
+// lockTransferLimitOwners locks the owners of the source accounts whose transfers count against limits, in username order.
+// Owners are locked before the accounts, so that concurrent transfers from any of their accounts are counted one after
+// the other, and transactions locking both always take them in the same order.
+// Unknown accounts are skipped, the transfer itself fails on them.
+func lockTransferLimitOwners(ctx context.Context, q *Queries, accountIDs ...int64) error {
+	seen := map[string]bool{}
+	var usernames []string
+	for _, id := range accountIDs {
+		account, err := q.GetAccount(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+
+		if account.Kind == AccountKindCustomer && !seen[account.Owner] {
+			seen[account.Owner] = true
+			usernames = append(usernames, account.Owner)
+		}
+	}
+
+	sort.Strings(usernames)
+
+	for _, username := range usernames {
+		if _, err := q.GetUserForUpdate(ctx, username); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTransferLimits makes sure that a created transfer keeps its source account within its limits.
+// It relies on the owner being locked by lockTransferLimitOwners, the totals are read after the lock
+// and include the transfer itself.
+func checkTransferLimits(ctx context.Context, q *Queries, transfer Transfer, account Account) error {
+	if account.Kind != AccountKindCustomer {
+		return nil
+	}
+
+	allowance, err := transferAllowance(ctx, q, account, transfer.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if allowance.MaxPerTransfer.Valid && transfer.Amount > allowance.MaxPerTransfer.Int64 {
+		return fmt.Errorf("%w: amount is over the maximum of %d %s per transfer",
+			ErrTransferLimitExceeded, allowance.MaxPerTransfer.Int64, account.Currency)
+	}
+
+	for _, w := range allowance.windows() {
+		if w.window.Limit.Valid && w.window.Used > w.window.Limit.Int64 {
+			return fmt.Errorf("%w: %s limit of %d %s",
+				ErrTransferLimitExceeded, w.name, w.window.Limit.Int64, account.Currency)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antimatter007/go-backend/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveTransferLimits(t *testing.T) {
+	defaults := TransferLimit{
+		MaxPerTransfer:  pgtype.Int8{Int64: 1000, Valid: true},
+		DailyPerAccount: pgtype.Int8{Int64: 5000, Valid: true},
+	}
+	override := TransferLimit{
+		Username:       pgtype.Text{String: util.RandomOwner(), Valid: true},
+		MaxPerTransfer: pgtype.Int8{Int64: 200, Valid: true},
+		DailyPerUser:   pgtype.Int8{Int64: 300, Valid: true},
+	}
+
+	limits := effectiveTransferLimits([]TransferLimit{defaults, override})
+	require.Equal(t, int64(200), limits.MaxPerTransfer.Int64)
+	require.Equal(t, int64(5000), limits.DailyPerAccount.Int64)
+	require.Equal(t, int64(300), limits.DailyPerUser.Int64)
+	require.False(t, limits.MonthlyPerAccount.Valid)
+
+	// a currency without defaults or override is unlimited
+	require.Equal(t, TransferLimit{}, effectiveTransferLimits(nil))
+}
+
+func TestTransferTxLimits(t *testing.T) {
+	account1 := createAccountWithCurrency(t, util.USD)
+	account2 := createAccountWithCurrency(t, util.USD)
+
+	_, err := testStore.UpsertTransferLimits(context.Background(), UpsertTransferLimitsParams{
+		Username:        pgtype.Text{String: account1.Owner, Valid: true},
+		Currency:        util.USD,
+		MaxPerTransfer:  pgtype.Int8{Int64: 60, Valid: true},
+		DailyPerAccount: pgtype.Int8{Int64: 100, Valid: true},
+		UpdatedBy:       "banker",
+	})
+	require.NoError(t, err)
+
+	transfer := func(amount int64) error {
+		_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+
+	require.ErrorIs(t, transfer(61), ErrTransferLimitExceeded)
+	require.NoError(t, transfer(60))
+	require.ErrorIs(t, transfer(41), ErrTransferLimitExceeded)
+	require.NoError(t, transfer(40))
+
+	allowance, err := testStore.GetTransferAllowance(context.Background(), account1)
+	require.NoError(t, err)
+	require.Equal(t, int64(60), allowance.MaxPerTransfer.Int64)
+	require.Equal(t, int64(100), allowance.DailyPerAccount.Used)
+	require.Zero(t, allowance.DailyPerAccount.Remaining.Int64)
+	require.Zero(t, allowance.Remaining.Int64)
+	// the monthly user limit keeps the default of the currency
+	require.True(t, allowance.MonthlyPerUser.Limit.Valid)
+	require.Equal(t, int64(100), allowance.MonthlyPerUser.Used)
+
+	// the override of one user doesn't apply to another
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        61,
+	})
+	require.NoError(t, err)
+}
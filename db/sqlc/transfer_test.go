@@ -32,13 +32,13 @@ func createRandomTransfer(t *testing.T, account1, account2 Account) Transfer {
 
 func TestCreateTransfer(t *testing.T) {
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
 	createRandomTransfer(t, account1, account2)
 }
 
 func TestGetTransfer(t *testing.T) {
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
 	transfer1 := createRandomTransfer(t, account1, account2)
 
 	transfer2, err := testStore.GetTransfer(context.Background(), transfer1.ID)
@@ -54,7 +54,7 @@ func TestGetTransfer(t *testing.T) {
 
 func TestListTransfer(t *testing.T) {
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
 
 	for i := 0; i < 5; i++ {
 		createRandomTransfer(t, account1, account2)
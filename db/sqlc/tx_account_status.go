@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Constants for all statuses of an account
+const (
+	AccountActive = "active"
+	AccountFrozen = "frozen"
+	AccountClosed = "closed"
+)
+
+var (
+	ErrAccountFrozen              = errors.New("account is frozen")
+	ErrAccountClosed              = errors.New("account is closed")
+	ErrInvalidAccountStatusChange = errors.New("invalid account status change")
+	ErrAccountBalanceNotZero      = errors.New("account balance must be zero to close it")
+)
+
+// accountStatusChanges lists the statuses that an account may move to from each status.
+// A closed account stays closed.
+var accountStatusChanges = map[string][]string{
+	AccountActive: {AccountFrozen, AccountClosed},
+	AccountFrozen: {AccountActive},
+}
+
+// ChangeAccountStatusTxParams contains the input parameters of the change account status transaction
+type ChangeAccountStatusTxParams struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// ChangeAccountStatusTx freezes, unfreezes or closes an account.
+// The account is locked, so no transfer can move money in or out of it while the change is checked.
+// Only an active account with a zero balance can be closed.
+func (store *SQLStore) ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParams) (Account, error) {
+	var account Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		account, err = q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(accountStatusChanges[account.Status], arg.Status) {
+			return fmt.Errorf("%w: account is %s", ErrInvalidAccountStatusChange, account.Status)
+		}
+
+		if arg.Status == AccountClosed && account.Balance != 0 {
+			return ErrAccountBalanceNotZero
+		}
+
+		account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:           arg.ID,
+			Status:       arg.Status,
+			StatusReason: arg.Reason,
+		})
+		return err
+	})
+
+	return account, err
+}
+
+// checkAccountActive returns ErrAccountFrozen or ErrAccountClosed when money can't move in or out of the account
+func checkAccountActive(account Account) error {
+	switch account.Status {
+	case AccountFrozen:
+		return ErrAccountFrozen
+	case AccountClosed:
+		return ErrAccountClosed
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeAccount(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	frozen, err := testStore.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		ID:     account1.ID,
+		Status: AccountFrozen,
+		Reason: "suspicious activity",
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountFrozen, frozen.Status)
+	require.Equal(t, "suspicious activity", frozen.StatusReason)
+	require.True(t, frozen.StatusChangedAt.Valid)
+
+	// no debit
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// no credit
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// no hold
+	_, err = testStore.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// a frozen account can't be closed by its owner
+	_, err = testStore.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		ID:     account1.ID,
+		Status: AccountClosed,
+	})
+	require.ErrorIs(t, err, ErrInvalidAccountStatusChange)
+
+	active, err := testStore.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		ID:     account1.ID,
+		Status: AccountActive,
+		Reason: "cleared",
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountActive, active.Status)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+}
+
+func TestCloseAccount(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	_, err := testStore.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		ID:     account1.ID,
+		Status: AccountClosed,
+	})
+	require.ErrorIs(t, err, ErrAccountBalanceNotZero)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance,
+	})
+	require.NoError(t, err)
+
+	closed, err := testStore.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		ID:     account1.ID,
+		Status: AccountClosed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountClosed, closed.Status)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// closing is final
+	_, err = testStore.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		ID:     account1.ID,
+		Status: AccountActive,
+	})
+	require.ErrorIs(t, err, ErrInvalidAccountStatusChange)
+
+	// the owner may open a new account in the same currency
+	_, err = testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account1.Owner,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+}
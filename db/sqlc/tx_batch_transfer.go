@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// BatchTransferLeg is one transfer of a batch
+type BatchTransferLeg struct {
+	ToAccountID int64 `json:"to_account_id"`
+	Amount      int64 `json:"amount"`
+}
+
+// BatchTransferTxParams contains the input parameters of the batch transfer transaction
+type BatchTransferTxParams struct {
+	FromAccountID int64              `json:"from_account_id"`
+	Legs          []BatchTransferLeg `json:"legs"`
+	// BestEffort commits the legs that succeed instead of failing the whole batch on the first error
+	BestEffort bool `json:"best_effort"`
+}
+
+// BatchTransferLegResult is the outcome of one leg of a batch
+type BatchTransferLegResult struct {
+	Result TransferTxResult `json:"result"`
+	// Err is set when the leg failed and nothing of it was committed
+	Err error `json:"-"`
+}
+
+// BatchTransferTxResult is the result of the batch transfer transaction, with one entry per leg in request order
+type BatchTransferTxResult struct {
+	Legs []BatchTransferLegResult `json:"legs"`
+}
+
+// BatchLegError reports the leg that failed an all-or-nothing batch
+type BatchLegError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchLegError) Error() string {
+	return fmt.Sprintf("leg %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchLegError) Unwrap() error {
+	return e.Err
+}
+
+// BatchTransferTx performs many transfers from one account within a single database transaction.
+// The owner of the source account and all accounts of the batch are locked up front, as single transfers lock them,
+// so that concurrent batches and transfers touching the same accounts can't deadlock.
+// By default the first failing leg rolls back the whole batch and is returned as a *BatchLegError.
+// In best effort mode every leg runs in its own savepoint, and a rejected leg is only recorded in its result.
+// Other errors, such as a lost connection, still fail the whole batch.
+func (store *SQLStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error) {
+	var result BatchTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result.Legs = make([]BatchTransferLegResult, len(arg.Legs))
+
+		err := lockBatchAccounts(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		for i, leg := range arg.Legs {
+			legArg := TransferTxParams{
+				FromAccountID: arg.FromAccountID,
+				ToAccountID:   leg.ToAccountID,
+				Amount:        leg.Amount,
+			}
+
+			if !arg.BestEffort {
+				result.Legs[i].Result, err = transferTx(ctx, q, legArg)
+				if err != nil {
+					return &BatchLegError{Index: i, Err: err}
+				}
+				continue
+			}
+
+			err = execSavepoint(ctx, q, func(q *Queries) error {
+				var err error
+				result.Legs[i].Result, err = transferTx(ctx, q, legArg)
+				return err
+			})
+			if err != nil {
+				if !isTransferRejected(err) {
+					return &BatchLegError{Index: i, Err: err}
+				}
+				result.Legs[i] = BatchTransferLegResult{Err: err}
+			}
+		}
+
+		return nil
+	})
+	if err == nil {
+		for _, leg := range result.Legs {
+			if leg.Err == nil {
+				recordTransfer(transferMetricBatch, leg.Result)
+			}
+		}
+	}
+
+	return result, err
+}
+
+// lockBatchAccounts locks the owner of the source account, whose limits every leg counts against,
+// then the source and all destination accounts of a batch in account id order
+func lockBatchAccounts(ctx context.Context, q *Queries, arg BatchTransferTxParams) error {
+	err := lockTransferLimitOwners(ctx, q, arg.FromAccountID)
+	if err != nil {
+		return err
+	}
+
+	seen := map[int64]bool{arg.FromAccountID: true}
+	accountIDs := []int64{arg.FromAccountID}
+	for _, leg := range arg.Legs {
+		if !seen[leg.ToAccountID] {
+			seen[leg.ToAccountID] = true
+			accountIDs = append(accountIDs, leg.ToAccountID)
+		}
+	}
+
+	sort.Slice(accountIDs, func(i, j int) bool { return accountIDs[i] < accountIDs[j] })
+
+	for _, id := range accountIDs {
+		if _, err := q.GetAccountForUpdate(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransferTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+	account3 := createAccountWithCurrency(t, account1.Currency)
+
+	result, err := testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		FromAccountID: account1.ID,
+		Legs: []BatchTransferLeg{
+			{ToAccountID: account2.ID, Amount: 10},
+			{ToAccountID: account3.ID, Amount: 20},
+			{ToAccountID: account2.ID, Amount: 30},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Legs, 3)
+
+	for _, leg := range result.Legs {
+		require.NoError(t, leg.Err)
+		require.NotZero(t, leg.Result.Transfer.ID)
+		require.Equal(t, account1.ID, leg.Result.Transfer.FromAccountID)
+	}
+	require.Equal(t, account1.Balance-60, result.Legs[2].Result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+40, result.Legs[2].Result.ToAccount.Balance)
+	require.Equal(t, account3.Balance+20, result.Legs[1].Result.ToAccount.Balance)
+}
+
+func TestBatchTransferTxAllOrNothing(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	_, err := testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		FromAccountID: account1.ID,
+		Legs: []BatchTransferLeg{
+			{ToAccountID: account2.ID, Amount: 10},
+			{ToAccountID: account2.ID, Amount: account1.Balance},
+		},
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	var legErr *BatchLegError
+	require.ErrorAs(t, err, &legErr)
+	require.Equal(t, 1, legErr.Index)
+
+	// the first leg is rolled back too
+	updatedAccount1, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+}
+
+func TestBatchTransferTxBestEffort(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	result, err := testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		FromAccountID: account1.ID,
+		Legs: []BatchTransferLeg{
+			{ToAccountID: account2.ID, Amount: 10},
+			{ToAccountID: account2.ID, Amount: account1.Balance},
+			{ToAccountID: account2.ID, Amount: 20},
+		},
+		BestEffort: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Legs, 3)
+
+	require.NoError(t, result.Legs[0].Err)
+	require.ErrorIs(t, result.Legs[1].Err, ErrInsufficientFunds)
+	require.Zero(t, result.Legs[1].Result.Transfer.ID)
+	require.NoError(t, result.Legs[2].Err)
+
+	updatedAccount1, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-30, updatedAccount1.Balance)
+}
+
+func TestBatchTransferTxDeadlock(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+	account3 := createAccountWithCurrency(t, account1.Currency)
+
+	// run batches in opposite directions concurrently
+	n := 10
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		fromAccount, toAccounts := account1, []Account{account2, account3}
+		if i%2 == 1 {
+			fromAccount, toAccounts = account3, []Account{account2, account1}
+		}
+
+		go func() {
+			_, err := testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+				FromAccountID: fromAccount.ID,
+				Legs: []BatchTransferLeg{
+					{ToAccountID: toAccounts[0].ID, Amount: 1},
+					{ToAccountID: toAccounts[1].ID, Amount: 1},
+				},
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	updatedAccount2, err := testStore.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance+int64(n), updatedAccount2.Balance)
+}
+
+func TestBatchTransferTxBestEffortDeadlock(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+	account3 := createAccountWithCurrency(t, account1.Currency)
+
+	// batches of different users send to each other, while limits lock their owners
+	n := 10
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		fromAccount, toAccounts := account1, []Account{account2, account3}
+		if i%2 == 1 {
+			fromAccount, toAccounts = account2, []Account{account3, account1}
+		}
+
+		go func() {
+			_, err := testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+				FromAccountID: fromAccount.ID,
+				Legs: []BatchTransferLeg{
+					{ToAccountID: toAccounts[0].ID, Amount: 1},
+					{ToAccountID: toAccounts[1].ID, Amount: 1},
+				},
+				BestEffort: true,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+}
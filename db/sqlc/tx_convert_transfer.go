@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/antimatter007/go-backend/fx"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrFxQuoteExpired    = errors.New("fx quote has expired")
+	ErrFxQuoteUsed       = errors.New("fx quote has already been used")
+	ErrFxQuoteMismatch   = errors.New("fx quote doesn't match the transfer")
+	ErrInvalidConversion = errors.New("invalid conversion")
+)
+
+// ConvertTransferTxParams contains the input parameters of the conversion transfer transaction
+type ConvertTransferTxParams struct {
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	QuoteID       uuid.UUID `json:"quote_id"`
+	// Owner is the user who requested the quote
+	Owner string `json:"owner"`
+}
+
+// ConvertTransferTx performs a money transfer between accounts of different currencies.
+// It converts Amount with the rate locked by the quote, uses up the quote, creates the transfer,
+// add account entries, and update accounts' balance within a database transaction.
+func (store *SQLStore) ConvertTransferTx(ctx context.Context, arg ConvertTransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		quote, err := q.GetFxQuoteForUpdate(ctx, arg.QuoteID)
+		if err != nil {
+			return err
+		}
+
+		if quote.Owner != arg.Owner {
+			return ErrFxQuoteMismatch
+		}
+		if quote.UsedAt.Valid {
+			return ErrFxQuoteUsed
+		}
+		if !time.Now().Before(quote.ExpiresAt) {
+			return ErrFxQuoteExpired
+		}
+
+		fromAccount, err := q.GetAccount(ctx, arg.FromAccountID)
+		if err != nil {
+			return err
+		}
+
+		toAccount, err := q.GetAccount(ctx, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		if fromAccount.Currency != quote.FromCurrency || toAccount.Currency != quote.ToCurrency {
+			return ErrFxQuoteMismatch
+		}
+
+		toAmount, err := fx.Convert(arg.Amount, quote.FromCurrency, quote.ToCurrency, quote.Rate, quote.SpreadBps)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidConversion, err)
+		}
+
+		_, err = q.MarkFxQuoteUsed(ctx, quote.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = q.CreateConvertedTransfer(ctx, CreateConvertedTransferParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.Amount,
+			ToAmount:      pgtype.Int8{Int64: toAmount, Valid: true},
+			ExchangeRate:  pgtype.Int8{Int64: quote.Rate, Valid: true},
+			SpreadBps:     pgtype.Int4{Int32: quote.SpreadBps, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return postTransfer(ctx, q, &result, toAmount)
+	})
+	if err == nil {
+		recordTransfer(transferMetricConversion, result)
+	}
+
+	return result, err
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antimatter007/go-backend/fx"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestFxQuote(t *testing.T, owner string, fromCurrency string, toCurrency string, ttl time.Duration) FxQuote {
+	quote, err := testStore.CreateFxQuote(context.Background(), CreateFxQuoteParams{
+		ID:           uuid.New(),
+		Owner:        owner,
+		FromCurrency: fromCurrency,
+		ToCurrency:   toCurrency,
+		Rate:         92_000_000,
+		SpreadBps:    50,
+		Source:       "test",
+		ExpiresAt:    time.Now().Add(ttl),
+	})
+	require.NoError(t, err)
+	require.False(t, quote.UsedAt.Valid)
+
+	return quote
+}
+
+func TestConvertTransferTx(t *testing.T) {
+	account1 := createAccountWithCurrency(t, util.USD)
+	account2 := createAccountWithCurrency(t, util.EUR)
+	quote := createTestFxQuote(t, account1.Owner, util.USD, util.EUR, time.Minute)
+
+	amount := int64(100)
+	arg := ConvertTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+		QuoteID:       quote.ID,
+		Owner:         account1.Owner,
+	}
+
+	result, err := testStore.ConvertTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	toAmount, err := fx.Convert(amount, quote.FromCurrency, quote.ToCurrency, quote.Rate, quote.SpreadBps)
+	require.NoError(t, err)
+
+	transfer := result.Transfer
+	require.Equal(t, amount, transfer.Amount)
+	require.Equal(t, toAmount, transfer.ToAmount.Int64)
+	require.Equal(t, quote.Rate, transfer.ExchangeRate.Int64)
+	require.Equal(t, quote.SpreadBps, transfer.SpreadBps.Int32)
+
+	require.Equal(t, -amount, result.FromEntry.Amount)
+	require.Equal(t, toAmount, result.ToEntry.Amount)
+	require.Equal(t, account1.Balance-amount, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+toAmount, result.ToAccount.Balance)
+
+	// a quote can only be used once
+	_, err = testStore.ConvertTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrFxQuoteUsed)
+}
+
+func TestConvertTransferTxRejectedQuote(t *testing.T) {
+	account1 := createAccountWithCurrency(t, util.USD)
+	account2 := createAccountWithCurrency(t, util.EUR)
+
+	expired := createTestFxQuote(t, account1.Owner, util.USD, util.EUR, -time.Second)
+	_, err := testStore.ConvertTransferTx(context.Background(), ConvertTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		QuoteID:       expired.ID,
+		Owner:         account1.Owner,
+	})
+	require.ErrorIs(t, err, ErrFxQuoteExpired)
+
+	otherPair := createTestFxQuote(t, account1.Owner, util.USD, util.CAD, time.Minute)
+	_, err = testStore.ConvertTransferTx(context.Background(), ConvertTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		QuoteID:       otherPair.ID,
+		Owner:         account1.Owner,
+	})
+	require.ErrorIs(t, err, ErrFxQuoteMismatch)
+
+	_, err = testStore.ConvertTransferTx(context.Background(), ConvertTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		QuoteID:       uuid.New(),
+		Owner:         account1.Owner,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Constants for all statuses of a hold
+const (
+	HoldActive   = "active"
+	HoldCaptured = "captured"
+	HoldVoided   = "voided"
+	HoldExpired  = "expired"
+)
+
+var (
+	ErrHoldNotActive        = errors.New("hold is no longer active")
+	ErrHoldExpired          = errors.New("hold has expired")
+	ErrInvalidCaptureAmount = errors.New("capture amount must be positive and not exceed the held amount")
+)
+
+// AuthorizeHoldTxParams contains the input parameters of the authorize hold transaction
+type AuthorizeHoldTxParams struct {
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// AuthorizeHoldTx reserves Amount on the source account until the hold is captured, voided or expires.
+// No money moves, but the available balance of the account is reduced by the held amount.
+// It fails with ErrInsufficientFunds when the available balance doesn't cover the hold.
+// The source account must be active.
+func (store *SQLStore) AuthorizeHoldTx(ctx context.Context, arg AuthorizeHoldTxParams) (Hold, error) {
+	var hold Hold
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// lock the account so that concurrent holds and debits are checked one after the other
+		account, err := q.GetAccountForUpdate(ctx, arg.FromAccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkAccountActive(account); err != nil {
+			return err
+		}
+
+		heldAmount, err := q.GetHeldAmount(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+
+		if account.Balance-heldAmount < arg.Amount {
+			return ErrInsufficientFunds
+		}
+
+		hold, err = q.CreateHold(ctx, CreateHoldParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.Amount,
+			ExpiresAt:     arg.ExpiresAt,
+		})
+		return err
+	})
+
+	return hold, err
+}
+
+// CaptureHoldTxParams contains the input parameters of the capture hold transaction
+type CaptureHoldTxParams struct {
+	ID int64 `json:"id"`
+	// Amount is the part of the held amount to settle, the rest is released
+	Amount int64 `json:"amount"`
+}
+
+// CaptureHoldTxResult is the result of the capture hold transaction
+type CaptureHoldTxResult struct {
+	Hold     Hold             `json:"hold"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// CaptureHoldTx settles an active hold by transferring Amount to the destination account
+// and releasing whatever is left of the hold, within a database transaction.
+func (store *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHoldForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != HoldActive {
+			return ErrHoldNotActive
+		}
+		if !time.Now().Before(hold.ExpiresAt) {
+			return ErrHoldExpired
+		}
+		if arg.Amount <= 0 || arg.Amount > hold.Amount {
+			return ErrInvalidCaptureAmount
+		}
+
+		// release the hold first, so that the transfer doesn't count it against the balance
+		_, err = q.UpdateHold(ctx, UpdateHoldParams{
+			ID:             hold.ID,
+			Status:         HoldCaptured,
+			CapturedAmount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = transferTx(ctx, q, TransferTxParams{
+			FromAccountID: hold.FromAccountID,
+			ToAccountID:   hold.ToAccountID,
+			Amount:        arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.UpdateHold(ctx, UpdateHoldParams{
+			ID:             hold.ID,
+			Status:         HoldCaptured,
+			CapturedAmount: arg.Amount,
+			TransferID: pgtype.Int8{
+				Int64: result.Transfer.Transfer.ID,
+				Valid: true,
+			},
+		})
+		return err
+	})
+	if err == nil {
+		recordTransfer(transferMetricHoldCapture, result.Transfer)
+	}
+
+	return result, err
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func authorizeTestHold(t *testing.T, account1, account2 Account, amount int64, ttl time.Duration) Hold {
+	hold, err := testStore.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+		ExpiresAt:     time.Now().Add(ttl),
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldActive, hold.Status)
+	require.Equal(t, amount, hold.Amount)
+
+	return hold
+}
+
+func TestAuthorizeHoldTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	hold := authorizeTestHold(t, account1, account2, account1.Balance-10, time.Minute)
+
+	heldAmount, err := testStore.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, hold.Amount, heldAmount)
+
+	// the balance itself doesn't change
+	updatedAccount1, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	// only 10 are left available
+	_, err = testStore.AuthorizeHoldTx(context.Background(), AuthorizeHoldTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        11,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        11,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+	hold := authorizeTestHold(t, account1, account2, 50, time.Minute)
+
+	_, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{ID: hold.ID, Amount: 51})
+	require.ErrorIs(t, err, ErrInvalidCaptureAmount)
+
+	result, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{ID: hold.ID, Amount: 30})
+	require.NoError(t, err)
+	require.Equal(t, HoldCaptured, result.Hold.Status)
+	require.Equal(t, int64(30), result.Hold.CapturedAmount)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Hold.TransferID.Int64)
+	require.Equal(t, account1.Balance-30, result.Transfer.FromAccount.Balance)
+	require.Equal(t, account2.Balance+30, result.Transfer.ToAccount.Balance)
+
+	// the remaining 20 are released
+	heldAmount, err := testStore.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, heldAmount)
+
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{ID: hold.ID, Amount: 10})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestVoidAndExpireHolds(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	hold := authorizeTestHold(t, account1, account2, 10, time.Minute)
+	voided, err := testStore.VoidHold(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldVoided, voided.Status)
+
+	_, err = testStore.VoidHold(context.Background(), hold.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	expired := authorizeTestHold(t, account1, account2, 10, -time.Second)
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{ID: expired.ID, Amount: 10})
+	require.ErrorIs(t, err, ErrHoldExpired)
+
+	_, err = testStore.ExpireHolds(context.Background(), 1000)
+	require.NoError(t, err)
+
+	expired, err = testStore.GetHold(context.Background(), expired.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldExpired, expired.Status)
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PostInterestTxParams contains the input parameters of the post interest transaction
+type PostInterestTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Before is the first day that isn't posted, accruals of earlier days are paid out
+	Before pgtype.Date `json:"before"`
+}
+
+// PostInterestTxResult is the result of the post interest transaction
+type PostInterestTxResult struct {
+	Account Account `json:"account"`
+	// Amount is the interest paid out, in minor units. Entry and JournalEntry are the zero value when it is 0.
+	Amount       int64        `json:"amount"`
+	Entry        Entry        `json:"entry"`
+	JournalEntry JournalEntry `json:"journal_entry"`
+	// Accruals is the number of daily accruals marked as posted
+	Accruals int64 `json:"accruals"`
+}
+
+// PostInterestTx pays out the interest accrued on an account before a day, from the interest system account of its currency.
+// Only whole minor units are paid. The remainder is kept on the account and carried over to the next posting,
+// so that the interest paid over time is exactly the interest accrued. The interest of a closed account is forfeited.
+func (store *SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Account, err = q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		accrued, err := q.SumUnpostedInterest(ctx, SumUnpostedInterestParams{
+			AccountID: arg.AccountID,
+			Before:    arg.Before,
+		})
+		if err != nil {
+			return err
+		}
+
+		amount, carry := splitInterest(result.Account.InterestCarry, accrued)
+		if result.Account.Status == AccountClosed {
+			amount, carry = 0, 0
+		}
+
+		var journalEntryID pgtype.Int8
+		if amount > 0 {
+			result.JournalEntry, err = q.CreateJournalEntry(ctx, CreateJournalEntryParams{
+				Kind: JournalInterest,
+			})
+			if err != nil {
+				return err
+			}
+			journalEntryID = pgtype.Int8{Int64: result.JournalEntry.ID, Valid: true}
+
+			result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+				AccountID:      arg.AccountID,
+				Amount:         amount,
+				JournalEntryID: journalEntryID,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+				ID:     arg.AccountID,
+				Amount: amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = postToSystemAccounts(ctx, q, result.JournalEntry.ID,
+				systemPosting{Kind: AccountKindInterest, Currency: result.Account.Currency, Amount: -amount},
+			)
+			if err != nil {
+				return err
+			}
+			result.Amount = amount
+		}
+
+		result.Accruals, err = q.MarkInterestPosted(ctx, MarkInterestPostedParams{
+			JournalEntryID: journalEntryID,
+			AccountID:      arg.AccountID,
+			Before:         arg.Before,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.UpdateAccountInterestCarry(ctx, UpdateAccountInterestCarryParams{
+			ID:            arg.AccountID,
+			InterestCarry: carry,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func accrueAccountInterest(t *testing.T, account Account, day time.Time) {
+	batch, err := testStore.AccrueInterest(context.Background(), AccrueInterestParams{
+		AfterID:     account.ID - 1,
+		Limit:       1,
+		AccrualDate: pgtype.Date{Time: day, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), batch.Accounts)
+	require.Equal(t, account.ID, batch.LastAccountID)
+}
+
+func TestPostInterestTx(t *testing.T) {
+	// 1000.00 at 3.65% earns 10 cents a day
+	product := createRandomAccountProduct(t, 365, 0, 0)
+	account := createAccountWithProduct(t, product, 100000)
+
+	monthStart := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	for day := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC); day.Before(monthStart); day = day.AddDate(0, 0, 1) {
+		accrueAccountInterest(t, account, day)
+	}
+	// accruing a day again is a no-op
+	accrueAccountInterest(t, account, monthStart.AddDate(0, 0, -1))
+	// a day of the next month isn't posted yet
+	accrueAccountInterest(t, account, monthStart)
+
+	accruals, err := testStore.ListInterestAccruals(context.Background(), ListInterestAccrualsParams{
+		AccountID: account.ID,
+		Limit:     100,
+	})
+	require.NoError(t, err)
+	require.Len(t, accruals, 32)
+	require.Equal(t, int64(100000*365), accruals[0].Accrued)
+
+	result, err := testStore.PostInterestTx(context.Background(), PostInterestTxParams{
+		AccountID: account.ID,
+		Before:    pgtype.Date{Time: monthStart, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(31*10), result.Amount)
+	require.Equal(t, int64(31), result.Accruals)
+	require.Equal(t, account.Balance+result.Amount, result.Account.Balance)
+	require.Zero(t, result.Account.InterestCarry)
+	require.Equal(t, result.Amount, result.Entry.Amount)
+	require.Equal(t, JournalInterest, result.JournalEntry.Kind)
+
+	lines := requireJournalEntryBalanced(t, result.JournalEntry)
+	require.Len(t, lines, 2)
+
+	// posting again finds nothing before the month
+	result, err = testStore.PostInterestTx(context.Background(), PostInterestTxParams{
+		AccountID: account.ID,
+		Before:    pgtype.Date{Time: monthStart, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Amount)
+	require.Zero(t, result.Accruals)
+}
+
+func TestPostInterestTxCarry(t *testing.T) {
+	// 10.01 at 1% earns a fraction of a cent a day
+	product := createRandomAccountProduct(t, 100, 0, 0)
+	account := createAccountWithProduct(t, product, 1001)
+
+	day := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+	accrueAccountInterest(t, account, day)
+
+	result, err := testStore.PostInterestTx(context.Background(), PostInterestTxParams{
+		AccountID: account.ID,
+		Before:    pgtype.Date{Time: day.AddDate(0, 0, 1), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Amount)
+	require.Zero(t, result.JournalEntry.ID)
+	require.Equal(t, int64(1), result.Accruals)
+	require.Equal(t, int64(1001*100), result.Account.InterestCarry)
+	require.Equal(t, account.Balance, result.Account.Balance)
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrTransferAlreadyReversed = errors.New("transfer has already been reversed in full")
+	ErrTransferIsReversal      = errors.New("a reversal cannot be reversed")
+	ErrConvertedReversal       = errors.New("a cross-currency transfer cannot be reversed")
+	ErrInvalidReversalAmount   = errors.New("reversal amount must be positive and not exceed the remaining amount")
+)
+
+// ReverseTransferTxParams contains the input parameters of the reverse transfer transaction
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount is the part of the transfer to refund, zero reverses whatever remains
+	Amount int64 `json:"amount"`
+}
+
+// ReverseTransferTxResult is the result of the reverse transfer transaction
+type ReverseTransferTxResult struct {
+	Original Transfer         `json:"original"`
+	Reversal TransferTxResult `json:"reversal"`
+	// RemainingAmount is what is left of the original transfer to reverse
+	RemainingAmount int64 `json:"remaining_amount"`
+}
+
+// ReverseTransferTx moves money of a transfer back from the recipient to the sender.
+// It creates a transfer linked to the original one, with compensating entries, within a database transaction.
+// The reversals of a transfer never add up to more than its amount.
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// lock the original transfer so that concurrent reversals are counted one after the other
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		result.Original = original
+
+		if original.ReversedTransferID.Valid {
+			return ErrTransferIsReversal
+		}
+		if original.ToAmount.Valid {
+			return ErrConvertedReversal
+		}
+
+		originalID := pgtype.Int8{
+			Int64: original.ID,
+			Valid: true,
+		}
+		reversedAmount, err := q.GetReversedAmount(ctx, originalID)
+		if err != nil {
+			return err
+		}
+
+		remainingAmount := original.Amount - reversedAmount
+		if remainingAmount <= 0 {
+			return ErrTransferAlreadyReversed
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remainingAmount
+		}
+		if amount < 0 || amount > remainingAmount {
+			return ErrInvalidReversalAmount
+		}
+
+		result.Reversal.Transfer, err = q.CreateReversalTransfer(ctx, CreateReversalTransferParams{
+			FromAccountID:      original.ToAccountID,
+			ToAccountID:        original.FromAccountID,
+			Amount:             amount,
+			ReversedTransferID: originalID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = postTransfer(ctx, q, &result.Reversal, amount)
+		if err != nil {
+			return err
+		}
+
+		result.RemainingAmount = remainingAmount - amount
+		return nil
+	})
+	if err == nil {
+		recordTransfer(transferMetricReversal, result.Reversal)
+	}
+
+	return result, err
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransferTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	transfer, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        50,
+	})
+	require.NoError(t, err)
+
+	// partial refund
+	result, err := testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     20,
+	})
+	require.NoError(t, err)
+
+	reversal := result.Reversal.Transfer
+	require.Equal(t, account2.ID, reversal.FromAccountID)
+	require.Equal(t, account1.ID, reversal.ToAccountID)
+	require.Equal(t, int64(20), reversal.Amount)
+	require.Equal(t, transfer.Transfer.ID, reversal.ReversedTransferID.Int64)
+	require.Equal(t, int64(-20), result.Reversal.FromEntry.Amount)
+	require.Equal(t, int64(20), result.Reversal.ToEntry.Amount)
+	require.Equal(t, int64(30), result.RemainingAmount)
+
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     31,
+	})
+	require.ErrorIs(t, err, ErrInvalidReversalAmount)
+
+	// full reversal of what remains
+	result, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(30), result.Reversal.Transfer.Amount)
+	require.Zero(t, result.RemainingAmount)
+
+	updatedAccount1, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := testStore.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: result.Reversal.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferIsReversal)
+}
+
+func TestReverseTransferTxConcurrent(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+
+	transfer, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        50,
+	})
+	require.NoError(t, err)
+
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: transfer.Transfer.ID,
+			})
+			errs <- err
+		}()
+	}
+
+	// only one full reversal goes through
+	reversed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			reversed++
+			continue
+		}
+		require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+	}
+	require.Equal(t, 1, reversed)
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/antimatter007/go-backend/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Constants for all statuses of a scheduled transfer
+const (
+	ScheduledTransferActive    = "active"
+	ScheduledTransferPaused    = "paused"
+	ScheduledTransferCancelled = "cancelled"
+	ScheduledTransferCompleted = "completed"
+)
+
+// Constants for all statuses of a scheduled transfer run
+const (
+	ScheduledTransferRunSucceeded = "succeeded"
+	ScheduledTransferRunFailed    = "failed"
+)
+
+// ErrScheduledTransferNotDue is returned when the run was already executed,
+// or the scheduled transfer was paused or cancelled in the meantime.
+var ErrScheduledTransferNotDue = errors.New("scheduled transfer is not due")
+
+// ExecuteScheduledTransferTxParams contains the input parameters of the scheduled transfer transaction
+type ExecuteScheduledTransferTxParams struct {
+	ID           int64     `json:"id"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
+
+// ExecuteScheduledTransferTxResult is the result of the scheduled transfer transaction
+type ExecuteScheduledTransferTxResult struct {
+	ScheduledTransfer ScheduledTransfer    `json:"scheduled_transfer"`
+	Run               ScheduledTransferRun `json:"run"`
+	Transfer          TransferTxResult     `json:"transfer"`
+}
+
+// ExecuteScheduledTransferTx executes the run of a scheduled transfer that is due at ScheduledFor.
+// It performs the transfer, records the run and moves the schedule to its next run within a database transaction.
+// If the transfer is rejected, the failed run is recorded and the schedule still moves on; the returned run tells both cases apart.
+// Other errors, such as deadlocks or a lost connection, are returned without recording the run, so that it can be retried.
+// A run is recorded at most once, so executing it again returns ErrScheduledTransferNotDue.
+func (store *SQLStore) ExecuteScheduledTransferTx(ctx context.Context, arg ExecuteScheduledTransferTxParams) (ExecuteScheduledTransferTxResult, error) {
+	var result ExecuteScheduledTransferTxResult
+	var transferErr error
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		scheduledTransfer, err := lockDueScheduledTransfer(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, transferErr = transferTx(ctx, q, TransferTxParams{
+			FromAccountID: scheduledTransfer.FromAccountID,
+			ToAccountID:   scheduledTransfer.ToAccountID,
+			Amount:        scheduledTransfer.Amount,
+		})
+		if transferErr != nil {
+			return transferErr
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduledTransfer.ID,
+			ScheduledFor:        arg.ScheduledFor,
+			Status:              ScheduledTransferRunSucceeded,
+			TransferID: pgtype.Int8{
+				Int64: result.Transfer.Transfer.ID,
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ScheduledTransfer, err = advanceScheduledTransfer(ctx, q, scheduledTransfer, arg.ScheduledFor)
+		return err
+	})
+	if transferErr == nil || err != transferErr || !isTransferRejected(transferErr) {
+		if err == nil {
+			recordTransfer(transferMetricScheduled, result.Transfer)
+		}
+		return result, err
+	}
+
+	// the failed transfer aborted the transaction, so the failed run is recorded in a new one
+	result = ExecuteScheduledTransferTxResult{}
+	err = store.execTx(ctx, func(q *Queries) error {
+		scheduledTransfer, err := lockDueScheduledTransfer(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduledTransfer.ID,
+			ScheduledFor:        arg.ScheduledFor,
+			Status:              ScheduledTransferRunFailed,
+			Error:               transferErr.Error(),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ScheduledTransfer, err = advanceScheduledTransfer(ctx, q, scheduledTransfer, arg.ScheduledFor)
+		return err
+	})
+
+	return result, err
+}
+
+// lockDueScheduledTransfer locks the scheduled transfer and checks that the run is still due,
+// so that concurrent workers never execute the same run twice.
+func lockDueScheduledTransfer(ctx context.Context, q *Queries, arg ExecuteScheduledTransferTxParams) (ScheduledTransfer, error) {
+	scheduledTransfer, err := q.GetScheduledTransferForUpdate(ctx, arg.ID)
+	if err != nil {
+		return scheduledTransfer, err
+	}
+
+	if scheduledTransfer.Status != ScheduledTransferActive ||
+		!scheduledTransfer.NextRunAt.Valid ||
+		!scheduledTransfer.NextRunAt.Time.Equal(arg.ScheduledFor) {
+		return scheduledTransfer, ErrScheduledTransferNotDue
+	}
+
+	return scheduledTransfer, nil
+}
+
+// advanceScheduledTransfer moves the schedule to its next run, or completes it when there is none left.
+// Runs that were missed while the worker was down are skipped rather than executed in a burst.
+func advanceScheduledTransfer(ctx context.Context, q *Queries, scheduledTransfer ScheduledTransfer, scheduledFor time.Time) (ScheduledTransfer, error) {
+	after := time.Now()
+	if scheduledFor.After(after) {
+		after = scheduledFor
+	}
+
+	arg := UpdateScheduledTransferScheduleParams{
+		ID:     scheduledTransfer.ID,
+		Status: ScheduledTransferCompleted,
+	}
+
+	nextRunAt, ok := util.NextOccurrence(scheduledTransfer.Recurrence, scheduledTransfer.FirstRunAt, after)
+	if ok && (!scheduledTransfer.EndAt.Valid || !nextRunAt.After(scheduledTransfer.EndAt.Time)) {
+		arg.Status = ScheduledTransferActive
+		arg.NextRunAt = pgtype.Timestamptz{
+			Time:  nextRunAt,
+			Valid: true,
+		}
+	}
+
+	return q.UpdateScheduledTransferSchedule(ctx, arg)
+}
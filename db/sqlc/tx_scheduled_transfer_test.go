@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antimatter007/go-backend/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createDueScheduledTransfer(t *testing.T, account1, account2 Account, recurrence string) ScheduledTransfer {
+	firstRunAt := time.Now().Add(-time.Minute).Truncate(time.Microsecond)
+
+	scheduledTransfer, err := testStore.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		Owner:         account1.Owner,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Recurrence:    recurrence,
+		FirstRunAt:    firstRunAt,
+		NextRunAt: pgtype.Timestamptz{
+			Time:  firstRunAt,
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferActive, scheduledTransfer.Status)
+
+	return scheduledTransfer
+}
+
+func TestExecuteScheduledTransferTxOnce(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+	scheduledTransfer := createDueScheduledTransfer(t, account1, account2, util.RecurrenceOnce)
+
+	n := 5
+	errs := make(chan error)
+	results := make(chan ExecuteScheduledTransferTxResult)
+
+	// the same run is picked up by n concurrent workers
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := testStore.ExecuteScheduledTransferTx(context.Background(), ExecuteScheduledTransferTxParams{
+				ID:           scheduledTransfer.ID,
+				ScheduledFor: scheduledTransfer.NextRunAt.Time,
+			})
+
+			errs <- err
+			results <- result
+		}()
+	}
+
+	executed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		result := <-results
+		if err != nil {
+			require.ErrorIs(t, err, ErrScheduledTransferNotDue)
+			continue
+		}
+
+		executed++
+		require.Equal(t, ScheduledTransferRunSucceeded, result.Run.Status)
+		require.Equal(t, result.Transfer.Transfer.ID, result.Run.TransferID.Int64)
+		require.Equal(t, ScheduledTransferCompleted, result.ScheduledTransfer.Status)
+		require.False(t, result.ScheduledTransfer.NextRunAt.Valid)
+	}
+	require.Equal(t, 1, executed)
+
+	updatedAccount1, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-scheduledTransfer.Amount, updatedAccount1.Balance)
+}
+
+func TestExecuteScheduledTransferTxRecurring(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+	scheduledTransfer := createDueScheduledTransfer(t, account1, account2, util.RecurrenceDaily)
+
+	result, err := testStore.ExecuteScheduledTransferTx(context.Background(), ExecuteScheduledTransferTxParams{
+		ID:           scheduledTransfer.ID,
+		ScheduledFor: scheduledTransfer.NextRunAt.Time,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferRunSucceeded, result.Run.Status)
+	require.Equal(t, ScheduledTransferActive, result.ScheduledTransfer.Status)
+	require.True(t, result.ScheduledTransfer.NextRunAt.Valid)
+	require.WithinDuration(t, scheduledTransfer.FirstRunAt.AddDate(0, 0, 1), result.ScheduledTransfer.NextRunAt.Time, time.Second)
+}
+
+func TestExecuteScheduledTransferTxRetriesTransientErrors(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+	scheduledTransfer := createDueScheduledTransfer(t, account1, account2, util.RecurrenceMonthly)
+	arg := ExecuteScheduledTransferTxParams{
+		ID:           scheduledTransfer.ID,
+		ScheduledFor: scheduledTransfer.NextRunAt.Time,
+	}
+
+	// another transaction holds the destination account, so the transfer times out
+	tx, err := testStore.(*SQLStore).connPool.Begin(context.Background())
+	require.NoError(t, err)
+	_, err = New(tx).GetAccountForUpdate(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, err = testStore.ExecuteScheduledTransferTx(ctx, arg)
+	require.Error(t, err)
+	require.False(t, isTransferRejected(err))
+	require.NoError(t, tx.Rollback(context.Background()))
+
+	// the run was neither recorded nor skipped
+	unchanged, err := testStore.GetScheduledTransfer(context.Background(), scheduledTransfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferActive, unchanged.Status)
+	require.True(t, unchanged.NextRunAt.Time.Equal(scheduledTransfer.NextRunAt.Time))
+
+	result, err := testStore.ExecuteScheduledTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferRunSucceeded, result.Run.Status)
+}
+
+func TestExecuteScheduledTransferTxRecordsRejectedTransfer(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createAccountWithCurrency(t, account1.Currency)
+	scheduledTransfer := createDueScheduledTransfer(t, account1, account2, util.RecurrenceMonthly)
+
+	_, err := testStore.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		ID:     account1.ID,
+		Status: AccountFrozen,
+	})
+	require.NoError(t, err)
+
+	result, err := testStore.ExecuteScheduledTransferTx(context.Background(), ExecuteScheduledTransferTxParams{
+		ID:           scheduledTransfer.ID,
+		ScheduledFor: scheduledTransfer.NextRunAt.Time,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferRunFailed, result.Run.Status)
+	require.Contains(t, result.Run.Error, ErrAccountFrozen.Error())
+	require.Equal(t, ScheduledTransferActive, result.ScheduledTransfer.Status)
+	require.True(t, result.ScheduledTransfer.NextRunAt.Time.After(scheduledTransfer.NextRunAt.Time))
+}
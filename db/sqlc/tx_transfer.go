@@ -1,6 +1,35 @@
 package db
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrInsufficientFunds is returned when the available balance of an account,
+// its balance less the amount of its active holds, doesn't cover a debit.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// isTransferRejected reports whether a transfer failed because of the accounts or the amount,
+// which executing it again would not change.
+func isTransferRejected(err error) bool {
+	switch {
+	case errors.Is(err, ErrInsufficientFunds),
+		errors.Is(err, ErrAccountFrozen),
+		errors.Is(err, ErrAccountClosed),
+		errors.Is(err, ErrTransferLimitExceeded),
+		errors.Is(err, ErrRecordNotFound):
+		return true
+	}
+
+	switch ErrorCode(err) {
+	case ForeignKeyViolation, CheckViolation:
+		return true
+	}
+	return false
+}
 
 // TransferTxParams contains the input parameters of the transfer transaction
 type TransferTxParams struct {
@@ -16,51 +45,153 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// JournalEntry links all entries of the transfer, which sum to zero in every currency
+	JournalEntry JournalEntry `json:"journal_entry"`
+	// Fee is charged to the source account on top of the amount, by the fee schedule of its product.
+	// FeeEntry is the zero value when there is no fee.
+	Fee      int64 `json:"fee"`
+	FeeEntry Entry `json:"fee_entry"`
 }
 
 // TransferTx performs a money transfer from one account to the other.
-// It creates the transfer, add account entries, and update accounts' balance within a database transaction
+// It creates the transfer, add account entries, and update accounts' balance within a database transaction.
+// It fails with ErrInsufficientFunds when the transfer and its fee exceed the available balance of the source account,
+// with ErrAccountFrozen or ErrAccountClosed when either account isn't active,
+// and with ErrTransferLimitExceeded when it goes over a transfer limit of the source account.
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
+		result, err = transferTx(ctx, q, arg)
+		return err
+	})
+	if err == nil {
+		recordTransfer(transferMetricTransfer, result)
+	}
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
-		if err != nil {
+	return result, err
+}
+
+// transferTx runs the statements of a transfer with q, so that other transactions
+// can move money as one of their steps.
+func transferTx(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
+
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = postTransfer(ctx, q, &result, arg.Amount)
+	return result, err
+}
+
+// postTransfer adds the journal entry of a created transfer with its account entries, and updates the accounts' balance.
+// The destination account is credited with toAmount, which differs from the transfer amount on a conversion.
+func postTransfer(ctx context.Context, q *Queries, result *TransferTxResult, toAmount int64) error {
+	var err error
+	transfer := result.Transfer
+
+	// a reversal gives money back, so it never counts against the limits of the account
+	limited := journalKind(transfer) != JournalReversal
+	if limited {
+		if err := lockTransferLimitOwners(ctx, q, transfer.FromAccountID); err != nil {
 			return err
 		}
+	}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
-		if err != nil {
+	result.JournalEntry, err = q.CreateJournalEntry(ctx, CreateJournalEntryParams{
+		Kind:       journalKind(transfer),
+		TransferID: pgtype.Int8{Int64: transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	journalEntryID := pgtype.Int8{Int64: result.JournalEntry.ID, Valid: true}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:      transfer.FromAccountID,
+		Amount:         -transfer.Amount,
+		JournalEntryID: journalEntryID,
+	})
+	if err != nil {
+		return err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:      transfer.ToAccountID,
+		Amount:         toAmount,
+		JournalEntryID: journalEntryID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// update the balances in account id order to avoid deadlocks
+	if transfer.FromAccountID < transfer.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMoney(ctx, q, transfer.FromAccountID, -transfer.Amount, transfer.ToAccountID, toAmount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(ctx, q, transfer.ToAccountID, toAmount, transfer.FromAccountID, -transfer.Amount)
+	}
+	if err != nil {
+		return err
+	}
+
+	// the status is checked on the locked rows, so that a concurrent freeze or close is never missed
+	if err := checkAccountActive(result.FromAccount); err != nil {
+		return fmt.Errorf("cannot debit account %d: %w", transfer.FromAccountID, err)
+	}
+	if err := checkAccountActive(result.ToAccount); err != nil {
+		return fmt.Errorf("cannot credit account %d: %w", transfer.ToAccountID, err)
+	}
+
+	if limited {
+		if err := checkTransferLimits(ctx, q, transfer, result.FromAccount); err != nil {
 			return err
 		}
+	}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+	if transfer.ToAmount.Valid {
+		// the bank buys the source currency and sells the destination currency,
+		// which balances the journal entry in each of them
+		_, err = postToSystemAccounts(ctx, q, result.JournalEntry.ID,
+			systemPosting{Kind: AccountKindFx, Currency: result.FromAccount.Currency, Amount: transfer.Amount},
+			systemPosting{Kind: AccountKindFx, Currency: result.ToAccount.Currency, Amount: -toAmount},
+		)
 		if err != nil {
 			return err
 		}
+	}
 
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	// fees only apply to plain transfers, a conversion is priced by its spread
+	if journalKind(transfer) == JournalTransfer {
+		if err := chargeTransferFee(ctx, q, result); err != nil {
+			return err
 		}
+	}
 
+	// the balance is checked after the update, which locks the account row
+	// and so makes sure that no concurrent debit or hold is missed
+	return checkAvailableBalance(ctx, q, result.FromAccount)
+}
+
+// checkAvailableBalance makes sure that the active holds on the account are still covered by its balance
+func checkAvailableBalance(ctx context.Context, q *Queries, account Account) error {
+	heldAmount, err := q.GetHeldAmount(ctx, account.ID)
+	if err != nil {
 		return err
-	})
+	}
 
-	return result, err
+	if account.Balance < heldAmount {
+		return ErrInsufficientFunds
+	}
+	return nil
 }
 
 func addMoney(
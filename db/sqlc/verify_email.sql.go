@@ -40,6 +40,24 @@ func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailPa
 	return i, err
 }
 
+const deleteExpiredVerifyEmails = `-- name: DeleteExpiredVerifyEmails :execrows
+DELETE FROM verify_emails
+WHERE id IN (
+    SELECT id FROM verify_emails
+    WHERE expired_at < now()
+    ORDER BY expired_at
+    LIMIT $1
+)
+`
+
+func (q *Queries) DeleteExpiredVerifyEmails(ctx context.Context, limit int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredVerifyEmails, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateVerifyEmail = `-- name: UpdateVerifyEmail :one
 UPDATE verify_emails
 SET
@@ -0,0 +1,140 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/antimatter007/go-backend/util"
+)
+
+// RateScale is the fixed-point scale of exchange rates: a rate of 1.0 is stored as RateScale
+const RateScale = 100_000_000
+
+// rateDecimals is the number of decimal places that RateScale keeps
+const rateDecimals = 8
+
+// MaxSpreadBps is the highest spread that can be charged on a conversion, 100%
+const MaxSpreadBps = 10_000
+
+var (
+	ErrRateNotFound = errors.New("exchange rate not found")
+	ErrInvalidRate  = errors.New("invalid exchange rate")
+)
+
+// Rate is the mid-market price of one unit of Base in units of Quote
+type Rate struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Value     int64     `json:"value"`
+	Source    string    `json:"source"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// RateProvider looks up exchange rates between two currencies
+type RateProvider interface {
+	Rate(ctx context.Context, base string, quote string) (Rate, error)
+}
+
+// ParseRate parses a positive decimal string such as "1.0825" into a fixed-point rate.
+// Digits beyond the eighth decimal place are truncated.
+func ParseRate(value string) (int64, error) {
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(value), ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+	if len(fraction) > rateDecimals {
+		fraction = fraction[:rateDecimals]
+	}
+	fraction += strings.Repeat("0", rateDecimals-len(fraction))
+
+	digits := whole + fraction
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+		}
+	}
+
+	rate, ok := new(big.Int).SetString(digits, 10)
+	if !ok || !rate.IsInt64() || rate.Sign() <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+
+	return rate.Int64(), nil
+}
+
+// FormatRate formats a fixed-point rate as a decimal string
+func FormatRate(rate int64) string {
+	return fmt.Sprintf("%d.%08d", rate/RateScale, rate%RateScale)
+}
+
+// CrossRate returns the rate from base to quote, given the rates of both against a common currency.
+func CrossRate(baseRate int64, quoteRate int64) (int64, error) {
+	if baseRate <= 0 || quoteRate <= 0 {
+		return 0, ErrInvalidRate
+	}
+
+	rate := new(big.Int).Mul(big.NewInt(quoteRate), big.NewInt(RateScale))
+	rate.Quo(rate, big.NewInt(baseRate))
+	if !rate.IsInt64() || rate.Sign() <= 0 {
+		return 0, ErrInvalidRate
+	}
+
+	return rate.Int64(), nil
+}
+
+// Convert converts an amount in minor units of from into minor units of to, with a mid-market rate
+// quoted per major unit, keeping spreadBps basis points of the result as the spread.
+// The result is rounded down, so rounding never favors the customer beyond the quoted rate.
+func Convert(amount int64, from string, to string, rate int64, spreadBps int32) (int64, error) {
+	if amount <= 0 {
+		return 0, fmt.Errorf("amount must be positive")
+	}
+	if rate <= 0 {
+		return 0, ErrInvalidRate
+	}
+	if spreadBps < 0 || spreadBps >= MaxSpreadBps {
+		return 0, fmt.Errorf("spread must be between 0 and %d basis points", MaxSpreadBps)
+	}
+
+	fromCurrency, ok := util.LookupCurrency(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", from)
+	}
+	toCurrency, ok := util.LookupCurrency(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", to)
+	}
+
+	denominator := big.NewInt(RateScale * MaxSpreadBps)
+	// minor units differ in size, e.g. one cent is worth a hundredth of a dollar but one yen is a whole yen
+	exponentDiff := toCurrency.Exponent - fromCurrency.Exponent
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exponentDiff))), nil)
+
+	converted := new(big.Int).Mul(big.NewInt(amount), big.NewInt(rate))
+	converted.Mul(converted, big.NewInt(int64(MaxSpreadBps-spreadBps)))
+	if exponentDiff > 0 {
+		converted.Mul(converted, scale)
+	} else {
+		denominator.Mul(denominator, scale)
+	}
+	converted.Quo(converted, denominator)
+	if !converted.IsInt64() {
+		return 0, fmt.Errorf("converted amount overflows")
+	}
+	if converted.Sign() <= 0 {
+		return 0, fmt.Errorf("amount is too small to convert")
+	}
+
+	return converted.Int64(), nil
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,154 @@
+package fx
+
+import (
+	"testing"
+
+	"github.com/antimatter007/go-backend/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	testCases := []struct {
+		value string
+		rate  int64
+		ok    bool
+	}{
+		{"1", RateScale, true},
+		{"0.92", 92_000_000, true},
+		{"1.0825", 108_250_000, true},
+		{"151.123456789", 15_112_345_678, true},
+		{".5", 50_000_000, true},
+		{"0", 0, false},
+		{"-1.2", 0, false},
+		{"1e3", 0, false},
+		{"1.2.3", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tc := range testCases {
+		rate, err := ParseRate(tc.value)
+		if !tc.ok {
+			require.ErrorIs(t, err, ErrInvalidRate, tc.value)
+			continue
+		}
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.rate, rate, tc.value)
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	require.Equal(t, "1.00000000", FormatRate(RateScale))
+	require.Equal(t, "0.92000000", FormatRate(92_000_000))
+	require.Equal(t, "151.12345678", FormatRate(15_112_345_678))
+}
+
+func TestCrossRate(t *testing.T) {
+	// USD based table: 1 USD = 0.8 EUR = 1.2 CAD, so 1 EUR = 1.5 CAD
+	rate, err := CrossRate(80_000_000, 120_000_000)
+	require.NoError(t, err)
+	require.Equal(t, int64(150_000_000), rate)
+
+	_, err = CrossRate(0, 120_000_000)
+	require.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestConvert(t *testing.T) {
+	converted, err := Convert(10_000, util.USD, util.EUR, 92_000_000, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(9_200), converted)
+
+	// 50 bps of 9200 is 46
+	converted, err = Convert(10_000, util.USD, util.EUR, 92_000_000, 50)
+	require.NoError(t, err)
+	require.Equal(t, int64(9_154), converted)
+
+	// 3 * 0.33333333 = 0.99999999 is rounded down
+	converted, err = Convert(300, util.USD, util.EUR, 33_333_333, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(99), converted)
+
+	_, err = Convert(1, util.USD, util.EUR, 1_000_000, 0)
+	require.Error(t, err)
+
+	_, err = Convert(0, util.USD, util.EUR, RateScale, 0)
+	require.Error(t, err)
+
+	_, err = Convert(100, util.USD, util.EUR, RateScale, MaxSpreadBps)
+	require.Error(t, err)
+
+	_, err = Convert(1<<62, util.USD, util.EUR, 1000*RateScale, 0)
+	require.Error(t, err)
+	_, err = Convert(100, "XXX", util.EUR, RateScale, 0)
+	require.Error(t, err)
+}
+
+func TestConvertExponents(t *testing.T) {
+	testCases := []struct {
+		name      string
+		amount    int64
+		from      string
+		to        string
+		rate      string
+		converted int64
+	}{
+		{
+			// 100.00 USD at 150 JPY per USD
+			name:      "USDToJPY",
+			amount:    10_000,
+			from:      util.USD,
+			to:        util.JPY,
+			rate:      "150",
+			converted: 15_000,
+		},
+		{
+			// 15,000 JPY at 0.00666667 USD per JPY is 100.00005 USD
+			name:      "JPYToUSD",
+			amount:    15_000,
+			from:      util.JPY,
+			to:        util.USD,
+			rate:      "0.00666667",
+			converted: 10_000,
+		},
+		{
+			// 100.00 USD at 0.376 BHD per USD
+			name:      "USDToBHD",
+			amount:    10_000,
+			from:      util.USD,
+			to:        util.BHD,
+			rate:      "0.376",
+			converted: 37_600,
+		},
+		{
+			// 1.000 BHD at 2.65957446 USD per BHD is rounded down to 2.65 USD
+			name:      "BHDToUSD",
+			amount:    1_000,
+			from:      util.BHD,
+			to:        util.USD,
+			rate:      "2.65957446",
+			converted: 265,
+		},
+		{
+			// 1 JPY at 0.00251 BHD per JPY is 0.00251 BHD, rounded down to 0.002 BHD
+			name:      "JPYToBHD",
+			amount:    1,
+			from:      util.JPY,
+			to:        util.BHD,
+			rate:      "0.00251",
+			converted: 2,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := ParseRate(tc.rate)
+			require.NoError(t, err)
+
+			converted, err := Convert(tc.amount, tc.from, tc.to, rate, 0)
+			require.NoError(t, err)
+			require.Equal(t, tc.converted, converted)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxRateResponse limits how much of the rate service response is read
+const maxRateResponse = 1 << 20
+
+// HTTPProvider fetches a rate table from a remote service and caches it for a while
+type HTTPProvider struct {
+	url      string
+	cacheTTL time.Duration
+	client   *http.Client
+	now      func() time.Time
+
+	mu        sync.Mutex
+	rates     map[string]int64
+	fetchedAt time.Time
+}
+
+// NewHTTPProvider creates a provider that fetches the rate table at url,
+// which must answer with a JSON object like {"base": "USD", "rates": {"EUR": 0.92}}.
+func NewHTTPProvider(url string, timeout time.Duration, cacheTTL time.Duration) RateProvider {
+	return &HTTPProvider{
+		url:      url,
+		cacheTTL: cacheTTL,
+		client:   &http.Client{Timeout: timeout},
+		now:      time.Now,
+	}
+}
+
+func (provider *HTTPProvider) Rate(ctx context.Context, base string, quote string) (Rate, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if provider.rates == nil || provider.now().Sub(provider.fetchedAt) >= provider.cacheTTL {
+		rates, err := provider.fetch(ctx)
+		if err != nil {
+			return Rate{}, err
+		}
+		provider.rates = rates
+		provider.fetchedAt = provider.now()
+	}
+
+	value, err := lookupRate(provider.rates, base, quote)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	return Rate{
+		Base:      base,
+		Quote:     quote,
+		Value:     value,
+		Source:    provider.url,
+		FetchedAt: provider.fetchedAt,
+	}, nil
+}
+
+func (provider *HTTPProvider) fetch(ctx context.Context) (map[string]int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	rsp, err := provider.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rates: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rate service responded with status %d", rsp.StatusCode)
+	}
+
+	var table RateTable
+	decoder := json.NewDecoder(io.LimitReader(rsp.Body, maxRateResponse))
+	decoder.UseNumber()
+	if err := decoder.Decode(&table); err != nil {
+		return nil, fmt.Errorf("failed to decode rates: %w", err)
+	}
+
+	return parseRateTable(table)
+}
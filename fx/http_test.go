@@ -0,0 +1,56 @@
+package fx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	requests := 0
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"base": "USD", "rates": {"EUR": 0.8, "CAD": 1.2}}`))
+	}))
+	defer stub.Close()
+
+	now := time.Now()
+	provider := NewHTTPProvider(stub.URL, time.Second, time.Minute).(*HTTPProvider)
+	provider.now = func() time.Time { return now }
+
+	rate, err := provider.Rate(context.Background(), "EUR", "CAD")
+	require.NoError(t, err)
+	require.Equal(t, int64(150_000_000), rate.Value)
+	require.Equal(t, stub.URL, rate.Source)
+
+	// served from the cache
+	rate, err = provider.Rate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, int64(80_000_000), rate.Value)
+	require.Equal(t, 1, requests)
+
+	now = now.Add(time.Minute)
+	_, err = provider.Rate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+
+	_, err = provider.Rate(context.Background(), "USD", "JPY")
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestHTTPProviderServiceError(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer stub.Close()
+
+	provider := NewHTTPProvider(stub.URL, time.Second, time.Minute)
+
+	_, err := provider.Rate(context.Background(), "USD", "EUR")
+	require.Error(t, err)
+}
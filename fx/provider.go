@@ -0,0 +1,25 @@
+package fx
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antimatter007/go-backend/util"
+)
+
+// httpTimeout is the time allowed for the rate service to respond
+const httpTimeout = 5 * time.Second
+
+// NewProvider creates the rate provider selected by the configuration:
+// the rate service at FXRatesURL if set, the rate file at FXRatesFile otherwise.
+func NewProvider(config util.Config) (RateProvider, error) {
+	if config.FXRatesURL != "" {
+		return NewHTTPProvider(config.FXRatesURL, httpTimeout, config.FXRatesCacheTTL), nil
+	}
+
+	if config.FXRatesFile != "" {
+		return LoadStaticProvider(config.FXRatesFile)
+	}
+
+	return nil, fmt.Errorf("neither FX_RATES_URL nor FX_RATES_FILE is set")
+}
@@ -0,0 +1,99 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RateTable is a set of rates against a single base currency, as found in rate files and HTTP responses
+type RateTable struct {
+	Base  string                 `json:"base"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// StaticProvider serves rates from a fixed table, for example one loaded from a file
+type StaticProvider struct {
+	source    string
+	rates     map[string]int64
+	fetchedAt time.Time
+}
+
+// NewStaticProvider creates a provider that serves the rates of the table
+func NewStaticProvider(source string, table RateTable) (RateProvider, error) {
+	rates, err := parseRateTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StaticProvider{
+		source:    source,
+		rates:     rates,
+		fetchedAt: time.Now(),
+	}, nil
+}
+
+// LoadStaticProvider creates a provider that serves the rates of a JSON rate file
+func LoadStaticProvider(path string) (RateProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate file: %w", err)
+	}
+
+	var table RateTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse rate file: %w", err)
+	}
+
+	return NewStaticProvider("file:"+path, table)
+}
+
+func (provider *StaticProvider) Rate(ctx context.Context, base string, quote string) (Rate, error) {
+	value, err := lookupRate(provider.rates, base, quote)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	return Rate{
+		Base:      base,
+		Quote:     quote,
+		Value:     value,
+		Source:    provider.source,
+		FetchedAt: provider.fetchedAt,
+	}, nil
+}
+
+// parseRateTable converts the decimal rates of a table, adding the base currency itself
+func parseRateTable(table RateTable) (map[string]int64, error) {
+	if table.Base == "" {
+		return nil, fmt.Errorf("rate table has no base currency")
+	}
+
+	rates := map[string]int64{table.Base: RateScale}
+	for currency, number := range table.Rates {
+		rate, err := ParseRate(number.String())
+		if err != nil {
+			return nil, fmt.Errorf("rate of %s: %w", currency, err)
+		}
+		rates[currency] = rate
+	}
+
+	return rates, nil
+}
+
+// lookupRate derives the rate between two currencies of a table through its base currency
+func lookupRate(rates map[string]int64, base string, quote string) (int64, error) {
+	baseRate, ok := rates[base]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrRateNotFound, base)
+	}
+
+	quoteRate, ok := rates[quote]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrRateNotFound, quote)
+	}
+
+	return CrossRate(baseRate, quoteRate)
+}
@@ -0,0 +1,54 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStaticProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(path, []byte(`{"base": "USD", "rates": {"EUR": "0.8", "CAD": 1.2}}`), 0o600)
+	require.NoError(t, err)
+
+	provider, err := LoadStaticProvider(path)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		base  string
+		quote string
+		rate  int64
+	}{
+		{"USD", "EUR", 80_000_000},
+		{"EUR", "USD", 125_000_000},
+		{"EUR", "CAD", 150_000_000},
+		{"USD", "USD", RateScale},
+	}
+
+	for _, tc := range testCases {
+		rate, err := provider.Rate(context.Background(), tc.base, tc.quote)
+		require.NoError(t, err)
+		require.Equal(t, tc.base, rate.Base)
+		require.Equal(t, tc.quote, rate.Quote)
+		require.Equal(t, tc.rate, rate.Value, "%s/%s", tc.base, tc.quote)
+		require.Equal(t, "file:"+path, rate.Source)
+	}
+
+	_, err = provider.Rate(context.Background(), "USD", "JPY")
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestNewStaticProviderInvalidRate(t *testing.T) {
+	_, err := NewStaticProvider("test", RateTable{
+		Base:  "USD",
+		Rates: map[string]json.Number{"EUR": "-0.8"},
+	})
+	require.ErrorIs(t, err, ErrInvalidRate)
+
+	_, err = NewStaticProvider("test", RateTable{})
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getOwnAccount loads an account and makes sure it belongs to the user
+func (server *Server) getOwnAccount(ctx context.Context, id int64, username string) (db.Account, error) {
+	account, err := server.getAccount(ctx, id)
+	if err != nil {
+		return account, err
+	}
+
+	if account.Owner != username {
+		return account, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	return account, nil
+}
+
+func (server *Server) getAccount(ctx context.Context, id int64) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return account, status.Errorf(codes.NotFound, "account %d not found", id)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	return account, nil
+}
+
+// accountResponse loads the active holds on the account to convert it with its available balance
+func (server *Server) accountResponse(ctx context.Context, account db.Account) (*pb.Account, error) {
+	heldAmount, err := server.store.GetHeldAmount(ctx, account.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get held amount: %s", err)
+	}
+
+	return convertAccount(account, heldAmount), nil
+}
+
+// changeAccountStatus freezes, unfreezes or closes an account
+func (server *Server) changeAccountStatus(ctx context.Context, id int64, newStatus string, reason string) (*pb.Account, error) {
+	account, err := server.store.ChangeAccountStatusTx(ctx, db.ChangeAccountStatusTxParams{
+		ID:     id,
+		Status: newStatus,
+		Reason: reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			return nil, status.Errorf(codes.NotFound, "account %d not found", id)
+		case errors.Is(err, db.ErrInvalidAccountStatusChange),
+			errors.Is(err, db.ErrAccountBalanceNotZero):
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to change account status: %s", err)
+		}
+	}
+
+	return server.accountResponse(ctx, account)
+}
+
+func validateAccountStatusRequest(accountID int64, reason string, reasonRequired bool) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(accountID); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	minLength := 0
+	if reasonRequired {
+		minLength = 1
+	}
+	if err := val.ValidateString(reason, minLength, 200); err != nil {
+		violations = append(violations, fieldViolation("reason", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,65 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/antimatter007/go-backend/val"
+	"github.com/hibiken/asynq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getArchivedTask loads a task and makes sure it is archived, so that
+// operators cannot accidentally run or delete a task which is still in flight.
+func (server *Server) getArchivedTask(queue string, taskID string) (*asynq.TaskInfo, error) {
+	info, err := server.taskInspector.GetTaskInfo(queue, taskID)
+	if err != nil {
+		return nil, archivedTaskError(err)
+	}
+
+	if info.State != asynq.TaskStateArchived {
+		return nil, status.Errorf(codes.FailedPrecondition, "task is %s, not archived", info.State)
+	}
+
+	return info, nil
+}
+
+// resolveFailedTask updates the summary row of a failed task after an operator acted on it.
+// The task itself has already been handled, so a failure here is only logged.
+func (server *Server) resolveFailedTask(ctx context.Context, queue string, taskID string, resolution string, resolvedBy string) {
+	_, err := server.store.ResolveFailedTask(ctx, db.ResolveFailedTaskParams{
+		Resolution: resolution,
+		ResolvedBy: resolvedBy,
+		Queue:      queue,
+		TaskID:     taskID,
+	})
+	if err != nil {
+		requestid.Logger(ctx).Error().Err(err).Str("queue", queue).Str("task_id", taskID).Msg("failed to resolve failed task")
+	}
+}
+
+func archivedTaskError(err error) error {
+	if errors.Is(err, asynq.ErrQueueNotFound) {
+		return status.Errorf(codes.NotFound, "queue not found")
+	}
+	if errors.Is(err, asynq.ErrTaskNotFound) {
+		return status.Errorf(codes.NotFound, "task not found")
+	}
+	return status.Errorf(codes.Internal, "failed to inspect task: %s", err)
+}
+
+func validateArchivedTaskID(queue string, taskID string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateString(queue, 1, 100); err != nil {
+		violations = append(violations, fieldViolation("queue", err))
+	}
+
+	if err := val.ValidateString(taskID, 1, 100); err != nil {
+		violations = append(violations, fieldViolation("task_id", err))
+	}
+
+	return violations
+}
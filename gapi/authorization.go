@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/antimatter007/go-backend/token"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 )
 
 const (
@@ -14,7 +16,53 @@ const (
 	authorizationBearer = "bearer"
 )
 
-func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+// authorizeUser authorizes the user of the access token, or an internal caller identified by its client certificate
+func (server *Server) authorizeUser(ctx context.Context, accessibleRoles []string) (*token.Payload, error) {
+	payload, ok := server.authorizeInternal(ctx)
+	if !ok {
+		var err error
+		payload, err = server.verifyAccessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !hasPermission(payload.Role, accessibleRoles) {
+		return nil, fmt.Errorf("permission denied")
+	}
+
+	return payload, nil
+}
+
+// authorizeInternal returns the principal of a caller that authenticated with a client certificate over mutual TLS,
+// named after the certificate identity and given the role that config.TLSClientRoles maps it to.
+// Calls with an access token are left to verifyAccessToken, and calls forwarded by the gateway never qualify:
+// the gateway presents the same certificate on behalf of all its HTTP clients.
+func (server *Server) authorizeInternal(ctx context.Context) (*token.Payload, bool) {
+	if len(server.config.TLSClientRoles) == 0 {
+		return nil, false
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get(authorizationHeader)) > 0 || len(md.Get(xForwardedForHeader)) > 0 {
+		return nil, false
+	}
+
+	identity, ok := clientCertificateIdentity(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	role, ok := server.config.TLSClientRoles[identity]
+	if !ok {
+		return nil, false
+	}
+
+	return &token.Payload{Username: identity, Role: role}, true
+}
+
+// verifyAccessToken verifies the bearer token of the call, whatever the role of its user
+func (server *Server) verifyAccessToken(ctx context.Context) (*token.Payload, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("missing metadata")
@@ -44,3 +92,38 @@ func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error)
 
 	return payload, nil
 }
+
+func hasPermission(userRole string, accessibleRoles []string) bool {
+	for _, role := range accessibleRoles {
+		if userRole == role {
+			return true
+		}
+	}
+	return false
+}
+
+// clientCertificateIdentity returns the identity of the client certificate that the caller
+// authenticated with over mutual TLS: its first URI name, such as a SPIFFE id, else its first
+// DNS name, else its common name. Only certificates verified against the client CAs count.
+func clientCertificateIdentity(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	switch {
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String(), true
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0], true
+	case cert.Subject.CommonName != "":
+		return cert.Subject.CommonName, true
+	}
+	return "", false
+}
@@ -0,0 +1,228 @@
+package gapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientCertificateIdentity(t *testing.T) {
+	spiffeID, err := url.Parse("spiffe://simple-bank/worker")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		authInfo credentials.AuthInfo
+		identity string
+		ok       bool
+	}{
+		{
+			name: "URI",
+			authInfo: verifiedTLSInfo(&x509.Certificate{
+				URIs:     []*url.URL{spiffeID},
+				DNSNames: []string{"worker.simple-bank.svc"},
+				Subject:  pkix.Name{CommonName: "worker"},
+			}),
+			identity: "spiffe://simple-bank/worker",
+			ok:       true,
+		},
+		{
+			name: "DNSName",
+			authInfo: verifiedTLSInfo(&x509.Certificate{
+				DNSNames: []string{"worker.simple-bank.svc"},
+				Subject:  pkix.Name{CommonName: "worker"},
+			}),
+			identity: "worker.simple-bank.svc",
+			ok:       true,
+		},
+		{
+			name:     "CommonName",
+			authInfo: verifiedTLSInfo(&x509.Certificate{Subject: pkix.Name{CommonName: "worker"}}),
+			identity: "worker",
+			ok:       true,
+		},
+		{
+			name: "UnverifiedCertificate",
+			authInfo: credentials.TLSInfo{State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "worker"}}},
+			}},
+		},
+		{
+			name: "Plaintext",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{
+				Addr:     &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000},
+				AuthInfo: tc.authInfo,
+			})
+
+			identity, ok := clientCertificateIdentity(ctx)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.identity, identity)
+		})
+	}
+}
+
+func verifiedTLSInfo(cert *x509.Certificate) credentials.TLSInfo {
+	return credentials.TLSInfo{State: tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}}
+}
+
+type testIssuer struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// issueCertificate signs template with issuer, or self-signs it as a CA when issuer is nil
+func issueCertificate(t *testing.T, issuer *testIssuer, template *x509.Certificate) (tls.Certificate, *testIssuer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+
+	parent, signer := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, signer = issuer.cert, issuer.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, &testIssuer{cert: cert, key: key}
+}
+
+func TestAuthorizeInternalOverMutualTLS(t *testing.T) {
+	spiffeID, err := url.Parse("spiffe://simple-bank/ops")
+	require.NoError(t, err)
+
+	_, ca := issueCertificate(t, nil, &x509.Certificate{Subject: pkix.Name{CommonName: "test CA"}})
+	serverCert, _ := issueCertificate(t, ca, &x509.Certificate{IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)}})
+	opsCert, _ := issueCertificate(t, ca, &x509.Certificate{URIs: []*url.URL{spiffeID}})
+	otherCert, _ := issueCertificate(t, ca, &x509.Certificate{Subject: pkix.Name{CommonName: "reporting"}})
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetLatestReconciliationReport(gomock.Any()).AnyTimes().Return(db.ReconciliationReport{}, db.ErrRecordNotFound)
+
+	server := newTestServer(t, store, nil)
+	server.config.TLSClientRoles = map[string]string{spiffeID.String(): util.AdminRole}
+
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	})))
+	pb.RegisterSimpleBankServer(grpcServer, server)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	testCases := []struct {
+		name       string
+		clientCert *tls.Certificate
+		setupCtx   func(ctx context.Context) context.Context
+		code       codes.Code
+	}{
+		{
+			name:       "MappedCertificate",
+			clientCert: &opsCert,
+			setupCtx:   func(ctx context.Context) context.Context { return ctx },
+			// authorized: the report is looked up, and there is none yet
+			code: codes.NotFound,
+		},
+		{
+			name:       "UnmappedCertificate",
+			clientCert: &otherCert,
+			setupCtx:   func(ctx context.Context) context.Context { return ctx },
+			code:       codes.Unauthenticated,
+		},
+		{
+			name:     "NoCertificate",
+			setupCtx: func(ctx context.Context) context.Context { return ctx },
+			code:     codes.Unauthenticated,
+		},
+		{
+			name:       "ForwardedByGateway",
+			clientCert: &opsCert,
+			setupCtx: func(ctx context.Context) context.Context {
+				return metadata.AppendToOutgoingContext(ctx, xForwardedForHeader, "203.0.113.7")
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:       "AccessTokenTakesPrecedence",
+			clientCert: &opsCert,
+			setupCtx: func(ctx context.Context) context.Context {
+				accessToken, _, err := server.tokenMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+				require.NoError(t, err)
+				return metadata.AppendToOutgoingContext(ctx, authorizationHeader, authorizationBearer+" "+accessToken)
+			},
+			code: codes.Unauthenticated,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			clientConfig := &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}
+			if tc.clientCert != nil {
+				clientConfig.Certificates = []tls.Certificate{*tc.clientCert}
+			}
+
+			conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(clientConfig)))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err = pb.NewSimpleBankClient(conn).GetReconciliationReport(tc.setupCtx(ctx), &pb.GetReconciliationReportRequest{})
+			require.Equal(t, tc.code, status.Code(err), err)
+		})
+	}
+}
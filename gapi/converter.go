@@ -3,6 +3,8 @@ package gapi
 import (
 	db "github.com/antimatter007/go-backend/db/sqlc"
 	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/hibiken/asynq"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -15,3 +17,210 @@ func convertUser(user db.User) *pb.User {
 		CreatedAt:         timestamppb.New(user.CreatedAt),
 	}
 }
+
+func convertNotification(notification db.Notification) *pb.Notification {
+	rsp := &pb.Notification{
+		Id:        notification.ID,
+		Event:     notification.Event,
+		Title:     notification.Title,
+		Content:   notification.Content,
+		IsRead:    notification.IsRead,
+		CreatedAt: timestamppb.New(notification.CreatedAt),
+	}
+	if notification.ReadAt.Valid {
+		rsp.ReadAt = timestamppb.New(notification.ReadAt.Time)
+	}
+	return rsp
+}
+
+func convertWebhookEndpoint(endpoint db.WebhookEndpoint) *pb.WebhookEndpoint {
+	return &pb.WebhookEndpoint{
+		Id:        endpoint.ID,
+		Url:       endpoint.Url,
+		Events:    endpoint.Events,
+		IsActive:  endpoint.IsActive,
+		CreatedAt: timestamppb.New(endpoint.CreatedAt),
+	}
+}
+
+func convertWebhookDelivery(delivery db.WebhookDelivery) *pb.WebhookDelivery {
+	rsp := &pb.WebhookDelivery{
+		Id:             delivery.ID,
+		EndpointId:     delivery.EndpointID,
+		Event:          delivery.Event,
+		Payload:        string(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAt:      timestamppb.New(delivery.CreatedAt),
+	}
+	if delivery.DeliveredAt.Valid {
+		rsp.DeliveredAt = timestamppb.New(delivery.DeliveredAt.Time)
+	}
+	return rsp
+}
+
+func convertArchivedTask(info *asynq.TaskInfo) *pb.ArchivedTask {
+	rsp := &pb.ArchivedTask{
+		Id:        info.ID,
+		Queue:     info.Queue,
+		Type:      info.Type,
+		Payload:   string(info.Payload),
+		MaxRetry:  int32(info.MaxRetry),
+		Retried:   int32(info.Retried),
+		LastError: info.LastErr,
+	}
+	if !info.LastFailedAt.IsZero() {
+		rsp.LastFailedAt = timestamppb.New(info.LastFailedAt)
+	}
+	return rsp
+}
+
+func convertScheduledTransfer(scheduledTransfer db.ScheduledTransfer, currency string) *pb.ScheduledTransfer {
+	rsp := &pb.ScheduledTransfer{
+		Id:            scheduledTransfer.ID,
+		FromAccountId: scheduledTransfer.FromAccountID,
+		ToAccountId:   scheduledTransfer.ToAccountID,
+		Amount:        scheduledTransfer.Amount,
+		AmountDecimal: util.FormatAmount(scheduledTransfer.Amount, currency),
+		Recurrence:    scheduledTransfer.Recurrence,
+		Status:        scheduledTransfer.Status,
+		FirstRunAt:    timestamppb.New(scheduledTransfer.FirstRunAt),
+		CreatedAt:     timestamppb.New(scheduledTransfer.CreatedAt),
+	}
+	if scheduledTransfer.NextRunAt.Valid {
+		rsp.NextRunAt = timestamppb.New(scheduledTransfer.NextRunAt.Time)
+	}
+	if scheduledTransfer.EndAt.Valid {
+		rsp.EndAt = timestamppb.New(scheduledTransfer.EndAt.Time)
+	}
+	return rsp
+}
+
+func convertHold(hold db.Hold, currency string) *pb.Hold {
+	return &pb.Hold{
+		Id:                    hold.ID,
+		FromAccountId:         hold.FromAccountID,
+		ToAccountId:           hold.ToAccountID,
+		Amount:                hold.Amount,
+		AmountDecimal:         util.FormatAmount(hold.Amount, currency),
+		CapturedAmount:        hold.CapturedAmount,
+		CapturedAmountDecimal: util.FormatAmount(hold.CapturedAmount, currency),
+		Status:                hold.Status,
+		TransferId:            hold.TransferID.Int64,
+		ExpiresAt:             timestamppb.New(hold.ExpiresAt),
+		CreatedAt:             timestamppb.New(hold.CreatedAt),
+		UpdatedAt:             timestamppb.New(hold.UpdatedAt),
+	}
+}
+
+// convertTransfer converts a transfer whose from and to accounts hold the given currencies.
+func convertTransfer(transfer db.Transfer, fromCurrency, toCurrency string) *pb.Transfer {
+	rsp := &pb.Transfer{
+		Id:              transfer.ID,
+		FromAccountId:   transfer.FromAccountID,
+		ToAccountId:     transfer.ToAccountID,
+		Amount:          transfer.Amount,
+		AmountDecimal:   util.FormatAmount(transfer.Amount, fromCurrency),
+		ToAmountDecimal: util.FormatAmount(transfer.Amount, toCurrency),
+		CreatedAt:       timestamppb.New(transfer.CreatedAt),
+	}
+	if transfer.ToAmount.Valid {
+		rsp.ToAmount = &transfer.ToAmount.Int64
+		rsp.ToAmountDecimal = util.FormatAmount(transfer.ToAmount.Int64, toCurrency)
+	}
+	if transfer.ExchangeRate.Valid {
+		rsp.ExchangeRate = &transfer.ExchangeRate.Int64
+	}
+	if transfer.SpreadBps.Valid {
+		rsp.SpreadBps = &transfer.SpreadBps.Int32
+	}
+	if transfer.ReversedTransferID.Valid {
+		rsp.ReversedTransferId = &transfer.ReversedTransferID.Int64
+	}
+	return rsp
+}
+
+func convertReconciliationReport(report db.ReconciliationReport) *pb.ReconciliationReport {
+	rsp := &pb.ReconciliationReport{
+		Id:               report.ID,
+		Status:           report.Status,
+		AccountsChecked:  report.AccountsChecked,
+		DiscrepancyCount: report.DiscrepancyCount,
+		Error:            report.Error,
+		StartedAt:        timestamppb.New(report.StartedAt),
+	}
+	if report.FinishedAt.Valid {
+		rsp.FinishedAt = timestamppb.New(report.FinishedAt.Time)
+	}
+	return rsp
+}
+
+func convertReconciliationDiscrepancy(discrepancy db.ReconciliationDiscrepancy) *pb.ReconciliationDiscrepancy {
+	return &pb.ReconciliationDiscrepancy{
+		AccountId:     discrepancy.AccountID,
+		StoredBalance: discrepancy.StoredBalance,
+		LedgerBalance: discrepancy.LedgerBalance,
+	}
+}
+
+// convertAccount converts an account with the given amount held on it by active holds.
+func convertAccount(account db.Account, heldAmount int64) *pb.Account {
+	availableBalance := account.Balance - heldAmount
+	rsp := &pb.Account{
+		Id:                      account.ID,
+		Owner:                   account.Owner,
+		Balance:                 account.Balance,
+		BalanceDecimal:          util.FormatAmount(account.Balance, account.Currency),
+		AvailableBalance:        availableBalance,
+		AvailableBalanceDecimal: util.FormatAmount(availableBalance, account.Currency),
+		Currency:                account.Currency,
+		Status:                  account.Status,
+		StatusReason:            account.StatusReason,
+		CreatedAt:               timestamppb.New(account.CreatedAt),
+	}
+	if account.StatusChangedAt.Valid {
+		rsp.StatusChangedAt = timestamppb.New(account.StatusChangedAt.Time)
+	}
+	if account.ProductID.Valid {
+		rsp.ProductId = account.ProductID.Int64
+	}
+	return rsp
+}
+
+func convertAccountProduct(product db.AccountProduct) *pb.AccountProduct {
+	return &pb.AccountProduct{
+		Id:              product.ID,
+		Name:            product.Name,
+		InterestRateBps: product.InterestRateBps,
+		TransferFeeFlat: product.TransferFeeFlat,
+		TransferFeeBps:  product.TransferFeeBps,
+		CreatedAt:       timestamppb.New(product.CreatedAt),
+	}
+}
+
+func convertTransferLimits(limits db.TransferLimit) *pb.TransferLimits {
+	rsp := &pb.TransferLimits{
+		Username:  limits.Username.String,
+		Currency:  limits.Currency,
+		UpdatedBy: limits.UpdatedBy,
+		UpdatedAt: timestamppb.New(limits.UpdatedAt),
+	}
+	if limits.MaxPerTransfer.Valid {
+		rsp.MaxPerTransfer = &limits.MaxPerTransfer.Int64
+	}
+	if limits.DailyPerAccount.Valid {
+		rsp.DailyPerAccount = &limits.DailyPerAccount.Int64
+	}
+	if limits.MonthlyPerAccount.Valid {
+		rsp.MonthlyPerAccount = &limits.MonthlyPerAccount.Int64
+	}
+	if limits.DailyPerUser.Valid {
+		rsp.DailyPerUser = &limits.DailyPerUser.Int64
+	}
+	if limits.MonthlyPerUser.Valid {
+		rsp.MonthlyPerUser = &limits.MonthlyPerUser.Int64
+	}
+	return rsp
+}
@@ -0,0 +1,127 @@
+package gapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antimatter007/go-backend/problems"
+	"github.com/antimatter007/go-backend/requestid"
+)
+
+// corsExposedHeaders are the response headers that browsers let cross-origin scripts read
+var corsExposedHeaders = strings.Join([]string{requestid.Header, "Retry-After"}, ", ")
+
+// CORSPolicy lists what the gateway accepts from cross-origin browser requests
+type CORSPolicy struct {
+	AllowedOrigins   []string      // Origins such as https://app.example.com, or * for any origin
+	AllowedMethods   []string      // Methods that preflight requests may ask for
+	AllowedHeaders   []string      // Request headers that preflight requests may ask for
+	AllowCredentials bool          // Let browsers send cookies and authorization headers, never with the * origin
+	MaxAge           time.Duration // How long browsers may cache the result of a preflight request
+}
+
+func (policy CORSPolicy) allowOrigin(origin string) (string, bool) {
+	for _, allowed := range policy.AllowedOrigins {
+		if allowed == "*" && !policy.AllowCredentials {
+			return "*", true
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+func (policy CORSPolicy) allowMethod(method string) bool {
+	for _, allowed := range policy.AllowedMethods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// allowHeaders reports whether all the headers of a preflight request, separated by commas, are allowed
+func (policy CORSPolicy) allowHeaders(headers string) bool {
+	for _, header := range strings.Split(headers, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		allowed := false
+		for _, allowedHeader := range policy.AllowedHeaders {
+			if strings.EqualFold(allowedHeader, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// HttpCORS answers the preflight requests of the allowed origins, and lets browsers read the responses
+// to their actual requests. Requests without an Origin header, such as those of other services, are not affected.
+// Preflight requests that the policy does not allow are rejected, other requests get no CORS headers
+// so that browsers hide their response.
+func HttpCORS(policy CORSPolicy, handler http.Handler) http.Handler {
+	allowedMethods := strings.Join(policy.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(policy.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(policy.MaxAge.Seconds()))
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		preflight := req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+
+		header := res.Header()
+		header.Add("Vary", "Origin")
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" {
+			handler.ServeHTTP(res, req)
+			return
+		}
+
+		allowedOrigin, ok := policy.allowOrigin(origin)
+		if preflight && (!ok ||
+			!policy.allowMethod(req.Header.Get("Access-Control-Request-Method")) ||
+			!policy.allowHeaders(req.Header.Get("Access-Control-Request-Headers"))) {
+			p := problems.New(problems.CodePermissionDenied, "cross-origin request not allowed")
+			p.Instance = req.URL.Path
+			p.Write(res)
+			return
+		}
+		if !ok {
+			handler.ServeHTTP(res, req)
+			return
+		}
+
+		header.Set("Access-Control-Allow-Origin", allowedOrigin)
+		if policy.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			header.Set("Access-Control-Allow-Methods", allowedMethods)
+			if allowedHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", allowedHeaders)
+			}
+			if policy.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			res.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		handler.ServeHTTP(res, req)
+	})
+}
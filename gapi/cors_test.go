@@ -0,0 +1,184 @@
+package gapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antimatter007/go-backend/problems"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpCORS(t *testing.T) {
+	policy := CORSPolicy{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	testCases := []struct {
+		name          string
+		policy        CORSPolicy
+		setupRequest  func(request *http.Request)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, called bool)
+	}{
+		{
+			name:   "Preflight",
+			policy: policy,
+			setupRequest: func(request *http.Request) {
+				request.Method = http.MethodOptions
+				request.Header.Set("Origin", "https://app.example.com")
+				request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				request.Header.Set("Access-Control-Request-Headers", "content-type, authorization")
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, called bool) {
+				require.False(t, called)
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+				header := recorder.Header()
+				require.Equal(t, "https://app.example.com", header.Get("Access-Control-Allow-Origin"))
+				require.Equal(t, "true", header.Get("Access-Control-Allow-Credentials"))
+				require.Equal(t, "GET, POST", header.Get("Access-Control-Allow-Methods"))
+				require.Equal(t, "Authorization, Content-Type", header.Get("Access-Control-Allow-Headers"))
+				require.Equal(t, "600", header.Get("Access-Control-Max-Age"))
+				require.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+					header.Values("Vary"))
+			},
+		},
+		{
+			name:   "PreflightOriginNotAllowed",
+			policy: policy,
+			setupRequest: func(request *http.Request) {
+				request.Method = http.MethodOptions
+				request.Header.Set("Origin", "https://evil.example.com")
+				request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, called bool) {
+				require.False(t, called)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Equal(t, problems.ContentType, recorder.Header().Get("Content-Type"))
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+			},
+		},
+		{
+			name:   "PreflightMethodNotAllowed",
+			policy: policy,
+			setupRequest: func(request *http.Request) {
+				request.Method = http.MethodOptions
+				request.Header.Set("Origin", "https://app.example.com")
+				request.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, called bool) {
+				require.False(t, called)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "PreflightHeaderNotAllowed",
+			policy: policy,
+			setupRequest: func(request *http.Request) {
+				request.Method = http.MethodOptions
+				request.Header.Set("Origin", "https://app.example.com")
+				request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				request.Header.Set("Access-Control-Request-Headers", "content-type, x-debug")
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, called bool) {
+				require.False(t, called)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "ActualRequest",
+			policy: policy,
+			setupRequest: func(request *http.Request) {
+				request.Header.Set("Origin", "https://APP.example.com")
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, called bool) {
+				require.True(t, called)
+				require.Equal(t, http.StatusOK, recorder.Code)
+				header := recorder.Header()
+				require.Equal(t, "https://APP.example.com", header.Get("Access-Control-Allow-Origin"))
+				require.Equal(t, "true", header.Get("Access-Control-Allow-Credentials"))
+				require.Equal(t, "X-Request-ID, Retry-After", header.Get("Access-Control-Expose-Headers"))
+				require.Empty(t, header.Get("Access-Control-Allow-Methods"))
+				require.Equal(t, []string{"Origin"}, header.Values("Vary"))
+			},
+		},
+		{
+			name:   "ActualRequestOriginNotAllowed",
+			policy: policy,
+			setupRequest: func(request *http.Request) {
+				request.Header.Set("Origin", "https://evil.example.com")
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, called bool) {
+				require.True(t, called)
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"))
+			},
+		},
+		{
+			name:   "NoOrigin",
+			policy: policy,
+			setupRequest: func(request *http.Request) {
+				request.Method = http.MethodOptions
+				request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, called bool) {
+				require.True(t, called)
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+			},
+		},
+		{
+			name: "AnyOrigin",
+			policy: CORSPolicy{
+				AllowedOrigins: []string{"*"},
+				AllowedMethods: []string{http.MethodGet},
+			},
+			setupRequest: func(request *http.Request) {
+				request.Method = http.MethodOptions
+				request.Header.Set("Origin", "https://other.example.com")
+				request.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, called bool) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+				header := recorder.Header()
+				require.Equal(t, "*", header.Get("Access-Control-Allow-Origin"))
+				require.Empty(t, header.Get("Access-Control-Allow-Credentials"))
+				require.Empty(t, header.Get("Access-Control-Allow-Headers"))
+				require.Empty(t, header.Get("Access-Control-Max-Age"))
+			},
+		},
+		{
+			name:   "NoOriginAllowed",
+			policy: CORSPolicy{AllowedMethods: []string{http.MethodGet}},
+			setupRequest: func(request *http.Request) {
+				request.Method = http.MethodOptions
+				request.Header.Set("Origin", "https://app.example.com")
+				request.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, called bool) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := HttpCORS(tc.policy, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				called = true
+			}))
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/v1/login_user", nil)
+			tc.setupRequest(request)
+
+			handler.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, called)
+		})
+	}
+}
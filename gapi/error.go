@@ -1,6 +1,13 @@
 package gapi
 
 import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/antimatter007/go-backend/problems"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -28,3 +35,44 @@ func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 func unauthenticatedError(err error) error {
 	return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
 }
+
+// ErrorHandler writes the errors of the gateway, including its routing errors, as problems.
+// Like runtime.DefaultHTTPErrorHandler, it forwards the header metadata of the failed call.
+func ErrorHandler(
+	ctx context.Context,
+	_ *runtime.ServeMux,
+	_ runtime.Marshaler,
+	res http.ResponseWriter,
+	req *http.Request,
+	err error,
+) {
+	var p *problems.Problem
+	var httpErr *runtime.HTTPStatusError
+	if errors.As(err, &httpErr) {
+		p = problems.FromStatus(status.Convert(httpErr.Err))
+		if p.Status != httpErr.HTTPStatus {
+			p = problems.New(problems.CodeForStatus(httpErr.HTTPStatus), p.Detail)
+			p.Status = httpErr.HTTPStatus
+		}
+	} else {
+		p = problems.FromStatus(status.Convert(err))
+	}
+	p.Instance = req.URL.Path
+	p.RequestID = requestid.FromContext(ctx)
+
+	if p.Internal() {
+		requestid.Logger(ctx).Error().Err(err).Int("status", p.Status).Msg("gateway call failed")
+	}
+
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		for key, values := range md.HeaderMD {
+			if header, ok := OutgoingHeaderMatcher(key); ok {
+				for _, value := range values {
+					res.Header().Add(header, value)
+				}
+			}
+		}
+	}
+
+	p.Write(res)
+}
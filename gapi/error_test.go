@@ -0,0 +1,123 @@
+package gapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antimatter007/go-backend/problems"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	testCases := []struct {
+		name          string
+		ctx           context.Context
+		err           error
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, p problems.Problem)
+	}{
+		{
+			name: "InvalidArgument",
+			ctx:  context.Background(),
+			err: invalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+				fieldViolation("username", errors.New("must contain only letters and digits")),
+			}),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, p problems.Problem) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, problems.CodeInvalidArgument, p.Code)
+				require.Equal(t, "urn:simple-bank:problem:invalid_argument", p.Type)
+				require.Equal(t, "invalid parameters", p.Detail)
+				require.Equal(t, []problems.InvalidParam{
+					{Name: "username", Reason: "must contain only letters and digits"},
+				}, p.InvalidParams)
+			},
+		},
+		{
+			name: "Unauthenticated",
+			ctx:  context.Background(),
+			err:  unauthenticatedError(errors.New("missing metadata")),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, p problems.Problem) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+				require.Equal(t, problems.CodeUnauthenticated, p.Code)
+			},
+		},
+		{
+			name: "InternalDetailHidden",
+			ctx:  requestid.NewContext(context.Background(), "req-1"),
+			err:  status.Errorf(codes.Internal, "failed to create user: connection refused"),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, p problems.Problem) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, problems.CodeInternal, p.Code)
+				require.NotContains(t, recorder.Body.String(), "connection refused")
+				require.Equal(t, "req-1", p.RequestID)
+			},
+		},
+		{
+			name: "RateLimited",
+			ctx: runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{
+				HeaderMD: metadata.Pairs(retryAfterHeader, "3", "x-extra", "1"),
+			}),
+			err: status.Error(codes.ResourceExhausted, "rate limit exceeded"),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, p problems.Problem) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "3", recorder.Header().Get("Retry-After"))
+				require.Equal(t, "1", recorder.Header().Get("Grpc-Metadata-X-Extra"))
+				require.Equal(t, problems.CodeRateLimited, p.Code)
+			},
+		},
+		{
+			name: "MethodNotAllowed",
+			ctx:  context.Background(),
+			err: &runtime.HTTPStatusError{
+				HTTPStatus: http.StatusMethodNotAllowed,
+				Err:        status.Error(codes.Unimplemented, "Method Not Allowed"),
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, p problems.Problem) {
+				require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+				require.Equal(t, problems.CodeMethodNotAllowed, p.Code)
+				require.Equal(t, "Method Not Allowed", p.Detail)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/v1/create_user", nil)
+
+			ErrorHandler(tc.ctx, nil, nil, recorder, request, tc.err)
+			require.Equal(t, problems.ContentType, recorder.Header().Get("Content-Type"))
+
+			var p problems.Problem
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &p))
+			require.Equal(t, recorder.Code, p.Status)
+			require.Equal(t, "/v1/create_user", p.Instance)
+			tc.checkResponse(t, recorder, p)
+		})
+	}
+}
+
+func TestGatewayRoutingError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/v1/unknown", nil)
+	NewGatewayMux().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	require.Equal(t, problems.ContentType, recorder.Header().Get("Content-Type"))
+
+	var p problems.Problem
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &p))
+	require.Equal(t, problems.CodeNotFound, p.Code)
+}
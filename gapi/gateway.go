@@ -0,0 +1,57 @@
+package gapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// NewGatewayMux creates the mux that translates the REST API to the gRPC service.
+// The handlers are registered on it by the caller, according to the gateway mode.
+func NewGatewayMux() *runtime.ServeMux {
+	jsonOption := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames: true,
+		},
+		UnmarshalOptions: protojson.UnmarshalOptions{
+			DiscardUnknown: true,
+		},
+	})
+
+	return runtime.NewServeMux(
+		jsonOption,
+		runtime.WithMetadata(AnnotateRoute),
+		runtime.WithIncomingHeaderMatcher(IncomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(OutgoingHeaderMatcher),
+		runtime.WithErrorHandler(ErrorHandler),
+	)
+}
+
+// OutgoingHeaderMatcher turns the header metadata of gRPC responses into HTTP headers.
+// The retry delay of rate limited calls becomes the Retry-After header, and the request id
+// is left out as HttpRequestID already sets it. Other keys keep the gateway's Grpc-Metadata- prefix.
+func OutgoingHeaderMatcher(key string) (string, bool) {
+	switch strings.ToLower(key) {
+	case retryAfterHeader:
+		return "Retry-After", true
+	case requestid.MetadataKey:
+		return "", false
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// Multiplex serves the gRPC requests with grpcServer and the other requests with handler,
+// so that both share a port. gRPC requires HTTP/2, which the server must negotiate with TLS or h2c.
+func Multiplex(grpcServer *grpc.Server, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.ProtoMajor == 2 && strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(res, req)
+			return
+		}
+		handler.ServeHTTP(res, req)
+	})
+}
@@ -0,0 +1,200 @@
+package gapi
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/problems"
+	"github.com/antimatter007/go-backend/ratelimit"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestGatewayDial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), "alice1").Times(1).Return(db.User{}, db.ErrRecordNotFound)
+
+	// the gateway goes through the interceptors of the gRPC server, including the rate limit
+	server := newTestServer(t, store, nil)
+	limiter := ratelimit.NewLimiter(
+		ratelimit.NewLocalStore(),
+		ratelimit.Rule{Limit: ratelimit.Limit{Requests: 1, Per: time.Minute}, Key: ratelimit.KeyIP},
+		nil,
+	)
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(GrpcRequestID, server.GrpcRateLimit(limiter)))
+	pb.RegisterSimpleBankServer(grpcServer, server)
+
+	// a loopback listener, so that the server trusts the client address forwarded by the gateway
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	grpcMux := NewGatewayMux()
+	err = pb.RegisterSimpleBankHandler(context.Background(), grpcMux, conn)
+	require.NoError(t, err)
+	handler := HttpRequestID(grpcMux)
+
+	login := func(forwardedFor string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		body := strings.NewReader(`{"username": "alice1", "password": "secret123"}`)
+		request := httptest.NewRequest(http.MethodPost, "/v1/login_user", body)
+		request.Header.Set(requestid.Header, "req-1")
+		// clients choosing their forwarded address must not get around the limit of their own address
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := login("198.51.100.1")
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	require.Equal(t, problems.ContentType, recorder.Header().Get("Content-Type"))
+	require.Equal(t, []string{"req-1"}, recorder.Header().Values(requestid.Header))
+	require.Empty(t, recorder.Header().Get("Grpc-Metadata-X-Request-Id"))
+
+	recorder = login("198.51.100.2, 198.51.100.3")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, []string{"60"}, recorder.Header().Values("Retry-After"))
+
+	var p problems.Problem
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &p))
+	require.Equal(t, problems.CodeRateLimited, p.Code)
+	require.Equal(t, "/v1/login_user", p.Instance)
+	require.Equal(t, "req-1", p.RequestID)
+}
+
+func TestMultiplex(t *testing.T) {
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	defer grpcServer.Stop()
+
+	rest := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		io.WriteString(res, "rest")
+	})
+
+	httpServer := httptest.NewUnstartedServer(Multiplex(grpcServer, rest))
+	httpServer.EnableHTTP2 = true
+	httpServer.StartTLS()
+	defer httpServer.Close()
+
+	// gRPC calls are served on the HTTP port
+	roots := httpServer.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	conn, err := grpc.Dial(httpServer.Listener.Addr().String(),
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: roots})))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+
+	// and so are the other requests
+	res, err := httpServer.Client().Get(httpServer.URL + "/v1/accounts")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "rest", string(body))
+}
+
+func TestOutgoingHeaderMatcher(t *testing.T) {
+	key, ok := OutgoingHeaderMatcher("retry-after")
+	require.True(t, ok)
+	require.Equal(t, "Retry-After", key)
+
+	_, ok = OutgoingHeaderMatcher(requestid.MetadataKey)
+	require.False(t, ok)
+
+	key, ok = OutgoingHeaderMatcher("x-custom")
+	require.True(t, ok)
+	require.Equal(t, "Grpc-Metadata-x-custom", key)
+}
+
+func TestExtractMetadataClientIP(t *testing.T) {
+	server := newTestServer(t, nil, nil)
+
+	testCases := []struct {
+		name         string
+		peerAddr     net.Addr
+		forwardedFor string
+		extraValues  []string
+		expectedIP   string
+	}{
+		{
+			name:         "Gateway",
+			peerAddr:     &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5000},
+			forwardedFor: "203.0.113.7",
+			expectedIP:   "203.0.113.7",
+		},
+		{
+			name:         "ClientSuppliedHops",
+			peerAddr:     &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5000},
+			forwardedFor: "198.51.100.1, 10.0.0.9,203.0.113.7",
+			expectedIP:   "203.0.113.7",
+		},
+		{
+			name:         "ClientSuppliedHeaderValues",
+			peerAddr:     &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5000},
+			forwardedFor: "198.51.100.1",
+			extraValues:  []string{"198.51.100.2, 203.0.113.7"},
+			expectedIP:   "203.0.113.7",
+		},
+		{
+			name:         "ForwardedByRemoteCaller",
+			peerAddr:     &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000},
+			forwardedFor: "203.0.113.7",
+			expectedIP:   "10.0.0.1:5000",
+		},
+		{
+			name:       "LoopbackCaller",
+			peerAddr:   &net.TCPAddr{IP: net.IPv6loopback, Port: 5000},
+			expectedIP: "[::1]:5000",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tc.peerAddr})
+			if tc.forwardedFor != "" {
+				md := metadata.Pairs(xForwardedForHeader, tc.forwardedFor)
+				md.Append(xForwardedForHeader, tc.extraValues...)
+				ctx = metadata.NewIncomingContext(ctx, md)
+			}
+
+			require.Equal(t, tc.expectedIP, server.extractMetadata(ctx).ClientIP)
+		})
+	}
+}
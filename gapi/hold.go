@@ -0,0 +1,41 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) getHold(ctx context.Context, id int64) (db.Hold, error) {
+	hold, err := server.store.GetHold(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return hold, status.Errorf(codes.NotFound, "hold not found")
+		}
+		return hold, status.Errorf(codes.Internal, "failed to get hold: %s", err)
+	}
+
+	return hold, nil
+}
+
+// holdError converts the errors of the hold transactions into gRPC errors
+func holdError(err error, action string) error {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return status.Errorf(codes.NotFound, "hold not found")
+	case errors.Is(err, db.ErrInsufficientFunds),
+		errors.Is(err, db.ErrAccountFrozen),
+		errors.Is(err, db.ErrAccountClosed),
+		errors.Is(err, db.ErrTransferLimitExceeded),
+		errors.Is(err, db.ErrHoldNotActive),
+		errors.Is(err, db.ErrHoldExpired):
+		return status.Errorf(codes.FailedPrecondition, "%s", err)
+	case errors.Is(err, db.ErrInvalidCaptureAmount):
+		return status.Errorf(codes.InvalidArgument, "%s", err)
+	default:
+		return status.Errorf(codes.Internal, "failed to %s hold: %s", action, err)
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/rs/zerolog/log"
+	"github.com/antimatter007/go-backend/requestid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -26,9 +26,14 @@ func GrpcLogger(
 		statusCode = st.Code()
 	}
 
-	logger := log.Info()
+	requestLogger := requestid.Logger(ctx)
+	logger := requestLogger.Info()
 	if err != nil {
-		logger = log.Error().Err(err)
+		logger = requestLogger.Error().Err(err)
+	}
+
+	if identity, ok := clientCertificateIdentity(ctx); ok {
+		logger = logger.Str("client_cert", identity)
 	}
 
 	logger.Str("protocol", "grpc").
@@ -67,9 +72,10 @@ func HttpLogger(handler http.Handler) http.Handler {
 		handler.ServeHTTP(rec, req)
 		duration := time.Since(startTime)
 
-		logger := log.Info()
+		requestLogger := requestid.Logger(req.Context())
+		logger := requestLogger.Info()
 		if rec.StatusCode != http.StatusOK {
-			logger = log.Error().Bytes("body", rec.Body)
+			logger = requestLogger.Error().Bytes("body", rec.Body)
 		}
 
 		logger.Str("protocol", "http").
@@ -18,16 +18,17 @@ func newTestServer(t *testing.T, store db.Store, taskDistributor worker.TaskDist
 	config := util.Config{
 		TokenSymmetricKey:   util.RandomString(32),
 		AccessTokenDuration: time.Minute,
+		HoldTTL:             time.Hour,
 	}
 
-	server, err := NewServer(config, store, taskDistributor)
+	server, err := NewServer(config, store, taskDistributor, nil)
 	require.NoError(t, err)
 
 	return server
 }
 
-func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username string, duration time.Duration) context.Context {
-	accessToken, _, err := tokenMaker.CreateToken(username, duration)
+func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username string, role string, duration time.Duration) context.Context {
+	accessToken, _, err := tokenMaker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 
 	bearerToken := fmt.Sprintf("%s %s", authorizationBearer, accessToken)
@@ -2,6 +2,8 @@ package gapi
 
 import (
 	"context"
+	"net"
+	"strings"
 
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
@@ -30,14 +32,29 @@ func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 			mtdt.UserAgent = userAgents[0]
 		}
 
-		if clientIPs := md.Get(xForwardedForHeader); len(clientIPs) > 0 {
-			mtdt.ClientIP = clientIPs[0]
+		// the gateway appends the address of its HTTP client as the last hop,
+		// the hops before it come from the client and cannot be trusted
+		if forwardedFor := md.Get(xForwardedForHeader); len(forwardedFor) > 0 {
+			hops := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+			mtdt.ClientIP = strings.TrimSpace(hops[len(hops)-1])
 		}
 	}
 
-	if p, ok := peer.FromContext(ctx); ok {
+	// the gateway forwards the address of its HTTP clients when it dials the server over loopback,
+	// other callers cannot be trusted with it
+	if p, ok := peer.FromContext(ctx); ok && (mtdt.ClientIP == "" || !isLoopback(p.Addr)) {
 		mtdt.ClientIP = p.Addr.String()
 	}
 
 	return mtdt
 }
+
+func isLoopback(addr net.Addr) bool {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,174 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var grpcRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "Number of gRPC requests handled, by method and status code.",
+	},
+	[]string{"method", "code"},
+)
+
+var grpcRequestDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "simple_bank",
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to handle gRPC requests, by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"method", "code"},
+)
+
+var httpRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP gateway requests handled, by method, route and status code.",
+	},
+	[]string{"method", "route", "code"},
+)
+
+var httpRequestDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "simple_bank",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to handle HTTP gateway requests, by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"method", "route", "code"},
+)
+
+var loginAttempts = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "auth",
+		Name:      "logins_total",
+		Help:      "Number of login attempts, by result and reason of failure.",
+	},
+	[]string{"result", "reason"},
+)
+
+// Reasons a login failed, as recorded by loginAttempts
+const (
+	loginFailedInvalidArgument = "invalid_argument"
+	loginFailedUserNotFound    = "user_not_found"
+	loginFailedWrongPassword   = "wrong_password"
+	loginFailedInternal        = "internal"
+)
+
+func recordLoginSucceeded() {
+	loginAttempts.WithLabelValues("succeeded", "").Inc()
+}
+
+func recordLoginFailed(reason string) {
+	loginAttempts.WithLabelValues("failed", reason).Inc()
+}
+
+// GrpcMetrics counts the gRPC requests and observes how long they take.
+// The method label is bounded by the service definition.
+func GrpcMetrics(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+	startTime := time.Now()
+	result, err := handler(ctx, req)
+	duration := time.Since(startTime)
+
+	code := status.Code(err).String()
+	grpcRequests.WithLabelValues(info.FullMethod, code).Inc()
+	grpcRequestDuration.WithLabelValues(info.FullMethod, code).Observe(duration.Seconds())
+
+	return result, err
+}
+
+// Route labels of the requests that no gateway pattern matched
+const (
+	routeSwagger   = "/swagger/"
+	routeUnmatched = "unmatched"
+)
+
+type routeKey struct{}
+
+// route is filled in by AnnotateRoute once the gateway has matched the request
+type route struct {
+	pattern string
+}
+
+// AnnotateRoute records the path pattern matched by the gateway, such as /v1/accounts/{id},
+// so that HttpMetrics labels requests and the request span is named by route instead of by raw path.
+// It is registered with runtime.WithMetadata and adds no metadata.
+func AnnotateRoute(ctx context.Context, req *http.Request) metadata.MD {
+	pattern, ok := runtime.HTTPPathPattern(ctx)
+	if !ok {
+		return nil
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetName(req.Method + " " + pattern)
+	span.SetAttributes(semconv.HTTPRoute(pattern))
+
+	if rt, ok := req.Context().Value(routeKey{}).(*route); ok {
+		rt.pattern = pattern
+	}
+	return nil
+}
+
+// HttpMetrics counts the gateway requests and observes how long they take
+func HttpMetrics(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		startTime := time.Now()
+		rt := &route{}
+		rec := &ResponseRecorder{
+			ResponseWriter: res,
+			StatusCode:     http.StatusOK,
+		}
+		handler.ServeHTTP(rec, req.WithContext(context.WithValue(req.Context(), routeKey{}, rt)))
+		duration := time.Since(startTime)
+
+		pattern := rt.pattern
+		if pattern == "" {
+			pattern = routeUnmatched
+			if strings.HasPrefix(req.URL.Path, routeSwagger) {
+				pattern = routeSwagger
+			}
+		}
+
+		method := methodLabel(req.Method)
+		code := strconv.Itoa(rec.StatusCode)
+		httpRequests.WithLabelValues(method, pattern, code).Inc()
+		httpRequestDuration.WithLabelValues(method, pattern, code).Observe(duration.Seconds())
+	})
+}
+
+// methodLabel keeps the method label bounded, as clients can send any method name
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "other"
+}
@@ -0,0 +1,161 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/golang/mock/gomock"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHttpMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), "alice1").Times(1).Return(db.User{}, db.ErrRecordNotFound)
+
+	server := newTestServer(t, store, nil)
+	grpcMux := runtime.NewServeMux(runtime.WithMetadata(AnnotateRoute))
+	err := pb.RegisterSimpleBankHandlerServer(context.Background(), grpcMux, server)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", grpcMux)
+	handler := HttpMetrics(mux)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		route  string
+		code   string
+	}{
+		{
+			name:   "MatchedRoute",
+			method: http.MethodPost,
+			path:   "/v1/login_user",
+			body:   `{"username": "alice1", "password": "secret123"}`,
+			route:  "/v1/login_user",
+			code:   "404",
+		},
+		{
+			name:   "UnmatchedRoute",
+			method: http.MethodGet,
+			path:   "/v1/no_such_route/123",
+			route:  routeUnmatched,
+			code:   "404",
+		},
+		{
+			name:   "UnknownMethod",
+			method: "PURGE",
+			path:   "/v1/login_user",
+			route:  routeUnmatched,
+			code:   "501",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			counter := httpRequests.WithLabelValues(methodLabel(tc.method), tc.route, tc.code)
+			before := testutil.ToFloat64(counter)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			handler.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.code, strconv.Itoa(recorder.Code))
+			require.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
+
+func TestGrpcMetrics(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.SimpleBank/LoginUser"}
+
+	testCases := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{
+			name: "OK",
+			code: codes.OK.String(),
+		},
+		{
+			name: "StatusError",
+			err:  status.Error(codes.NotFound, "user not found"),
+			code: codes.NotFound.String(),
+		},
+		{
+			name: "PlainError",
+			err:  errors.New("boom"),
+			code: codes.Unknown.String(),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			counter := grpcRequests.WithLabelValues(info.FullMethod, tc.code)
+			before := testutil.ToFloat64(counter)
+
+			_, err := GrpcMetrics(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, tc.err
+			})
+			require.Equal(t, tc.err, err)
+			require.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
+
+func TestAnnotateRouteNamesSpan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), "alice1").Times(1).Return(db.User{}, db.ErrRecordNotFound)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	server := newTestServer(t, store, nil)
+	grpcMux := runtime.NewServeMux(runtime.WithMetadata(AnnotateRoute))
+	err := pb.RegisterSimpleBankHandlerServer(context.Background(), grpcMux, server)
+	require.NoError(t, err)
+
+	handler := otelhttp.NewHandler(HttpMetrics(grpcMux), "gateway", otelhttp.WithTracerProvider(provider))
+
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader(`{"username": "alice1", "password": "secret123"}`)
+	request := httptest.NewRequest(http.MethodPost, "/v1/login_user", body)
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "POST /v1/login_user", spans[0].Name)
+	require.Contains(t, spans[0].Attributes, semconv.HTTPRoute("/v1/login_user"))
+}
@@ -0,0 +1,100 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"path"
+	"strconv"
+
+	"github.com/antimatter007/go-backend/ratelimit"
+	"github.com/antimatter007/go-backend/requestid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	apiKeyHeader     = "x-api-key"
+	retryAfterHeader = "retry-after"
+)
+
+// GrpcRateLimit rejects the calls of clients that exceed the limit of the method with ResourceExhausted.
+// Limits are looked up by the short name of the method, such as LoginUser.
+// Calls are let through if the limiter fails, rate limiting is not worth an outage.
+func (server *Server) GrpcRateLimit(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		result, err := limiter.Allow(ctx, path.Base(info.FullMethod), server.rateLimitIdentity(ctx))
+		if err != nil {
+			requestid.Logger(ctx).Error().Err(err).Msg("cannot apply rate limit")
+			return handler(ctx, req)
+		}
+
+		if !result.Allowed {
+			retryAfter := retryAfterSeconds(result)
+			err = grpc.SetHeader(ctx, metadata.Pairs(retryAfterHeader, strconv.Itoa(retryAfter)))
+			if err != nil {
+				requestid.Logger(ctx).Warn().Err(err).Msg("cannot set retry-after header")
+			}
+			return nil, rateLimitExceededError(result)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitIdentity identifies the caller by client IP, and by the username of its access token
+// or its issued API key when the rule of the method counts requests by them.
+func (server *Server) rateLimitIdentity(ctx context.Context) ratelimit.Identity {
+	identity := ratelimit.Identity{
+		ClientIP: clientIP(server.extractMetadata(ctx).ClientIP),
+	}
+
+	if payload, err := server.verifyAccessToken(ctx); err == nil {
+		identity.Username = payload.Username
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if apiKeys := md.Get(apiKeyHeader); len(apiKeys) > 0 {
+			identity.APIKey = server.apiKeys.Authenticate(apiKeys[0])
+		}
+	}
+
+	return identity
+}
+
+// clientIP removes the port from the peer address, so that all the connections of a client share a bucket
+func clientIP(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+// retryAfterSeconds rounds up the time to wait before retrying to whole seconds, as in the Retry-After HTTP header
+func retryAfterSeconds(result ratelimit.Result) int {
+	return int(math.Ceil(result.RetryAfter.Seconds()))
+}
+
+func rateLimitExceededError(result ratelimit.Result) error {
+	statusExhausted := status.New(codes.ResourceExhausted,
+		fmt.Sprintf("rate limit exceeded: retry in %ds", retryAfterSeconds(result)))
+
+	statusDetails, err := statusExhausted.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(result.RetryAfter),
+	})
+	if err != nil {
+		return statusExhausted.Err()
+	}
+
+	return statusDetails.Err()
+}
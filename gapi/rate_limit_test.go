@@ -0,0 +1,89 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/antimatter007/go-backend/ratelimit"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcRateLimit(t *testing.T) {
+	server := newTestServer(t, nil, nil)
+	limiter := ratelimit.NewLimiter(
+		ratelimit.NewLocalStore(),
+		ratelimit.Rule{Limit: ratelimit.Limit{Requests: 1, Per: time.Minute}, Key: ratelimit.KeyUser},
+		map[string]ratelimit.Rule{
+			"LoginUser": {Limit: ratelimit.Limit{Requests: 1, Per: time.Minute}, Key: ratelimit.KeyIP},
+		},
+	)
+	interceptor := server.GrpcRateLimit(limiter)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	call := func(ctx context.Context, method string) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pb.SimpleBank/" + method}, handler)
+		return err
+	}
+	fromPeer := func(ctx context.Context, port int) context.Context {
+		return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: port}})
+	}
+
+	// LoginUser is counted by client IP, whatever the connection
+	require.NoError(t, call(fromPeer(context.Background(), 5000), "LoginUser"))
+	err := call(fromPeer(context.Background(), 5001), "LoginUser")
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	require.Positive(t, retryInfo.RetryDelay.AsDuration())
+
+	// the other methods are counted by user
+	alice := newContextWithBearerToken(t, server.tokenMaker, "alice", util.DepositorRole, time.Minute)
+	bob := newContextWithBearerToken(t, server.tokenMaker, "bob", util.DepositorRole, time.Minute)
+
+	require.NoError(t, call(fromPeer(alice, 5000), "ListAccounts"))
+	require.NoError(t, call(fromPeer(bob, 5000), "ListAccounts"))
+	err = call(fromPeer(alice, 5000), "ListAccounts")
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// anonymous callers are counted by client IP
+	require.NoError(t, call(fromPeer(context.Background(), 5000), "ListAccounts"))
+	err = call(fromPeer(context.Background(), 5000), "ListAccounts")
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimitIdentity(t *testing.T) {
+	server := newTestServer(t, nil, nil)
+	server.apiKeys = ratelimit.NewAPIKeys([]string{"key-1"})
+
+	ctx := newContextWithBearerToken(t, server.tokenMaker, "alice", util.DepositorRole, time.Minute)
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = metadata.Join(md, metadata.Pairs(apiKeyHeader, "key-1", xForwardedForHeader, "10.0.0.2"))
+	ctx = metadata.NewIncomingContext(context.Background(), md)
+
+	identity := server.rateLimitIdentity(ctx)
+	require.Equal(t, ratelimit.Identity{ClientIP: "10.0.0.2", Username: "alice", APIKey: "key-1"}, identity)
+
+	// unknown API keys are counted by client IP
+	md.Set(apiKeyHeader, "key-2")
+	ctx = metadata.NewIncomingContext(context.Background(), md)
+	require.Empty(t, server.rateLimitIdentity(ctx).APIKey)
+
+	// expired tokens do not identify a user
+	ctx = newContextWithBearerToken(t, server.tokenMaker, "alice", util.DepositorRole, -time.Minute)
+	require.Empty(t, server.rateLimitIdentity(ctx).Username)
+}
@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// GrpcRequestID takes the request id from the incoming metadata, or generates one,
+// puts it in the context of the call and sends it back in the response header.
+func GrpcRequestID(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+	id := requestid.FromContext(ctx)
+	if id == "" {
+		id = requestid.New()
+	}
+	ctx = requestid.NewContext(ctx, id)
+
+	err = grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, id))
+	if err != nil {
+		log.Warn().Err(err).Str("request_id", id).Msg("cannot set request id header")
+	}
+
+	return handler(ctx, req)
+}
+
+// HttpRequestID takes the X-Request-ID header of the request, or generates one, and echoes it in the response.
+// The header is forwarded to the handlers as gRPC metadata by IncomingHeaderMatcher.
+func HttpRequestID(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		req.Header.Set(requestid.Header, id)
+		res.Header().Set(requestid.Header, id)
+		handler.ServeHTTP(res, req.WithContext(requestid.NewContext(req.Context(), id)))
+	})
+}
+
+// IncomingHeaderMatcher forwards the request id header to gRPC metadata,
+// along with the headers that the gateway forwards by default.
+// It is registered with runtime.WithIncomingHeaderMatcher.
+func IncomingHeaderMatcher(key string) (string, bool) {
+	if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(requestid.Header) {
+		return requestid.MetadataKey, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -0,0 +1,99 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestHttpRequestID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		header     string
+		expectEcho bool
+	}{
+		{
+			name:       "ValidHeader",
+			header:     "req-123",
+			expectEcho: true,
+		},
+		{
+			name:   "NoHeader",
+			header: "",
+		},
+		{
+			name:   "InvalidHeader",
+			header: "req 123\r\n",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var ctxID, headerID string
+			handler := HttpRequestID(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				ctxID = requestid.FromContext(req.Context())
+				headerID = req.Header.Get(requestid.Header)
+			}))
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/v1/accounts", nil)
+			if tc.header != "" {
+				request.Header.Set(requestid.Header, tc.header)
+			}
+			handler.ServeHTTP(recorder, request)
+
+			id := recorder.Header().Get(requestid.Header)
+			require.True(t, requestid.Valid(id))
+			require.Equal(t, id, ctxID)
+			require.Equal(t, id, headerID)
+			if tc.expectEcho {
+				require.Equal(t, tc.header, id)
+			} else {
+				require.NotEqual(t, tc.header, id)
+			}
+		})
+	}
+}
+
+func TestGrpcRequestID(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.SimpleBank/GetAccount"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return requestid.FromContext(ctx), nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.MetadataKey, "req-123"))
+	id, err := GrpcRequestID(ctx, nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "req-123", id)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.MetadataKey, "req 123"))
+	id, err = GrpcRequestID(ctx, nil, info, handler)
+	require.NoError(t, err)
+	require.NotEqual(t, "req 123", id)
+	require.True(t, requestid.Valid(id.(string)))
+}
+
+func TestIncomingHeaderMatcher(t *testing.T) {
+	key, ok := IncomingHeaderMatcher("x-request-id")
+	require.True(t, ok)
+	require.Equal(t, requestid.MetadataKey, key)
+
+	key, ok = IncomingHeaderMatcher(requestid.Header)
+	require.True(t, ok)
+	require.Equal(t, requestid.MetadataKey, key)
+
+	key, ok = IncomingHeaderMatcher("Authorization")
+	require.True(t, ok)
+	require.Equal(t, "grpcgateway-Authorization", key)
+
+	_, ok = IncomingHeaderMatcher("X-Custom")
+	require.False(t, ok)
+}
@@ -0,0 +1,102 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) AuthorizeHold(ctx context.Context, req *pb.AuthorizeHoldRequest) (*pb.AuthorizeHoldResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	now := time.Now()
+	violations := validateAuthorizeHoldRequest(req, now, server.config.HoldTTL)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.getOwnAccount(ctx, req.GetFromAccountId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	toAccount, err := server.getAccount(ctx, req.GetToAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, account := range []db.Account{fromAccount, toAccount} {
+		if account.Currency != req.GetCurrency() {
+			return nil, status.Errorf(codes.InvalidArgument, "account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, req.GetCurrency())
+		}
+	}
+
+	expiresAt := now.Add(server.config.HoldTTL)
+	if req.ExpiresAt != nil {
+		expiresAt = req.GetExpiresAt().AsTime()
+	}
+
+	hold, err := server.store.AuthorizeHoldTx(ctx, db.AuthorizeHoldTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        req.GetAmount(),
+		ExpiresAt:     expiresAt,
+	})
+	if err != nil {
+		return nil, holdError(err, "authorize")
+	}
+
+	account, err := server.accountResponse(ctx, fromAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.AuthorizeHoldResponse{
+		Hold:    convertHold(hold, fromAccount.Currency),
+		Account: account,
+	}
+	return rsp, nil
+}
+
+func validateAuthorizeHoldRequest(req *pb.AuthorizeHoldRequest, now time.Time, maxTTL time.Duration) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	} else if req.GetToAccountId() == req.GetFromAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("must differ from from_account_id")))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolation("amount", err))
+		}
+	} else if err := val.ValidateMoney(req.GetAmount(), req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if req.ExpiresAt != nil {
+		expiresAt := req.GetExpiresAt().AsTime()
+		if !expiresAt.After(now) {
+			violations = append(violations, fieldViolation("expires_at", fmt.Errorf("must be in the future")))
+		} else if expiresAt.After(now.Add(maxTTL)) {
+			violations = append(violations, fieldViolation("expires_at", fmt.Errorf("must be within %s", maxTTL)))
+		}
+	}
+
+	return violations
+}
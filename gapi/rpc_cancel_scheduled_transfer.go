@@ -0,0 +1,43 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) CancelScheduledTransfer(ctx context.Context, req *pb.CancelScheduledTransferRequest) (*pb.CancelScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCancelScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	scheduledTransfer, err := server.changeScheduledTransferStatus(ctx, req.GetScheduledTransferId(), authPayload.Username,
+		[]string{db.ScheduledTransferActive, db.ScheduledTransferPaused}, db.ScheduledTransferCancelled, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.CancelScheduledTransferResponse{
+		ScheduledTransfer: scheduledTransfer,
+	}
+	return rsp, nil
+}
+
+func validateCancelScheduledTransferRequest(req *pb.CancelScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetScheduledTransferId()); err != nil {
+		violations = append(violations, fieldViolation("scheduled_transfer_id", err))
+	}
+
+	return violations
+}
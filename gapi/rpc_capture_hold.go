@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// CaptureHold settles a hold. Only the owner of the destination account, who is being paid, may capture it.
+func (server *Server) CaptureHold(ctx context.Context, req *pb.CaptureHoldRequest) (*pb.CaptureHoldResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCaptureHoldRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	hold, err := server.getHold(ctx, req.GetHoldId())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.getOwnAccount(ctx, hold.ToAccountID, authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	// capture the full amount unless told otherwise
+	amount := hold.Amount
+	if req.Amount != nil {
+		amount = req.GetAmount()
+	}
+
+	result, err := server.store.CaptureHoldTx(ctx, db.CaptureHoldTxParams{
+		ID:     hold.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		return nil, holdError(err, "capture")
+	}
+
+	server.announceTransfer(ctx, result.Transfer)
+
+	account, err := server.accountResponse(ctx, result.Transfer.ToAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.CaptureHoldResponse{
+		Hold:    convertHold(result.Hold, result.Transfer.ToAccount.Currency),
+		Account: account,
+	}
+	return rsp, nil
+}
+
+func validateCaptureHoldRequest(req *pb.CaptureHoldRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetHoldId()); err != nil {
+		violations = append(violations, fieldViolation("hold_id", err))
+	}
+
+	if req.Amount != nil {
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolation("amount", err))
+		}
+	}
+
+	return violations
+}
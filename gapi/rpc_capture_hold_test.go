@@ -0,0 +1,196 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	mockwk "github.com/antimatter007/go-backend/worker/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCaptureHoldAPI(t *testing.T) {
+	payer, _ := randomUser(t)
+	payee, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: util.RandomInt(1, 1000), Owner: payer.Username, Currency: util.USD}
+	toAccount := db.Account{ID: fromAccount.ID + 1, Owner: payee.Username, Currency: util.USD}
+
+	hold := db.Hold{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        100,
+		Status:        db.HoldActive,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CaptureHoldRequest
+		buildStubs    func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.CaptureHoldResponse, err error)
+	}{
+		{
+			name: "PartialCapture",
+			req: &pb.CaptureHoldRequest{
+				HoldId: hold.ID,
+				Amount: proto.Int64(60),
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				arg := db.CaptureHoldTxParams{ID: hold.ID, Amount: 60}
+				captured := hold
+				captured.Status = db.HoldCaptured
+				captured.CapturedAmount = 60
+				captured.TransferID = pgtype.Int8{Int64: 7, Valid: true}
+				paid := toAccount
+				paid.Balance = 60
+				store.EXPECT().
+					CaptureHoldTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CaptureHoldTxResult{
+						Hold: captured,
+						Transfer: db.TransferTxResult{
+							Transfer:    db.Transfer{ID: 7, FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 60},
+							FromAccount: fromAccount,
+							ToAccount:   paid,
+						},
+					}, nil)
+				store.EXPECT().GetHeldAmount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(int64(25), nil)
+
+				taskDistributor.EXPECT().DistributeTaskSendTransferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				taskDistributor.EXPECT().DistributeTaskPublishWebhookEvent(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, payee.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.HoldCaptured, res.GetHold().GetStatus())
+				require.Equal(t, int64(60), res.GetHold().GetCapturedAmount())
+				require.Equal(t, "1.00", res.GetHold().GetAmountDecimal())
+				require.Equal(t, "0.60", res.GetHold().GetCapturedAmountDecimal())
+				require.Equal(t, toAccount.ID, res.GetAccount().GetId())
+				require.Equal(t, int64(60), res.GetAccount().GetBalance())
+				require.Equal(t, int64(35), res.GetAccount().GetAvailableBalance())
+				require.Equal(t, "0.35", res.GetAccount().GetAvailableBalanceDecimal())
+				require.Equal(t, int64(7), res.GetHold().GetTransferId())
+			},
+		},
+		{
+			name: "FullCapture",
+			req: &pb.CaptureHoldRequest{
+				HoldId: hold.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				arg := db.CaptureHoldTxParams{ID: hold.ID, Amount: hold.Amount}
+				store.EXPECT().
+					CaptureHoldTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CaptureHoldTxResult{Hold: hold, Transfer: db.TransferTxResult{ToAccount: toAccount}}, nil)
+				store.EXPECT().GetHeldAmount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(int64(0), nil)
+
+				taskDistributor.EXPECT().DistributeTaskSendTransferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				taskDistributor.EXPECT().DistributeTaskPublishWebhookEvent(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, payee.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "NotPayee",
+			req: &pb.CaptureHoldRequest{
+				HoldId: hold.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, payer.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "HoldExpired",
+			req: &pb.CaptureHoldRequest{
+				HoldId: hold.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, db.ErrHoldExpired)
+				taskDistributor.EXPECT().DistributeTaskSendTransferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, payee.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "InvalidAmount",
+			req: &pb.CaptureHoldRequest{
+				HoldId: hold.ID,
+				Amount: proto.Int64(0),
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, payee.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			taskDistributor := mockwk.NewMockTaskDistributor(ctrl)
+
+			tc.buildStubs(store, taskDistributor)
+			server := newTestServer(t, store, taskDistributor)
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.CaptureHold(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+)
+
+// CloseAccount closes an account of the user for good. Its balance must be zero.
+func (server *Server) CloseAccount(ctx context.Context, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateAccountStatusRequest(req.GetAccountId(), req.GetReason(), false)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.getOwnAccount(ctx, req.GetAccountId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := server.changeAccountStatus(ctx, req.GetAccountId(), db.AccountClosed, req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.CloseAccountResponse{
+		Account: account,
+	}
+	return rsp, nil
+}
@@ -0,0 +1,133 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCloseAccountAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	account := db.Account{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    user.Username,
+		Currency: util.USD,
+		Status:   db.AccountActive,
+	}
+	closed := account
+	closed.Status = db.AccountClosed
+
+	testCases := []struct {
+		name          string
+		req           *pb.CloseAccountRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.CloseAccountResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.CloseAccountRequest{
+				AccountId: account.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.ChangeAccountStatusTxParams{
+					ID:     account.ID,
+					Status: db.AccountClosed,
+				}
+				store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(closed, nil)
+				store.EXPECT().GetHeldAmount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(int64(0), nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.AccountClosed, res.GetAccount().GetStatus())
+			},
+		},
+		{
+			name: "BalanceNotZero",
+			req: &pb.CloseAccountRequest{
+				AccountId: account.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrAccountBalanceNotZero)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "NotOwner",
+			req: &pb.CloseAccountRequest{
+				AccountId: account.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "InvalidAccountID",
+			req: &pb.CloseAccountRequest{
+				AccountId: 0,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStubs(store)
+			server := newTestServer(t, store, nil)
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.CloseAccount(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBps is the largest rate in basis points that an account product accepts, 100%
+const maxBps = 10000
+
+// CreateAccountProduct adds a product that customers can open accounts with
+func (server *Server) CreateAccountProduct(ctx context.Context, req *pb.CreateAccountProductRequest) (*pb.CreateAccountProductResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateAccountProductRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	product, err := server.store.CreateAccountProduct(ctx, db.CreateAccountProductParams{
+		Name:            req.GetName(),
+		InterestRateBps: req.GetInterestRateBps(),
+		TransferFeeFlat: req.GetTransferFeeFlat(),
+		TransferFeeBps:  req.GetTransferFeeBps(),
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			return nil, status.Errorf(codes.AlreadyExists, "account product %q already exists", req.GetName())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account product: %s", err)
+	}
+
+	rsp := &pb.CreateAccountProductResponse{
+		Product: convertAccountProduct(product),
+	}
+	return rsp, nil
+}
+
+func validateCreateAccountProductRequest(req *pb.CreateAccountProductRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateString(req.GetName(), 1, 50); err != nil {
+		violations = append(violations, fieldViolation("name", err))
+	}
+
+	if req.GetInterestRateBps() < 0 || req.GetInterestRateBps() > maxBps {
+		violations = append(violations, fieldViolation("interest_rate_bps", fmt.Errorf("must be between 0 and %d", maxBps)))
+	}
+
+	if req.GetTransferFeeFlat() < 0 {
+		violations = append(violations, fieldViolation("transfer_fee_flat", fmt.Errorf("must not be negative")))
+	}
+
+	if req.GetTransferFeeBps() < 0 || req.GetTransferFeeBps() > maxBps {
+		violations = append(violations, fieldViolation("transfer_fee_bps", fmt.Errorf("must be between 0 and %d", maxBps)))
+	}
+
+	return violations
+}
@@ -0,0 +1,136 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateAccountProductAPI(t *testing.T) {
+	product := db.AccountProduct{
+		ID:              util.RandomInt(1, 1000),
+		Name:            "savings",
+		InterestRateBps: 250,
+		TransferFeeFlat: 30,
+		TransferFeeBps:  10,
+		CreatedAt:       time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateAccountProductRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.CreateAccountProductResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.CreateAccountProductRequest{
+				Name:            product.Name,
+				InterestRateBps: product.InterestRateBps,
+				TransferFeeFlat: product.TransferFeeFlat,
+				TransferFeeBps:  product.TransferFeeBps,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateAccountProductParams{
+					Name:            product.Name,
+					InterestRateBps: product.InterestRateBps,
+					TransferFeeFlat: product.TransferFeeFlat,
+					TransferFeeBps:  product.TransferFeeBps,
+				}
+				store.EXPECT().CreateAccountProduct(gomock.Any(), gomock.Eq(arg)).Times(1).Return(product, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountProductResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, product.ID, res.GetProduct().GetId())
+				require.Equal(t, product.InterestRateBps, res.GetProduct().GetInterestRateBps())
+				require.Equal(t, product.TransferFeeFlat, res.GetProduct().GetTransferFeeFlat())
+				require.Equal(t, product.TransferFeeBps, res.GetProduct().GetTransferFeeBps())
+			},
+		},
+		{
+			name: "AlreadyExists",
+			req: &pb.CreateAccountProductRequest{
+				Name: product.Name,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountProduct(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountProduct{}, db.ErrUniqueViolation)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountProductResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.AlreadyExists, st.Code())
+			},
+		},
+		{
+			name: "InvalidRates",
+			req: &pb.CreateAccountProductRequest{
+				Name:            product.Name,
+				InterestRateBps: -1,
+				TransferFeeFlat: -1,
+				TransferFeeBps:  10001,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountProductResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "Banker",
+			req: &pb.CreateAccountProductRequest{
+				Name: product.Name,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountProductResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStubs(store)
+			server := newTestServer(t, store, nil)
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.CreateAccountProduct(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
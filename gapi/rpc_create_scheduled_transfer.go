@@ -0,0 +1,118 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// firstRunAtSkew is how far in the past first_run_at may be, to allow for clock skew of the client
+const firstRunAtSkew = time.Minute
+
+func (server *Server) CreateScheduledTransfer(ctx context.Context, req *pb.CreateScheduledTransferRequest) (*pb.CreateScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateScheduledTransferRequest(req, time.Now())
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.getOwnAccount(ctx, req.GetFromAccountId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	toAccount, err := server.getAccount(ctx, req.GetToAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, account := range []db.Account{fromAccount, toAccount} {
+		if account.Currency != req.GetCurrency() {
+			return nil, status.Errorf(codes.InvalidArgument, "account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, req.GetCurrency())
+		}
+	}
+
+	firstRunAt := req.GetFirstRunAt().AsTime()
+	arg := db.CreateScheduledTransferParams{
+		Owner:         authPayload.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        req.GetAmount(),
+		Recurrence:    req.GetRecurrence(),
+		FirstRunAt:    firstRunAt,
+		NextRunAt: pgtype.Timestamptz{
+			Time:  firstRunAt,
+			Valid: true,
+		},
+	}
+	if req.EndAt != nil {
+		arg.EndAt = pgtype.Timestamptz{
+			Time:  req.GetEndAt().AsTime(),
+			Valid: true,
+		}
+	}
+
+	scheduledTransfer, err := server.store.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create scheduled transfer: %s", err)
+	}
+
+	rsp := &pb.CreateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer, fromAccount.Currency),
+	}
+	return rsp, nil
+}
+
+func validateCreateScheduledTransferRequest(req *pb.CreateScheduledTransferRequest, now time.Time) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	} else if req.GetToAccountId() == req.GetFromAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("must differ from from_account_id")))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolation("amount", err))
+		}
+	} else if err := val.ValidateMoney(req.GetAmount(), req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateRecurrence(req.GetRecurrence()); err != nil {
+		violations = append(violations, fieldViolation("recurrence", err))
+	}
+
+	if req.FirstRunAt == nil {
+		violations = append(violations, fieldViolation("first_run_at", fmt.Errorf("must be set")))
+	} else if req.GetFirstRunAt().AsTime().Before(now.Add(-firstRunAtSkew)) {
+		violations = append(violations, fieldViolation("first_run_at", fmt.Errorf("must not be in the past")))
+	}
+
+	if req.EndAt != nil {
+		if req.GetRecurrence() == util.RecurrenceOnce {
+			violations = append(violations, fieldViolation("end_at", fmt.Errorf("must not be set for a one-off transfer")))
+		} else if req.FirstRunAt != nil && req.GetEndAt().AsTime().Before(req.GetFirstRunAt().AsTime()) {
+			violations = append(violations, fieldViolation("end_at", fmt.Errorf("must not be before first_run_at")))
+		}
+	}
+
+	return violations
+}
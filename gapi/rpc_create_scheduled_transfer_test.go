@@ -0,0 +1,219 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreateScheduledTransferAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	otherUser, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: util.RandomInt(1, 1000), Owner: user.Username, Currency: util.USD}
+	toAccount := db.Account{ID: fromAccount.ID + 1, Owner: otherUser.Username, Currency: util.USD}
+	eurAccount := db.Account{ID: fromAccount.ID + 2, Owner: otherUser.Username, Currency: util.EUR}
+
+	amount := util.RandomMoney()
+	firstRunAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateScheduledTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Recurrence:    util.RecurrenceMonthly,
+				FirstRunAt:    timestamppb.New(firstRunAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					CreateScheduledTransfer(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateScheduledTransferParams) (db.ScheduledTransfer, error) {
+						require.Equal(t, user.Username, arg.Owner)
+						require.Equal(t, amount, arg.Amount)
+						require.True(t, firstRunAt.Equal(arg.FirstRunAt))
+						require.True(t, arg.NextRunAt.Valid)
+						require.True(t, firstRunAt.Equal(arg.NextRunAt.Time))
+						require.False(t, arg.EndAt.Valid)
+						return db.ScheduledTransfer{
+							ID:            1,
+							Owner:         arg.Owner,
+							FromAccountID: arg.FromAccountID,
+							ToAccountID:   arg.ToAccountID,
+							Amount:        arg.Amount,
+							Recurrence:    arg.Recurrence,
+							Status:        db.ScheduledTransferActive,
+							FirstRunAt:    arg.FirstRunAt,
+							NextRunAt:     arg.NextRunAt,
+						}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+				scheduledTransfer := res.GetScheduledTransfer()
+				require.Equal(t, db.ScheduledTransferActive, scheduledTransfer.GetStatus())
+				require.Equal(t, util.FormatAmount(amount, util.USD), scheduledTransfer.GetAmountDecimal())
+				require.True(t, firstRunAt.Equal(scheduledTransfer.GetNextRunAt().AsTime()))
+				require.Nil(t, scheduledTransfer.GetEndAt())
+			},
+		},
+		{
+			name: "FromAccountOfOtherUser",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Recurrence:    util.RecurrenceOnce,
+				FirstRunAt:    timestamppb.New(firstRunAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, otherUser.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   eurAccount.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Recurrence:    util.RecurrenceOnce,
+				FirstRunAt:    timestamppb.New(firstRunAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(eurAccount.ID)).Times(1).Return(eurAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "FirstRunAtInPast",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Recurrence:    util.RecurrenceDaily,
+				FirstRunAt:    timestamppb.New(time.Now().Add(-time.Hour)),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidRecurrence",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Recurrence:    "yearly",
+				FirstRunAt:    timestamppb.New(firstRunAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Recurrence:    util.RecurrenceOnce,
+				FirstRunAt:    timestamppb.New(firstRunAt),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStubs(store)
+			server := newTestServer(t, store, nil)
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.CreateScheduledTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
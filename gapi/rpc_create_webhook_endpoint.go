@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"github.com/antimatter007/go-backend/webhook"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateWebhookEndpoint(ctx context.Context, req *pb.CreateWebhookEndpointRequest) (*pb.CreateWebhookEndpointResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateWebhookEndpointRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate secret: %s", err)
+	}
+
+	endpoint, err := server.store.CreateWebhookEndpoint(ctx, db.CreateWebhookEndpointParams{
+		Owner:  authPayload.Username,
+		Url:    req.GetUrl(),
+		Secret: secret,
+		Events: req.GetEvents(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create webhook endpoint: %s", err)
+	}
+
+	// The secret is only ever revealed here, so the client must store it to verify signatures
+	rsp := &pb.CreateWebhookEndpointResponse{
+		Endpoint: convertWebhookEndpoint(endpoint),
+		Secret:   endpoint.Secret,
+	}
+	return rsp, nil
+}
+
+func validateCreateWebhookEndpointRequest(req *pb.CreateWebhookEndpointRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateWebhookURL(req.GetUrl()); err != nil {
+		violations = append(violations, fieldViolation("url", err))
+	}
+
+	if len(req.GetEvents()) == 0 {
+		violations = append(violations, fieldViolation("events", fmt.Errorf("must not be empty")))
+	}
+
+	for i, event := range req.GetEvents() {
+		if !webhook.IsSupportedEvent(event) {
+			field := fmt.Sprintf("events[%d]", i)
+			violations = append(violations, fieldViolation(field, fmt.Errorf("unsupported event")))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,34 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/worker"
+)
+
+func (server *Server) DeleteArchivedTask(ctx context.Context, req *pb.DeleteArchivedTaskRequest) (*pb.DeleteArchivedTaskResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateArchivedTaskID(req.GetQueue(), req.GetTaskId())
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.getArchivedTask(req.GetQueue(), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.taskInspector.DeleteTask(req.GetQueue(), req.GetTaskId())
+	if err != nil {
+		return nil, archivedTaskError(err)
+	}
+
+	server.resolveFailedTask(ctx, req.GetQueue(), req.GetTaskId(), worker.FailedTaskDeleted, authPayload.Username)
+	return &pb.DeleteArchivedTaskResponse{}, nil
+}
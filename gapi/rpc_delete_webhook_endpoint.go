@@ -0,0 +1,44 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) DeleteWebhookEndpoint(ctx context.Context, req *pb.DeleteWebhookEndpointRequest) (*pb.DeleteWebhookEndpointResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateDeleteWebhookEndpointRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	endpoint, err := server.getOwnWebhookEndpoint(ctx, req.GetEndpointId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.store.DeleteWebhookEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete webhook endpoint: %s", err)
+	}
+
+	return &pb.DeleteWebhookEndpointResponse{}, nil
+}
+
+func validateDeleteWebhookEndpointRequest(req *pb.DeleteWebhookEndpointRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetEndpointId()); err != nil {
+		violations = append(violations, fieldViolation("endpoint_id", err))
+	}
+
+	return violations
+}
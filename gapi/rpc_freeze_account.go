@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+)
+
+// FreezeAccount stops all debits and credits of an account until it is unfrozen
+func (server *Server) FreezeAccount(ctx context.Context, req *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole, util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateAccountStatusRequest(req.GetAccountId(), req.GetReason(), true)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.changeAccountStatus(ctx, req.GetAccountId(), db.AccountFrozen, req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.FreezeAccountResponse{
+		Account: account,
+	}
+	return rsp, nil
+}
@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFreezeAccountAPI(t *testing.T) {
+	account := db.Account{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    util.RandomOwner(),
+		Balance:  util.RandomMoney(),
+		Currency: util.USD,
+		Status:   db.AccountFrozen,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.FreezeAccountRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.FreezeAccountResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.FreezeAccountRequest{
+				AccountId: account.ID,
+				Reason:    "court order",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ChangeAccountStatusTxParams{
+					ID:     account.ID,
+					Status: db.AccountFrozen,
+					Reason: "court order",
+				}
+				store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+				store.EXPECT().GetHeldAmount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(int64(10), nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.AccountFrozen, res.GetAccount().GetStatus())
+				require.Equal(t, util.FormatAmount(account.Balance, account.Currency), res.GetAccount().GetBalanceDecimal())
+				require.Equal(t, account.Balance-10, res.GetAccount().GetAvailableBalance())
+			},
+		},
+		{
+			name: "AlreadyClosed",
+			req: &pb.FreezeAccountRequest{
+				AccountId: account.ID,
+				Reason:    "court order",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				err := fmt.Errorf("%w: account is closed", db.ErrInvalidAccountStatusChange)
+				store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, err)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "NotFound",
+			req: &pb.FreezeAccountRequest{
+				AccountId: account.ID,
+				Reason:    "court order",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "MissingReason",
+			req: &pb.FreezeAccountRequest{
+				AccountId: account.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "Depositor",
+			req: &pb.FreezeAccountRequest{
+				AccountId: account.ID,
+				Reason:    "court order",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, account.Owner, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStubs(store)
+			server := newTestServer(t, store, nil)
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.FreezeAccount(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+)
+
+func (server *Server) GetArchivedTask(ctx context.Context, req *pb.GetArchivedTaskRequest) (*pb.GetArchivedTaskResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateArchivedTaskID(req.GetQueue(), req.GetTaskId())
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	info, err := server.getArchivedTask(req.GetQueue(), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.GetArchivedTaskResponse{
+		Task: convertArchivedTask(info),
+	}
+	return rsp, nil
+}
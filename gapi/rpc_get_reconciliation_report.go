@@ -0,0 +1,67 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultMaxDiscrepancies is the number of discrepancies returned when the request doesn't set one
+const defaultMaxDiscrepancies = 100
+
+func (server *Server) GetReconciliationReport(ctx context.Context, req *pb.GetReconciliationReportRequest) (*pb.GetReconciliationReportResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetReconciliationReportRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	report, err := server.store.GetLatestReconciliationReport(ctx)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "no reconciliation has run yet")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get reconciliation report: %s", err)
+	}
+
+	limit := req.GetMaxDiscrepancies()
+	if limit == 0 {
+		limit = defaultMaxDiscrepancies
+	}
+
+	discrepancies, err := server.store.ListReconciliationDiscrepancies(ctx, db.ListReconciliationDiscrepanciesParams{
+		ReportID: report.ID,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list reconciliation discrepancies: %s", err)
+	}
+
+	rsp := &pb.GetReconciliationReportResponse{
+		Report:        convertReconciliationReport(report),
+		Discrepancies: make([]*pb.ReconciliationDiscrepancy, len(discrepancies)),
+	}
+	for i, discrepancy := range discrepancies {
+		rsp.Discrepancies[i] = convertReconciliationDiscrepancy(discrepancy)
+	}
+	return rsp, nil
+}
+
+func validateGetReconciliationReportRequest(req *pb.GetReconciliationReportRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetMaxDiscrepancies() < 0 || req.GetMaxDiscrepancies() > 1000 {
+		violations = append(violations, fieldViolation("max_discrepancies", fmt.Errorf("must be between 0 and 1000")))
+	}
+
+	return violations
+}
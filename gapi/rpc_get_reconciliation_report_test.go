@@ -0,0 +1,135 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetReconciliationReportAPI(t *testing.T) {
+	report := db.ReconciliationReport{
+		ID:               util.RandomInt(1, 1000),
+		Status:           db.ReconciliationCompleted,
+		AccountsChecked:  42,
+		DiscrepancyCount: 1,
+		StartedAt:        time.Now().Add(-time.Minute),
+		FinishedAt:       pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	discrepancy := db.ReconciliationDiscrepancy{
+		ReportID:      report.ID,
+		AccountID:     util.RandomInt(1, 1000),
+		StoredBalance: 100,
+		LedgerBalance: 90,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.GetReconciliationReportRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.GetReconciliationReportResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.GetReconciliationReportRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLatestReconciliationReport(gomock.Any()).Times(1).Return(report, nil)
+
+				arg := db.ListReconciliationDiscrepanciesParams{
+					ReportID: report.ID,
+					Limit:    defaultMaxDiscrepancies,
+				}
+				store.EXPECT().
+					ListReconciliationDiscrepancies(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ReconciliationDiscrepancy{discrepancy}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetReconciliationReportResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, report.ID, res.GetReport().GetId())
+				require.Equal(t, int64(1), res.GetReport().GetDiscrepancyCount())
+				require.NotNil(t, res.GetReport().GetFinishedAt())
+				require.Len(t, res.GetDiscrepancies(), 1)
+				require.Equal(t, discrepancy.AccountID, res.GetDiscrepancies()[0].GetAccountId())
+				require.Equal(t, int64(90), res.GetDiscrepancies()[0].GetLedgerBalance())
+			},
+		},
+		{
+			name: "NoReport",
+			req:  &pb.GetReconciliationReportRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLatestReconciliationReport(gomock.Any()).Times(1).Return(db.ReconciliationReport{}, db.ErrRecordNotFound)
+				store.EXPECT().ListReconciliationDiscrepancies(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetReconciliationReportResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "NotAdmin",
+			req:  &pb.GetReconciliationReportRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLatestReconciliationReport(gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetReconciliationReportResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "InvalidMaxDiscrepancies",
+			req:  &pb.GetReconciliationReportRequest{MaxDiscrepancies: 1001},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLatestReconciliationReport(gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetReconciliationReportResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStubs(store)
+			server := newTestServer(t, store, nil)
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.GetReconciliationReport(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
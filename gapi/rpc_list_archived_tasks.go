@@ -0,0 +1,51 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) ListArchivedTasks(ctx context.Context, req *pb.ListArchivedTasksRequest) (*pb.ListArchivedTasksResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListArchivedTasksRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	tasks, err := server.taskInspector.ListArchivedTasks(req.GetQueue(), int(req.GetPageId()), int(req.GetPageSize()))
+	if err != nil {
+		return nil, archivedTaskError(err)
+	}
+
+	rsp := &pb.ListArchivedTasksResponse{
+		Tasks: make([]*pb.ArchivedTask, len(tasks)),
+	}
+	for i, task := range tasks {
+		rsp.Tasks[i] = convertArchivedTask(task)
+	}
+	return rsp, nil
+}
+
+func validateListArchivedTasksRequest(req *pb.ListArchivedTasksRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateString(req.GetQueue(), 1, 100); err != nil {
+		violations = append(violations, fieldViolation("queue", err))
+	}
+
+	if err := val.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListNotifications(ctx context.Context, req *pb.ListNotificationsRequest) (*pb.ListNotificationsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListNotificationsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	notifications, err := server.store.ListNotifications(ctx, db.ListNotificationsParams{
+		Username:   authPayload.Username,
+		UnreadOnly: req.GetUnreadOnly(),
+		Limit:      req.GetPageSize(),
+		Offset:     (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list notifications: %s", err)
+	}
+
+	rsp := &pb.ListNotificationsResponse{
+		Notifications: make([]*pb.Notification, len(notifications)),
+	}
+	for i, notification := range notifications {
+		rsp.Notifications[i] = convertNotification(notification)
+	}
+	return rsp, nil
+}
+
+func validateListNotificationsRequest(req *pb.ListNotificationsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
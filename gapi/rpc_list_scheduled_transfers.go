@@ -0,0 +1,64 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListScheduledTransfers(ctx context.Context, req *pb.ListScheduledTransfersRequest) (*pb.ListScheduledTransfersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListScheduledTransfersRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	scheduledTransfers, err := server.store.ListScheduledTransfers(ctx, db.ListScheduledTransfersParams{
+		Owner:  authPayload.Username,
+		Limit:  req.GetPageSize(),
+		Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled transfers: %s", err)
+	}
+
+	rsp := &pb.ListScheduledTransfersResponse{
+		ScheduledTransfers: make([]*pb.ScheduledTransfer, len(scheduledTransfers)),
+	}
+	// scheduled transfers of a user tend to share source accounts
+	currencies := make(map[int64]string)
+	for i, scheduledTransfer := range scheduledTransfers {
+		currency, ok := currencies[scheduledTransfer.FromAccountID]
+		if !ok {
+			currency, err = server.scheduledTransferCurrency(ctx, scheduledTransfer)
+			if err != nil {
+				return nil, err
+			}
+			currencies[scheduledTransfer.FromAccountID] = currency
+		}
+		rsp.ScheduledTransfers[i] = convertScheduledTransfer(scheduledTransfer, currency)
+	}
+	return rsp, nil
+}
+
+func validateListScheduledTransfersRequest(req *pb.ListScheduledTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
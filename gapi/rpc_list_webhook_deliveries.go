@@ -0,0 +1,63 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListWebhookDeliveriesRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	endpoint, err := server.getOwnWebhookEndpoint(ctx, req.GetEndpointId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		Limit:      req.GetPageSize(),
+		Offset:     (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list webhook deliveries: %s", err)
+	}
+
+	rsp := &pb.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*pb.WebhookDelivery, len(deliveries)),
+	}
+	for i, delivery := range deliveries {
+		rsp.Deliveries[i] = convertWebhookDelivery(delivery)
+	}
+	return rsp, nil
+}
+
+func validateListWebhookDeliveriesRequest(req *pb.ListWebhookDeliveriesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetEndpointId()); err != nil {
+		violations = append(violations, fieldViolation("endpoint_id", err))
+	}
+
+	if err := val.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolation("page_id", err))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
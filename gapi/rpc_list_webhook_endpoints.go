@@ -0,0 +1,30 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListWebhookEndpoints(ctx context.Context, req *pb.ListWebhookEndpointsRequest) (*pb.ListWebhookEndpointsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	endpoints, err := server.store.ListWebhookEndpoints(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list webhook endpoints: %s", err)
+	}
+
+	rsp := &pb.ListWebhookEndpointsResponse{
+		Endpoints: make([]*pb.WebhookEndpoint, len(endpoints)),
+	}
+	for i, endpoint := range endpoints {
+		rsp.Endpoints[i] = convertWebhookEndpoint(endpoint)
+	}
+	return rsp, nil
+}
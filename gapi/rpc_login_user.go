@@ -17,35 +17,43 @@ import (
 func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
 	violations := validateLoginUserRequest(req)
 	if violations != nil {
+		recordLoginFailed(loginFailedInvalidArgument)
 		return nil, invalidArgumentError(violations)
 	}
 
 	user, err := server.store.GetUser(ctx, req.GetUsername())
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
+			recordLoginFailed(loginFailedUserNotFound)
 			return nil, status.Errorf(codes.NotFound, "user not found")
 		}
+		recordLoginFailed(loginFailedInternal)
 		return nil, status.Errorf(codes.Internal, "failed to find user")
 	}
 
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
+		recordLoginFailed(loginFailedWrongPassword)
 		return nil, status.Errorf(codes.NotFound, "incorrect password")
 	}
 
 	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
 		server.config.AccessTokenDuration,
 	)
 	if err != nil {
+		recordLoginFailed(loginFailedInternal)
 		return nil, status.Errorf(codes.Internal, "failed to create access token")
 	}
 
 	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
 		server.config.RefreshTokenDuration,
 	)
 	if err != nil {
+		recordLoginFailed(loginFailedInternal)
 		return nil, status.Errorf(codes.Internal, "failed to create refresh token")
 	}
 
@@ -60,6 +68,7 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		ExpiresAt:    refreshPayload.ExpiredAt,
 	})
 	if err != nil {
+		recordLoginFailed(loginFailedInternal)
 		return nil, status.Errorf(codes.Internal, "failed to create session")
 	}
 
@@ -71,6 +80,7 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt),
 		RefreshTokenExpiresAt: timestamppb.New(refreshPayload.ExpiredAt),
 	}
+	recordLoginSucceeded()
 	return rsp, nil
 }
 
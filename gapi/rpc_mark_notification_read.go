@@ -0,0 +1,50 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) MarkNotificationRead(ctx context.Context, req *pb.MarkNotificationReadRequest) (*pb.MarkNotificationReadResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateMarkNotificationReadRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	notification, err := server.store.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ID:       req.GetNotificationId(),
+		Username: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "notification not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to mark notification read: %s", err)
+	}
+
+	rsp := &pb.MarkNotificationReadResponse{
+		Notification: convertNotification(notification),
+	}
+	return rsp, nil
+}
+
+func validateMarkNotificationReadRequest(req *pb.MarkNotificationReadRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetNotificationId()); err != nil {
+		violations = append(violations, fieldViolation("notification_id", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,106 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/notification"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	notificationEvents   = []string{notification.EventTransferSent, notification.EventTransferReceived}
+	notificationChannels = []string{notification.ChannelEmail, notification.ChannelInApp}
+)
+
+func (server *Server) GetNotificationPreferences(ctx context.Context, req *pb.GetNotificationPreferencesRequest) (*pb.GetNotificationPreferencesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	preferences, err := server.store.ListNotificationPreferences(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list notification preferences: %s", err)
+	}
+
+	rsp := &pb.GetNotificationPreferencesResponse{
+		Preferences: effectiveNotificationPreferences(preferences),
+	}
+	return rsp, nil
+}
+
+func (server *Server) UpdateNotificationPreferences(ctx context.Context, req *pb.UpdateNotificationPreferencesRequest) (*pb.UpdateNotificationPreferencesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateUpdateNotificationPreferencesRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	for _, preference := range req.GetPreferences() {
+		_, err := server.store.UpsertNotificationPreference(ctx, db.UpsertNotificationPreferenceParams{
+			Username: authPayload.Username,
+			Event:    preference.GetEvent(),
+			Channel:  preference.GetChannel(),
+			Enabled:  preference.GetEnabled(),
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to update notification preference: %s", err)
+		}
+	}
+
+	preferences, err := server.store.ListNotificationPreferences(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list notification preferences: %s", err)
+	}
+
+	rsp := &pb.UpdateNotificationPreferencesResponse{
+		Preferences: effectiveNotificationPreferences(preferences),
+	}
+	return rsp, nil
+}
+
+// effectiveNotificationPreferences lists every event and channel combination,
+// filling in the default for the ones the user never changed.
+func effectiveNotificationPreferences(preferences []db.NotificationPreference) []*pb.NotificationPreference {
+	var rsp []*pb.NotificationPreference
+	for _, event := range notificationEvents {
+		for _, channel := range notificationChannels {
+			rsp = append(rsp, &pb.NotificationPreference{
+				Event:   event,
+				Channel: channel,
+				Enabled: notification.IsEnabled(preferences, event, channel),
+			})
+		}
+	}
+	return rsp
+}
+
+func validateUpdateNotificationPreferencesRequest(req *pb.UpdateNotificationPreferencesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if len(req.GetPreferences()) == 0 {
+		violations = append(violations, fieldViolation("preferences", fmt.Errorf("must not be empty")))
+	}
+
+	for i, preference := range req.GetPreferences() {
+		if !notification.IsSupportedEvent(preference.GetEvent()) {
+			field := fmt.Sprintf("preferences[%d].event", i)
+			violations = append(violations, fieldViolation(field, fmt.Errorf("unsupported event")))
+		}
+
+		if !notification.IsSupportedChannel(preference.GetChannel()) {
+			field := fmt.Sprintf("preferences[%d].channel", i)
+			violations = append(violations, fieldViolation(field, fmt.Errorf("unsupported channel")))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,48 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) PauseScheduledTransfer(ctx context.Context, req *pb.PauseScheduledTransferRequest) (*pb.PauseScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validatePauseScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// the next run is kept for display only, resuming computes it again
+	scheduledTransfer, err := server.changeScheduledTransferStatus(ctx, req.GetScheduledTransferId(), authPayload.Username,
+		[]string{db.ScheduledTransferActive}, db.ScheduledTransferPaused,
+		func(scheduledTransfer db.ScheduledTransfer) (pgtype.Timestamptz, error) {
+			return scheduledTransfer.NextRunAt, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.PauseScheduledTransferResponse{
+		ScheduledTransfer: scheduledTransfer,
+	}
+	return rsp, nil
+}
+
+func validatePauseScheduledTransferRequest(req *pb.PauseScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetScheduledTransferId()); err != nil {
+		violations = append(violations, fieldViolation("scheduled_transfer_id", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"github.com/antimatter007/go-backend/worker"
+	"github.com/hibiken/asynq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RedeliverWebhook(ctx context.Context, req *pb.RedeliverWebhookRequest) (*pb.RedeliverWebhookResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateRedeliverWebhookRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, req.GetDeliveryId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "webhook delivery not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get webhook delivery: %s", err)
+	}
+
+	_, err = server.getOwnWebhookEndpoint(ctx, delivery.EndpointID, authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	// A manual redelivery is a single attempt, its outcome is final
+	taskPayload := &worker.PayloadDeliverWebhook{DeliveryID: delivery.ID}
+	opts := []asynq.Option{
+		asynq.MaxRetry(0),
+		asynq.Queue(worker.QueueCritical),
+	}
+
+	err = server.taskDistributor.DistributeTaskDeliverWebhook(ctx, taskPayload, opts...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to distribute task to redeliver webhook: %s", err)
+	}
+
+	rsp := &pb.RedeliverWebhookResponse{
+		Delivery: convertWebhookDelivery(delivery),
+	}
+	return rsp, nil
+}
+
+func validateRedeliverWebhookRequest(req *pb.RedeliverWebhookRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetDeliveryId()); err != nil {
+		violations = append(violations, fieldViolation("delivery_id", err))
+	}
+
+	return violations
+}
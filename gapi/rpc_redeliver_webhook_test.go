@@ -0,0 +1,167 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/webhook"
+	"github.com/antimatter007/go-backend/worker"
+	mockwk "github.com/antimatter007/go-backend/worker/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRedeliverWebhookAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	otherUser, _ := randomUser(t)
+
+	endpoint := db.WebhookEndpoint{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    user.Username,
+		Url:      "https://example.com/hook",
+		Secret:   util.RandomString(32),
+		Events:   []string{webhook.EventTransferCreated},
+		IsActive: true,
+	}
+	delivery := db.WebhookDelivery{
+		ID:         util.RandomInt(1, 1000),
+		EndpointID: endpoint.ID,
+		Event:      webhook.EventTransferCreated,
+		Payload:    []byte(`{}`),
+		Status:     webhook.StatusFailed,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.RedeliverWebhookRequest
+		buildStubs    func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.RedeliverWebhookResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.RedeliverWebhookRequest{
+				DeliveryId: delivery.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+
+				taskPayload := &worker.PayloadDeliverWebhook{DeliveryID: delivery.ID}
+				taskDistributor.EXPECT().
+					DistributeTaskDeliverWebhook(gomock.Any(), gomock.Eq(taskPayload), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RedeliverWebhookResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, delivery.ID, res.GetDelivery().GetId())
+			},
+		},
+		{
+			name: "DeliveryNotFound",
+			req: &pb.RedeliverWebhookRequest{
+				DeliveryId: delivery.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Any()).Times(1).Return(db.WebhookDelivery{}, db.ErrRecordNotFound)
+				taskDistributor.EXPECT().DistributeTaskDeliverWebhook(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RedeliverWebhookResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "EndpointOfOtherUser",
+			req: &pb.RedeliverWebhookRequest{
+				DeliveryId: delivery.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				taskDistributor.EXPECT().DistributeTaskDeliverWebhook(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, otherUser.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RedeliverWebhookResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "InvalidDeliveryID",
+			req: &pb.RedeliverWebhookRequest{
+				DeliveryId: 0,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RedeliverWebhookResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req: &pb.RedeliverWebhookRequest{
+				DeliveryId: delivery.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.RedeliverWebhookResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			taskDistributor := mockwk.NewMockTaskDistributor(ctrl)
+
+			tc.buildStubs(store, taskDistributor)
+			server := newTestServer(t, store, taskDistributor)
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.RedeliverWebhook(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
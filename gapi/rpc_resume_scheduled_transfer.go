@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ResumeScheduledTransfer(ctx context.Context, req *pb.ResumeScheduledTransferRequest) (*pb.ResumeScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateResumeScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// runs missed while paused are skipped
+	scheduledTransfer, err := server.changeScheduledTransferStatus(ctx, req.GetScheduledTransferId(), authPayload.Username,
+		[]string{db.ScheduledTransferPaused}, db.ScheduledTransferActive,
+		func(scheduledTransfer db.ScheduledTransfer) (pgtype.Timestamptz, error) {
+			nextRunAt, ok := util.NextOccurrence(scheduledTransfer.Recurrence, scheduledTransfer.FirstRunAt, time.Now())
+			if !ok || (scheduledTransfer.EndAt.Valid && nextRunAt.After(scheduledTransfer.EndAt.Time)) {
+				return pgtype.Timestamptz{}, status.Errorf(codes.FailedPrecondition, "scheduled transfer has no runs left")
+			}
+			return pgtype.Timestamptz{Time: nextRunAt, Valid: true}, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.ResumeScheduledTransferResponse{
+		ScheduledTransfer: scheduledTransfer,
+	}
+	return rsp, nil
+}
+
+func validateResumeScheduledTransferRequest(req *pb.ResumeScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetScheduledTransferId()); err != nil {
+		violations = append(violations, fieldViolation("scheduled_transfer_id", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,87 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReverseTransfer refunds a transfer. Admins may reverse any transfer, other users only the ones they received.
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.AdminRole, util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateReverseTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if authPayload.Role != util.AdminRole {
+		transfer, err := server.store.GetTransfer(ctx, req.GetTransferId())
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, status.Errorf(codes.NotFound, "transfer not found")
+			}
+			return nil, status.Errorf(codes.Internal, "failed to get transfer: %s", err)
+		}
+
+		_, err = server.getOwnAccount(ctx, transfer.ToAccountID, authPayload.Username)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID: req.GetTransferId(),
+		Amount:     req.GetAmount(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			return nil, status.Errorf(codes.NotFound, "transfer not found")
+		case errors.Is(err, db.ErrTransferAlreadyReversed),
+			errors.Is(err, db.ErrTransferIsReversal),
+			errors.Is(err, db.ErrConvertedReversal),
+			errors.Is(err, db.ErrInsufficientFunds),
+			errors.Is(err, db.ErrAccountFrozen),
+			errors.Is(err, db.ErrAccountClosed):
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		case errors.Is(err, db.ErrInvalidReversalAmount):
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to reverse transfer: %s", err)
+		}
+	}
+
+	server.announceTransfer(ctx, result.Reversal)
+
+	rsp := &pb.ReverseTransferResponse{
+		Reversal:        convertTransfer(result.Reversal.Transfer, result.Reversal.FromAccount.Currency, result.Reversal.ToAccount.Currency),
+		RemainingAmount: result.RemainingAmount,
+	}
+	return rsp, nil
+}
+
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetTransferId()); err != nil {
+		violations = append(violations, fieldViolation("transfer_id", err))
+	}
+
+	// a missing amount reverses whatever remains of the transfer
+	if req.Amount != nil {
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolation("amount", err))
+		}
+	}
+
+	return violations
+}
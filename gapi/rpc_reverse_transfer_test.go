@@ -0,0 +1,163 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	mockwk "github.com/antimatter007/go-backend/worker/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReverseTransferAPI(t *testing.T) {
+	sender, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: util.RandomInt(1, 1000), Owner: sender.Username, Currency: util.USD}
+	toAccount := db.Account{ID: fromAccount.ID + 1, Owner: recipient.Username, Currency: util.USD}
+
+	transfer := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        100,
+	}
+	reversal := db.ReverseTransferTxResult{
+		Original: transfer,
+		Reversal: db.TransferTxResult{
+			Transfer: db.Transfer{
+				ID:                 transfer.ID + 1,
+				FromAccountID:      toAccount.ID,
+				ToAccountID:        fromAccount.ID,
+				Amount:             40,
+				ReversedTransferID: pgtype.Int8{Int64: transfer.ID, Valid: true},
+			},
+			FromAccount: toAccount,
+			ToAccount:   fromAccount,
+		},
+		RemainingAmount: 60,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ReverseTransferRequest
+		buildStubs    func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ReverseTransferResponse, err error)
+	}{
+		{
+			name: "RecipientPartialRefund",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+				Amount:     proto.Int64(40),
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				arg := db.ReverseTransferTxParams{TransferID: transfer.ID, Amount: 40}
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(reversal, nil)
+
+				taskDistributor.EXPECT().DistributeTaskSendTransferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				taskDistributor.EXPECT().DistributeTaskPublishWebhookEvent(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, recipient.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(40), res.GetReversal().GetAmount())
+				require.Equal(t, "0.40", res.GetReversal().GetAmountDecimal())
+				require.Equal(t, "0.40", res.GetReversal().GetToAmountDecimal())
+				require.Equal(t, transfer.ID, res.GetReversal().GetReversedTransferId())
+				require.Equal(t, int64(60), res.GetRemainingAmount())
+			},
+		},
+		{
+			name: "AdminFullReversal",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+
+				arg := db.ReverseTransferTxParams{TransferID: transfer.ID}
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(reversal, nil)
+
+				taskDistributor.EXPECT().DistributeTaskSendTransferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				taskDistributor.EXPECT().DistributeTaskPublishWebhookEvent(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Sender",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, sender.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "AlreadyReversed",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, db.ErrTransferAlreadyReversed)
+				taskDistributor.EXPECT().DistributeTaskSendTransferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			taskDistributor := mockwk.NewMockTaskDistributor(ctrl)
+
+			tc.buildStubs(store, taskDistributor)
+			server := newTestServer(t, store, taskDistributor)
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.ReverseTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
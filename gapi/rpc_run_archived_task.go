@@ -0,0 +1,34 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/worker"
+)
+
+func (server *Server) RunArchivedTask(ctx context.Context, req *pb.RunArchivedTaskRequest) (*pb.RunArchivedTaskResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateArchivedTaskID(req.GetQueue(), req.GetTaskId())
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.getArchivedTask(req.GetQueue(), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.taskInspector.RunTask(req.GetQueue(), req.GetTaskId())
+	if err != nil {
+		return nil, archivedTaskError(err)
+	}
+
+	server.resolveFailedTask(ctx, req.GetQueue(), req.GetTaskId(), worker.FailedTaskRequeued, authPayload.Username)
+	return &pb.RunArchivedTaskResponse{}, nil
+}
@@ -0,0 +1,174 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/worker"
+	mockwk "github.com/antimatter007/go-backend/worker/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRunArchivedTaskAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	task := &asynq.TaskInfo{
+		ID:      util.RandomString(16),
+		Queue:   worker.QueueCritical,
+		Type:    worker.TaskSendVerifyEmail,
+		Payload: []byte(`{"username":"alice"}`),
+		State:   asynq.TaskStateArchived,
+		LastErr: "smtp unavailable",
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.RunArchivedTaskRequest
+		buildStubs    func(store *mockdb.MockStore, taskInspector *mockwk.MockTaskInspector)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.RunArchivedTaskResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.RunArchivedTaskRequest{
+				Queue:  task.Queue,
+				TaskId: task.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskInspector *mockwk.MockTaskInspector) {
+				taskInspector.EXPECT().GetTaskInfo(gomock.Eq(task.Queue), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				taskInspector.EXPECT().RunTask(gomock.Eq(task.Queue), gomock.Eq(task.ID)).Times(1).Return(nil)
+
+				arg := db.ResolveFailedTaskParams{
+					Resolution: worker.FailedTaskRequeued,
+					ResolvedBy: admin.Username,
+					Queue:      task.Queue,
+					TaskID:     task.ID,
+				}
+				store.EXPECT().ResolveFailedTask(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(1), nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, admin.Username, admin.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RunArchivedTaskResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "TaskNotArchived",
+			req: &pb.RunArchivedTaskRequest{
+				Queue:  task.Queue,
+				TaskId: task.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskInspector *mockwk.MockTaskInspector) {
+				pending := *task
+				pending.State = asynq.TaskStatePending
+				taskInspector.EXPECT().GetTaskInfo(gomock.Any(), gomock.Any()).Times(1).Return(&pending, nil)
+				taskInspector.EXPECT().RunTask(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ResolveFailedTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, admin.Username, admin.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RunArchivedTaskResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "TaskNotFound",
+			req: &pb.RunArchivedTaskRequest{
+				Queue:  task.Queue,
+				TaskId: task.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskInspector *mockwk.MockTaskInspector) {
+				taskInspector.EXPECT().
+					GetTaskInfo(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, fmt.Errorf("asynq: %w", asynq.ErrTaskNotFound))
+				taskInspector.EXPECT().RunTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, admin.Username, admin.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RunArchivedTaskResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req: &pb.RunArchivedTaskRequest{
+				Queue:  task.Queue,
+				TaskId: task.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskInspector *mockwk.MockTaskInspector) {
+				taskInspector.EXPECT().GetTaskInfo(gomock.Any(), gomock.Any()).Times(0)
+				taskInspector.EXPECT().RunTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, admin.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RunArchivedTaskResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "InvalidTaskID",
+			req: &pb.RunArchivedTaskRequest{
+				Queue:  task.Queue,
+				TaskId: "",
+			},
+			buildStubs: func(store *mockdb.MockStore, taskInspector *mockwk.MockTaskInspector) {
+				taskInspector.EXPECT().GetTaskInfo(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, admin.Username, admin.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.RunArchivedTaskResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			taskInspector := mockwk.NewMockTaskInspector(ctrl)
+
+			tc.buildStubs(store, taskInspector)
+			server := newTestServer(t, store, nil)
+			server.taskInspector = taskInspector
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.RunArchivedTask(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetTransferLimits replaces the override of a user's transfer limits in a currency.
+// A limit left unset falls back to the default of the currency.
+func (server *Server) SetTransferLimits(ctx context.Context, req *pb.SetTransferLimitsRequest) (*pb.SetTransferLimitsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetTransferLimitsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	limits, err := server.store.UpsertTransferLimits(ctx, db.UpsertTransferLimitsParams{
+		Username:          pgtype.Text{String: req.GetUsername(), Valid: true},
+		Currency:          req.GetCurrency(),
+		MaxPerTransfer:    optionalLimit(req.MaxPerTransfer),
+		DailyPerAccount:   optionalLimit(req.DailyPerAccount),
+		MonthlyPerAccount: optionalLimit(req.MonthlyPerAccount),
+		DailyPerUser:      optionalLimit(req.DailyPerUser),
+		MonthlyPerUser:    optionalLimit(req.MonthlyPerUser),
+		UpdatedBy:         authPayload.Username,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to set transfer limits: %s", err)
+	}
+
+	rsp := &pb.SetTransferLimitsResponse{
+		Limits: convertTransferLimits(limits),
+	}
+	return rsp, nil
+}
+
+func optionalLimit(limit *int64) pgtype.Int8 {
+	if limit == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: *limit, Valid: true}
+}
+
+func validateSetTransferLimitsRequest(req *pb.SetTransferLimitsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	limits := []struct {
+		field string
+		limit *int64
+	}{
+		{"max_per_transfer", req.MaxPerTransfer},
+		{"daily_per_account", req.DailyPerAccount},
+		{"monthly_per_account", req.MonthlyPerAccount},
+		{"daily_per_user", req.DailyPerUser},
+		{"monthly_per_user", req.MonthlyPerUser},
+	}
+	for _, limit := range limits {
+		if limit.limit != nil && *limit.limit < 0 {
+			violations = append(violations, fieldViolation(limit.field, fmt.Errorf("must not be negative")))
+		}
+	}
+
+	return violations
+}
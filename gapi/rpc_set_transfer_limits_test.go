@@ -0,0 +1,150 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/token"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSetTransferLimitsAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		req           *pb.SetTransferLimitsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.SetTransferLimitsResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.SetTransferLimitsRequest{
+				Username:       username,
+				Currency:       util.USD,
+				MaxPerTransfer: proto.Int64(5000),
+				DailyPerUser:   proto.Int64(20000),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertTransferLimitsParams{
+					Username:       pgtype.Text{String: username, Valid: true},
+					Currency:       util.USD,
+					MaxPerTransfer: pgtype.Int8{Int64: 5000, Valid: true},
+					DailyPerUser:   pgtype.Int8{Int64: 20000, Valid: true},
+					UpdatedBy:      "banker",
+				}
+				store.EXPECT().
+					UpsertTransferLimits(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.TransferLimit{
+						Username:       arg.Username,
+						Currency:       arg.Currency,
+						MaxPerTransfer: arg.MaxPerTransfer,
+						DailyPerUser:   arg.DailyPerUser,
+						UpdatedBy:      arg.UpdatedBy,
+						UpdatedAt:      time.Now(),
+					}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitsResponse, err error) {
+				require.NoError(t, err)
+				limits := res.GetLimits()
+				require.Equal(t, username, limits.GetUsername())
+				require.Equal(t, int64(5000), limits.GetMaxPerTransfer())
+				require.Equal(t, int64(20000), limits.GetDailyPerUser())
+				// limits that aren't overridden stay unset
+				require.Nil(t, limits.DailyPerAccount)
+			},
+		},
+		{
+			name: "UserNotFound",
+			req: &pb.SetTransferLimitsRequest{
+				Username: username,
+				Currency: util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertTransferLimits(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferLimit{}, &pgconn.PgError{Code: db.ForeignKeyViolation})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitsResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "NegativeLimit",
+			req: &pb.SetTransferLimitsRequest{
+				Username:          username,
+				Currency:          util.USD,
+				MonthlyPerAccount: proto.Int64(-1),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertTransferLimits(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitsResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "Depositor",
+			req: &pb.SetTransferLimitsRequest{
+				Username:       username,
+				Currency:       util.USD,
+				MaxPerTransfer: proto.Int64(1000000000),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertTransferLimits(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitsResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStubs(store)
+			server := newTestServer(t, store, nil)
+
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.SetTransferLimits(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
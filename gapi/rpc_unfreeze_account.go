@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+)
+
+// UnfreezeAccount makes a frozen account active again
+func (server *Server) UnfreezeAccount(ctx context.Context, req *pb.UnfreezeAccountRequest) (*pb.UnfreezeAccountResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole, util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateAccountStatusRequest(req.GetAccountId(), req.GetReason(), true)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.changeAccountStatus(ctx, req.GetAccountId(), db.AccountActive, req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.UnfreezeAccountResponse{
+		Account: account,
+	}
+	return rsp, nil
+}
@@ -6,6 +6,7 @@ import (
 	db "github.com/antimatter007/go-backend/db/sqlc"
 	"github.com/antimatter007/go-backend/pb"
 	"github.com/antimatter007/go-backend/val"
+	"github.com/antimatter007/go-backend/webhook"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -25,6 +26,11 @@ func (server *Server) VerifyEmail(ctx context.Context, req *pb.VerifyEmailReques
 		return nil, status.Errorf(codes.Internal, "failed to verify email")
 	}
 
+	server.publishWebhookEvent(ctx, webhook.EventUserEmailVerified, []string{txResult.User.Username}, map[string]string{
+		"username": txResult.User.Username,
+		"email":    txResult.User.Email,
+	})
+
 	rsp := &pb.VerifyEmailResponse{
 		IsVerified: txResult.User.IsEmailVerified,
 	}
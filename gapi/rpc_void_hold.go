@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// VoidHold releases a hold. The owners of either account may void it.
+func (server *Server) VoidHold(ctx context.Context, req *pb.VoidHoldRequest) (*pb.VoidHoldResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateVoidHoldRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	hold, err := server.getHold(ctx, req.GetHoldId())
+	if err != nil {
+		return nil, err
+	}
+
+	// both accounts of a hold share its currency
+	account, err := server.getOwnAccount(ctx, hold.FromAccountID, authPayload.Username)
+	if status.Code(err) == codes.PermissionDenied {
+		account, err = server.getOwnAccount(ctx, hold.ToAccountID, authPayload.Username)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err = server.store.VoidHold(ctx, hold.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// the hold exists, so it was captured, voided or expired in the meantime
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", db.ErrHoldNotActive)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to void hold: %s", err)
+	}
+
+	// voiding moves no money, so only the held amount of the account changed
+	accountRsp, err := server.accountResponse(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.VoidHoldResponse{
+		Hold:    convertHold(hold, account.Currency),
+		Account: accountRsp,
+	}
+	return rsp, nil
+}
+
+func validateVoidHoldRequest(req *pb.VoidHoldRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetHoldId()); err != nil {
+		violations = append(violations, fieldViolation("hold_id", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,82 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/pb"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getOwnScheduledTransfer loads a scheduled transfer and makes sure it belongs to the user
+func (server *Server) getOwnScheduledTransfer(ctx context.Context, id int64, username string) (db.ScheduledTransfer, error) {
+	scheduledTransfer, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return scheduledTransfer, status.Errorf(codes.NotFound, "scheduled transfer not found")
+		}
+		return scheduledTransfer, status.Errorf(codes.Internal, "failed to get scheduled transfer: %s", err)
+	}
+
+	if scheduledTransfer.Owner != username {
+		return scheduledTransfer, status.Errorf(codes.PermissionDenied, "scheduled transfer doesn't belong to the authenticated user")
+	}
+
+	return scheduledTransfer, nil
+}
+
+// scheduledTransferCurrency returns the currency a scheduled transfer moves, which is that of its source account
+func (server *Server) scheduledTransferCurrency(ctx context.Context, scheduledTransfer db.ScheduledTransfer) (string, error) {
+	fromAccount, err := server.getAccount(ctx, scheduledTransfer.FromAccountID)
+	if err != nil {
+		return "", err
+	}
+
+	return fromAccount.Currency, nil
+}
+
+// changeScheduledTransferStatus moves a scheduled transfer of the user from one of the allowed statuses to a new one
+func (server *Server) changeScheduledTransferStatus(
+	ctx context.Context,
+	id int64,
+	username string,
+	allowedStatuses []string,
+	newStatus string,
+	nextRunAt func(scheduledTransfer db.ScheduledTransfer) (pgtype.Timestamptz, error),
+) (*pb.ScheduledTransfer, error) {
+	scheduledTransfer, err := server.getOwnScheduledTransfer(ctx, id, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(allowedStatuses, scheduledTransfer.Status) {
+		return nil, status.Errorf(codes.FailedPrecondition, "scheduled transfer is %s", scheduledTransfer.Status)
+	}
+
+	arg := db.UpdateScheduledTransferScheduleParams{
+		ID:     scheduledTransfer.ID,
+		Status: newStatus,
+	}
+	if nextRunAt != nil {
+		arg.NextRunAt, err = nextRunAt(scheduledTransfer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	currency, err := server.scheduledTransferCurrency(ctx, scheduledTransfer)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledTransfer, err = server.store.UpdateScheduledTransferSchedule(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update scheduled transfer: %s", err)
+	}
+
+	return convertScheduledTransfer(scheduledTransfer, currency), nil
+}
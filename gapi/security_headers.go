@@ -0,0 +1,43 @@
+package gapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// apiContentSecurityPolicy forbids everything, the API only serves JSON that browsers must not render
+	apiContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+	// swaggerContentSecurityPolicy lets the swagger UI load its own scripts and call the API.
+	// Its components set inline styles and its stylesheet embeds icons as data URIs.
+	swaggerContentSecurityPolicy = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; " +
+		"img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'; base-uri 'self'; form-action 'self'"
+)
+
+// HttpSecurityHeaders sets the headers that keep browsers from sniffing, framing or downgrading the gateway.
+// HSTS is only sent on HTTPS requests, whether TLS ends here or at a proxy, and is disabled when hstsMaxAge is 0.
+func HttpSecurityHeaders(hstsMaxAge time.Duration, handler http.Handler) http.Handler {
+	hsts := fmt.Sprintf("max-age=%d; includeSubDomains", int(hstsMaxAge.Seconds()))
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		header := res.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "no-referrer")
+		header.Set("Content-Security-Policy", apiContentSecurityPolicy)
+		if hstsMaxAge > 0 && (req.TLS != nil || req.Header.Get("X-Forwarded-Proto") == "https") {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+
+		handler.ServeHTTP(res, req)
+	})
+}
+
+// SwaggerSecurityHeaders relaxes the content security policy of HttpSecurityHeaders for the swagger UI
+func SwaggerSecurityHeaders(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Security-Policy", swaggerContentSecurityPolicy)
+		handler.ServeHTTP(res, req)
+	})
+}
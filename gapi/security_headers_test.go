@@ -0,0 +1,52 @@
+package gapi
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpSecurityHeaders(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", http.NotFoundHandler())
+	mux.Handle("/swagger/", SwaggerSecurityHeaders(http.NotFoundHandler()))
+	handler := HttpSecurityHeaders(365*24*time.Hour, mux)
+
+	serve := func(handler http.Handler, path string, setupRequest func(request *http.Request)) http.Header {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		setupRequest(request)
+		handler.ServeHTTP(recorder, request)
+		return recorder.Header()
+	}
+
+	header := serve(handler, "/v1/list_accounts", func(request *http.Request) {})
+	require.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+	require.Equal(t, "DENY", header.Get("X-Frame-Options"))
+	require.Equal(t, "no-referrer", header.Get("Referrer-Policy"))
+	require.Equal(t, apiContentSecurityPolicy, header.Get("Content-Security-Policy"))
+	require.Empty(t, header.Get("Strict-Transport-Security"))
+
+	header = serve(handler, "/swagger/index.html", func(request *http.Request) {})
+	require.Equal(t, swaggerContentSecurityPolicy, header.Get("Content-Security-Policy"))
+	require.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+
+	header = serve(handler, "/v1/list_accounts", func(request *http.Request) {
+		request.TLS = &tls.ConnectionState{}
+	})
+	require.Equal(t, "max-age=31536000; includeSubDomains", header.Get("Strict-Transport-Security"))
+
+	header = serve(handler, "/v1/list_accounts", func(request *http.Request) {
+		request.Header.Set("X-Forwarded-Proto", "https")
+	})
+	require.Equal(t, "max-age=31536000; includeSubDomains", header.Get("Strict-Transport-Security"))
+
+	header = serve(HttpSecurityHeaders(0, mux), "/v1/list_accounts", func(request *http.Request) {
+		request.TLS = &tls.ConnectionState{}
+	})
+	require.Empty(t, header.Get("Strict-Transport-Security"))
+}
@@ -5,6 +5,7 @@ import (
 
 	db "github.com/antimatter007/go-backend/db/sqlc"
 	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/ratelimit"
 	"github.com/antimatter007/go-backend/token"
 	"github.com/antimatter007/go-backend/util"
 	"github.com/antimatter007/go-backend/worker"
@@ -17,10 +18,12 @@ type Server struct {
 	store           db.Store
 	tokenMaker      token.Maker
 	taskDistributor worker.TaskDistributor
+	taskInspector   worker.TaskInspector
+	apiKeys         ratelimit.APIKeys
 }
 
 // NewServer creates a new gRPC server.
-func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) (*Server, error) {
+func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor, taskInspector worker.TaskInspector) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
@@ -31,6 +34,8 @@ func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDi
 		store:           store,
 		tokenMaker:      tokenMaker,
 		taskDistributor: taskDistributor,
+		taskInspector:   taskInspector,
+		apiKeys:         ratelimit.NewAPIKeys(config.APIKeys),
 	}
 
 	return server, nil
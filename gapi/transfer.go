@@ -0,0 +1,31 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/antimatter007/go-backend/webhook"
+	"github.com/antimatter007/go-backend/worker"
+	"github.com/hibiken/asynq"
+)
+
+// announceTransfer lets both account owners know about a committed transfer.
+// Failures are only logged, as the money has already moved.
+func (server *Server) announceTransfer(ctx context.Context, result db.TransferTxResult) {
+	taskPayload := &worker.PayloadSendTransferNotification{
+		TransferID: result.Transfer.ID,
+	}
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueDefault),
+	}
+
+	err := server.taskDistributor.DistributeTaskSendTransferNotification(ctx, taskPayload, opts...)
+	if err != nil {
+		requestid.Logger(ctx).Error().Err(err).Int64("transfer_id", result.Transfer.ID).Msg("failed to enqueue transfer notification")
+	}
+
+	server.publishWebhookEvent(ctx, webhook.EventTransferCreated,
+		[]string{result.FromAccount.Owner, result.ToAccount.Owner}, result.Transfer)
+}
@@ -0,0 +1,50 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/antimatter007/go-backend/worker"
+	"github.com/hibiken/asynq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// publishWebhookEvent enqueues an event for the webhook endpoints of the given users.
+// The change it describes is already committed, so a failure here is only logged.
+func (server *Server) publishWebhookEvent(ctx context.Context, event string, usernames []string, data interface{}) {
+	taskPayload, err := worker.NewPayloadPublishWebhookEvent(event, usernames, data)
+	if err != nil {
+		requestid.Logger(ctx).Error().Err(err).Str("event", event).Msg("failed to create webhook event")
+		return
+	}
+
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueDefault),
+	}
+
+	err = server.taskDistributor.DistributeTaskPublishWebhookEvent(ctx, taskPayload, opts...)
+	if err != nil {
+		requestid.Logger(ctx).Error().Err(err).Str("event", event).Msg("failed to enqueue webhook event")
+	}
+}
+
+// getOwnWebhookEndpoint loads an endpoint and makes sure it belongs to the user
+func (server *Server) getOwnWebhookEndpoint(ctx context.Context, id int64, username string) (db.WebhookEndpoint, error) {
+	endpoint, err := server.store.GetWebhookEndpoint(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return endpoint, status.Errorf(codes.NotFound, "webhook endpoint not found")
+		}
+		return endpoint, status.Errorf(codes.Internal, "failed to get webhook endpoint: %s", err)
+	}
+
+	if endpoint.Owner != username {
+		return endpoint, status.Errorf(codes.PermissionDenied, "webhook endpoint doesn't belong to the authenticated user")
+	}
+
+	return endpoint, nil
+}
@@ -0,0 +1,240 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Status values reported by the HTTP endpoints
+const (
+	StatusOK       = "ok"
+	StatusFailing  = "failing"
+	StatusDraining = "draining"
+)
+
+// Check reports whether a dependency can be used, it must return once ctx is done
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Report is the outcome of running every check once
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Checker runs the readiness checks and publishes the result
+// to the gRPC health service and the HTTP probe endpoints.
+type Checker struct {
+	services []string
+	timeout  time.Duration
+	server   *health.Server
+	draining atomic.Bool
+
+	mu     sync.Mutex
+	checks []namedCheck
+}
+
+// NewChecker creates a checker that reports on the overall server and the given gRPC services.
+// Every check is cut short after timeout.
+func NewChecker(timeout time.Duration, services ...string) *Checker {
+	checker := &Checker{
+		services: append([]string{""}, services...),
+		timeout:  timeout,
+		server:   health.NewServer(),
+	}
+
+	// nothing has been checked yet, the first Run decides whether the server is serving
+	checker.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return checker
+}
+
+// Add registers a readiness check under name
+func (checker *Checker) Add(name string, check Check) {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	checker.checks = append(checker.checks, namedCheck{name: name, check: check})
+}
+
+// HealthServer returns the grpc.health.v1.Health implementation kept up to date by Run
+func (checker *Checker) HealthServer() healthpb.HealthServer {
+	return checker.server
+}
+
+// Check runs every check concurrently and reports the ones that failed.
+// A draining checker is never ready, whatever its dependencies say.
+func (checker *Checker) Check(ctx context.Context) Report {
+	checker.mu.Lock()
+	checks := checker.checks
+	checker.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, checker.timeout)
+	defer cancel()
+
+	results := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c namedCheck) {
+			defer wg.Done()
+			results[i] = c.check(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]string, len(checks)),
+	}
+	for i, c := range checks {
+		if results[i] != nil {
+			report.Status = StatusFailing
+			report.Checks[c.name] = results[i].Error()
+			continue
+		}
+		report.Checks[c.name] = StatusOK
+	}
+
+	if checker.Draining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// Run checks the dependencies every interval and updates the gRPC serving status until ctx is done
+func (checker *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checker.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (checker *Checker) refresh(ctx context.Context) {
+	report := checker.Check(ctx)
+	if checker.Draining() {
+		return
+	}
+
+	if report.Status != StatusOK {
+		log.Warn().Interface("checks", report.Checks).Msg("readiness checks failed")
+		checker.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		return
+	}
+	checker.setServingStatus(healthpb.HealthCheckResponse_SERVING)
+}
+
+// Drain marks the server as NOT_SERVING for good, so that load balancers
+// stop sending it traffic while in-flight calls finish. It is safe to call more than once.
+func (checker *Checker) Drain() {
+	if checker.draining.Swap(true) {
+		return
+	}
+
+	log.Info().Msg("health status set to not serving for shutdown")
+	// Shutdown also ignores any later status update
+	checker.server.Shutdown()
+}
+
+// Draining reports whether Drain has been called
+func (checker *Checker) Draining() bool {
+	return checker.draining.Load()
+}
+
+func (checker *Checker) setServingStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, service := range checker.services {
+		checker.server.SetServingStatus(service, status)
+	}
+}
+
+// LivenessHandler answers as long as the process can serve HTTP, dependencies are not checked
+// so that a database outage does not get every pod restarted.
+func (checker *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, http.StatusOK, Report{Status: StatusOK})
+	})
+}
+
+// ReadinessHandler runs the checks and answers 503 when any of them fails or the server is draining
+func (checker *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Check(r.Context())
+
+		statusCode := http.StatusOK
+		if report.Status != StatusOK {
+			statusCode = http.StatusServiceUnavailable
+		}
+		writeReport(w, statusCode, report)
+	})
+}
+
+func writeReport(w http.ResponseWriter, statusCode int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	err := json.NewEncoder(w).Encode(report)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot write health report")
+	}
+}
+
+// Pinger is implemented by the dependencies that can be reached with a round trip
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingCheck checks a dependency by pinging it
+func PingCheck(pinger Pinger) Check {
+	return func(ctx context.Context) error {
+		return pinger.Ping(ctx)
+	}
+}
+
+// Querier runs a single row query, it is implemented by *pgxpool.Pool
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// MigrationCheck fails when the schema is older than expectedVersion
+// or when a migration was interrupted and left the schema dirty.
+// A newer schema is accepted, it belongs to a release being rolled out.
+func MigrationCheck(querier Querier, expectedVersion uint) Check {
+	return func(ctx context.Context) error {
+		var version int64
+		var dirty bool
+		err := querier.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+		if err != nil {
+			return fmt.Errorf("cannot read migration version: %w", err)
+		}
+
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if version < int64(expectedVersion) {
+			return fmt.Errorf("schema version %d is older than %d", version, expectedVersion)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,266 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const testService = "pb.SimpleBank"
+
+func passingCheck(ctx context.Context) error {
+	return nil
+}
+
+func failingCheck(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func servingStatus(t *testing.T, checker *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	res, err := checker.HealthServer().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return res.Status
+}
+
+func serveReadiness(t *testing.T, checker *Checker) (int, Report) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	checker.ReadinessHandler().ServeHTTP(recorder, request)
+
+	var report Report
+	err := json.Unmarshal(recorder.Body.Bytes(), &report)
+	require.NoError(t, err)
+	require.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+	return recorder.Code, report
+}
+
+func TestReadiness(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checks         map[string]Check
+		drain          bool
+		expectedCode   int
+		expectedStatus string
+		expectedChecks map[string]string
+	}{
+		{
+			name: "OK",
+			checks: map[string]Check{
+				"postgres": passingCheck,
+				"redis":    passingCheck,
+			},
+			expectedCode:   http.StatusOK,
+			expectedStatus: StatusOK,
+			expectedChecks: map[string]string{
+				"postgres": StatusOK,
+				"redis":    StatusOK,
+			},
+		},
+		{
+			name: "CheckFailed",
+			checks: map[string]Check{
+				"postgres": passingCheck,
+				"redis":    failingCheck,
+			},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: StatusFailing,
+			expectedChecks: map[string]string{
+				"postgres": StatusOK,
+				"redis":    "connection refused",
+			},
+		},
+		{
+			name: "Draining",
+			checks: map[string]Check{
+				"postgres": passingCheck,
+			},
+			drain:          true,
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: StatusDraining,
+			expectedChecks: map[string]string{
+				"postgres": StatusOK,
+			},
+		},
+		{
+			name: "Timeout",
+			checks: map[string]Check{
+				"postgres": func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: StatusFailing,
+			expectedChecks: map[string]string{
+				"postgres": context.DeadlineExceeded.Error(),
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			checker := NewChecker(10*time.Millisecond, testService)
+			for name, check := range tc.checks {
+				checker.Add(name, check)
+			}
+			if tc.drain {
+				checker.Drain()
+			}
+
+			code, report := serveReadiness(t, checker)
+			require.Equal(t, tc.expectedCode, code)
+			require.Equal(t, tc.expectedStatus, report.Status)
+			require.Equal(t, tc.expectedChecks, report.Checks)
+		})
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("postgres", failingCheck)
+	checker.Drain()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	checker.LivenessHandler().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestServingStatus(t *testing.T) {
+	healthy := true
+	checker := NewChecker(time.Second, testService)
+	checker.Add("redis", func(ctx context.Context) error {
+		if healthy {
+			return nil
+		}
+		return errors.New("connection refused")
+	})
+
+	// nothing is served until the first check
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, checker, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, checker, testService))
+
+	checker.refresh(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, checker, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, checker, testService))
+
+	healthy = false
+	checker.refresh(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, checker, testService))
+
+	healthy = true
+	checker.refresh(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, checker, testService))
+
+	// once draining, passing checks do not bring the server back
+	checker.Drain()
+	checker.Drain()
+	require.True(t, checker.Draining())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, checker, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, checker, testService))
+
+	checker.refresh(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, checker, testService))
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	checker := NewChecker(time.Second, testService)
+	checker.Add("postgres", passingCheck)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		checker.Run(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return servingStatus(t, checker, testService) == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the context was canceled")
+	}
+}
+
+type fakeRow struct {
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (row fakeRow) Scan(dest ...any) error {
+	if row.err != nil {
+		return row.err
+	}
+	*dest[0].(*int64) = row.version
+	*dest[1].(*bool) = row.dirty
+	return nil
+}
+
+type fakeQuerier struct {
+	row fakeRow
+}
+
+func (querier fakeQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return querier.row
+}
+
+func TestMigrationCheck(t *testing.T) {
+	testCases := []struct {
+		name      string
+		row       fakeRow
+		expectErr bool
+	}{
+		{
+			name: "UpToDate",
+			row:  fakeRow{version: 18},
+		},
+		{
+			name: "Newer",
+			row:  fakeRow{version: 19},
+		},
+		{
+			name:      "Older",
+			row:       fakeRow{version: 17},
+			expectErr: true,
+		},
+		{
+			name:      "Dirty",
+			row:       fakeRow{version: 18, dirty: true},
+			expectErr: true,
+		},
+		{
+			name:      "NoVersion",
+			row:       fakeRow{err: pgx.ErrNoRows},
+			expectErr: true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			check := MigrationCheck(fakeQuerier{row: tc.row}, 18)
+			err := check(context.Background())
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
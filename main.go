@@ -2,31 +2,62 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	db "github.com/antimatter007/go-backend/db/sqlc"
 	_ "github.com/antimatter007/go-backend/doc/statik"
 	"github.com/antimatter007/go-backend/gapi"
+	"github.com/antimatter007/go-backend/health"
 	"github.com/antimatter007/go-backend/mail"
 	"github.com/antimatter007/go-backend/pb"
+	"github.com/antimatter007/go-backend/ratelimit"
+	"github.com/antimatter007/go-backend/tlsconfig"
+	"github.com/antimatter007/go-backend/tracing"
 	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/webhook"
 	"github.com/antimatter007/go-backend/worker"
+	"github.com/go-redis/redis/v8"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rakyll/statik/fs"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
-	"google.golang.org/protobuf/encoding/protojson"
 )
 
+// tracingFlushTimeout bounds how long the spans left at shutdown are exported for
+const tracingFlushTimeout = 5 * time.Second
+
+// interruptSignals stop the application gracefully, Kubernetes sends SIGTERM before killing a pod
+var interruptSignals = []os.Signal{
+	os.Interrupt,
+	syscall.SIGTERM,
+	syscall.SIGINT,
+}
+
 func main() {
 	// Load configuration
 	config, err := util.LoadConfig(".")
@@ -34,20 +65,37 @@ func main() {
 		log.Fatal().Err(err).Msg("cannot load config")
 	}
 
+	if err := util.SetSupportedCurrencies(config.SupportedCurrencies); err != nil {
+		log.Fatal().Err(err).Msg("cannot set supported currencies")
+	}
+
 	// Configure logger for development
 	if config.Environment == "development" {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), interruptSignals...)
+	defer stop()
+
+	tracerProvider, err := tracing.NewTracerProvider(ctx, config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create tracer provider")
+	}
+
 	// Connect to the database
-	connPool, err := pgxpool.New(context.Background(), config.DBSource)
+	poolConfig, err := pgxpool.ParseConfig(config.DBSource)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot parse db source")
+	}
+	poolConfig.ConnConfig.Tracer = tracing.NewQueryTracer()
+
+	connPool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot connect to db")
 	}
-	defer connPool.Close()
 
 	// Run database migrations
-	runDBMigration(config.MigrationURL, config.DBSource)
+	migrationVersion := runDBMigration(config.MigrationURL, config.DBSource)
 
 	// Initialize store
 	store := db.NewStore(connPool)
@@ -58,16 +106,77 @@ func main() {
 		Password: config.RedisPassword,
 	}
 
+	// Export the connection pool and queue statistics along with the metrics of every package
+	prometheus.MustRegister(
+		db.NewPoolStatsCollector(connPool),
+		worker.NewQueueCollector(redisOpt),
+	)
+
 	// Initialize task distributor
 	taskDistributor := worker.NewRedisTaskDistributor(redisOpt)
+	taskInspector := worker.NewRedisTaskInspector(redisOpt)
+
+	// Readiness checks, published to the gRPC health service and the /readyz endpoint
+	healthChecker := health.NewChecker(config.HealthCheckTimeout, pb.SimpleBank_ServiceDesc.ServiceName)
+	healthChecker.Add("postgres", health.PingCheck(connPool))
+	healthChecker.Add("redis", func(ctx context.Context) error {
+		return taskInspector.Ping()
+	})
+	healthChecker.Add("migrations", health.MigrationCheck(connPool, migrationVersion))
+
+	// Rate limits are counted in Redis, and in memory while Redis is unavailable
+	var limiter *ratelimit.Limiter
+	if config.RateLimitEnabled {
+		rateLimitClient := redis.NewClient(&redis.Options{
+			Addr:     config.RedisAddress,
+			Password: config.RedisPassword,
+		})
+		defer rateLimitClient.Close()
+
+		rateLimitStore := ratelimit.NewFallbackStore(ratelimit.NewRedisStore(rateLimitClient), ratelimit.NewLocalStore())
+		limiter = ratelimit.NewLimiter(rateLimitStore, config.RateLimitDefault, config.RateLimitRules)
+	}
+
+	// Certificates are reloaded when renewed, the listeners serve plaintext without them
+	var tlsReloader *tlsconfig.Reloader
+	if config.TLSCertFile != "" {
+		tlsReloader, err = tlsconfig.NewReloader(config.TLSCertFile, config.TLSKeyFile, config.TLSClientCAFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot load TLS certificates")
+		}
+	}
+
+	// The gRPC server is served on its own port, or on the HTTP port with the gateway in multiplex mode
+	server, err := gapi.NewServer(config, store, taskDistributor, taskInspector)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create server")
+	}
+	grpcServer := newGrpcServer(config, server, healthChecker, limiter, tlsReloader)
+
+	// Every component runs in the wait group. The first one to fail cancels ctx like a signal does,
+	// and all of them drain before the database is closed.
+	waitGroup, ctx := errgroup.WithContext(ctx)
+
+	runTaskProcessor(ctx, waitGroup, config, redisOpt, store, taskDistributor)
+	runTaskScheduler(ctx, waitGroup, config, redisOpt)
+	runHealthChecker(ctx, waitGroup, config, healthChecker)
+	runTLSReloader(ctx, waitGroup, config, tlsReloader)
+	if config.GatewayMode != util.GatewayMultiplex {
+		runGrpcServer(ctx, waitGroup, config, grpcServer, healthChecker)
+	}
+	runGatewayServer(ctx, waitGroup, config, server, grpcServer, healthChecker, tlsReloader)
 
-	// Start task processor in a separate goroutine
-	go runTaskProcessor(config, redisOpt, store)
-	go runGatewayServer(config, store, taskDistributor)
-	runGrpcServer(config, store, taskDistributor)
+	err = waitGroup.Wait()
+	closeDB(connPool, config.DBCloseTimeout)
+	shutdownTracing(tracerProvider)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error from wait group")
+	}
+	log.Info().Msg("shutdown complete")
 }
 
-func runDBMigration(migrationURL string, dbSource string) {
+// runDBMigration migrates the database up and returns the version of the schema
+func runDBMigration(migrationURL string, dbSource string) uint {
 	migration, err := migrate.New(migrationURL, dbSource)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create new migrate instance")
@@ -77,63 +186,205 @@ func runDBMigration(migrationURL string, dbSource string) {
 		log.Fatal().Err(err).Msg("failed to run migrate up")
 	}
 
+	version, _, err := migration.Version()
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot read migration version")
+	}
+
 	log.Info().Msg("db migrated successfully")
+	return version
 }
 
-func runTaskProcessor(config util.Config, redisOpt asynq.RedisClientOpt, store db.Store) {
+func runTaskProcessor(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	redisOpt asynq.RedisClientOpt,
+	store db.Store,
+	taskDistributor worker.TaskDistributor,
+) {
 	mailer := mail.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer)
+	webhookClient := webhook.NewHTTPClient(config.WebhookTimeout, config.WebhookAllowPrivateNetworks)
+	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer, taskDistributor, webhookClient, config.WorkerShutdownTimeout)
+
 	log.Info().Msg("start task processor")
 	err := taskProcessor.Start()
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to start task processor")
 	}
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown task processor")
+
+		taskProcessor.Shutdown()
+		log.Info().Msg("task processor is stopped")
+		return nil
+	})
 }
 
-func runGrpcServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) {
-	server, err := gapi.NewServer(config, store, taskDistributor)
+func runTaskScheduler(ctx context.Context, waitGroup *errgroup.Group, config util.Config, redisOpt asynq.RedisClientOpt) {
+	periodicTasks, err := worker.NewPeriodicTasks(config)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create server")
+		log.Fatal().Err(err).Msg("cannot create periodic tasks")
+	}
+
+	taskScheduler := worker.NewRedisTaskScheduler(redisOpt, periodicTasks)
+	log.Info().Msg("start task scheduler")
+	err = taskScheduler.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to start task scheduler")
 	}
 
-	grpcLogger := grpc.UnaryInterceptor(gapi.GrpcLogger)
-	grpcServer := grpc.NewServer(grpcLogger)
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown task scheduler")
+
+		taskScheduler.Shutdown()
+		log.Info().Msg("task scheduler is stopped")
+		return nil
+	})
+}
+
+// newGrpcServer creates the gRPC server of the API, with its interceptors
+func newGrpcServer(
+	config util.Config,
+	server *gapi.Server,
+	healthChecker *health.Checker,
+	limiter *ratelimit.Limiter,
+	tlsReloader *tlsconfig.Reloader,
+) *grpc.Server {
+	interceptors := []grpc.UnaryServerInterceptor{
+		otelgrpc.UnaryServerInterceptor(),
+		gapi.GrpcRequestID,
+		gapi.GrpcMetrics,
+		gapi.GrpcLogger,
+	}
+	// rejected calls are still counted and logged
+	if limiter != nil {
+		interceptors = append(interceptors, server.GrpcRateLimit(limiter))
+	}
+	options := []grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...)}
+
+	// a multiplexed server is served over the TLS of the HTTP server
+	if tlsReloader != nil && config.GatewayMode != util.GatewayMultiplex {
+		creds := credentials.NewTLS(tlsReloader.ServerConfig(clientAuthType(config), "h2"))
+		options = append(options, grpc.Creds(creds))
+	}
+
+	grpcServer := grpc.NewServer(options...)
 	pb.RegisterSimpleBankServer(grpcServer, server)
+	healthpb.RegisterHealthServer(grpcServer, healthChecker.HealthServer())
 	reflection.Register(grpcServer)
+	return grpcServer
+}
 
+// clientAuthType lets internal callers authenticate with a client certificate, the others with their access token
+func clientAuthType(config util.Config) tls.ClientAuthType {
+	switch {
+	case config.TLSClientCertRequired:
+		return tls.RequireAndVerifyClientCert
+	case config.TLSClientCAFile != "":
+		return tls.VerifyClientCertIfGiven
+	default:
+		return tls.NoClientCert
+	}
+}
+
+func runGrpcServer(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	grpcServer *grpc.Server,
+	healthChecker *health.Checker,
+) {
 	listener, err := net.Listen("tcp", config.GRPCServerAddress)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create listener")
 	}
 
-	log.Info().Msgf("start gRPC server at %s", listener.Addr().String())
-	err = grpcServer.Serve(listener)
-	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start gRPC server")
-	}
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start gRPC server at %s", listener.Addr().String())
+		err = grpcServer.Serve(listener)
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			return fmt.Errorf("gRPC server failed to serve: %w", err)
+		}
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown gRPC server")
+		healthChecker.Drain()
+
+		// GracefulStop waits for all calls to return, so it is cut short by Stop after the timeout
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(config.GRPCShutdownTimeout):
+			log.Warn().Msg("gRPC calls still running after the shutdown timeout, closing them")
+			grpcServer.Stop()
+		}
+
+		log.Info().Msg("gRPC server is stopped")
+		return nil
+	})
 }
 
-func runGatewayServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) {
-	server, err := gapi.NewServer(config, store, taskDistributor)
+// dialLoopback connects the gateway to the gRPC server listening at address, over loopback when
+// the server listens on all interfaces. The server then trusts the client addresses forwarded by the gateway.
+func dialLoopback(ctx context.Context, address string, config util.Config, tlsReloader *tlsconfig.Reloader) *grpc.ClientConn {
+	host, port, err := net.SplitHostPort(address)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create server")
+		log.Fatal().Err(err).Msg("cannot parse server address")
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
 	}
 
-	jsonOption := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-		MarshalOptions: protojson.MarshalOptions{
-			UseProtoNames: true,
-		},
-		UnmarshalOptions: protojson.UnmarshalOptions{
-			DiscardUnknown: true,
-		},
-	})
+	creds := insecure.NewCredentials()
+	if tlsReloader != nil {
+		creds = credentials.NewTLS(tlsReloader.LoopbackClientConfig(config.TLSClientCertRequired))
+	}
 
-	grpcMux := runtime.NewServeMux(jsonOption)
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort(host, port),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot dial gRPC server")
+	}
+	return conn
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+func runGatewayServer(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	server *gapi.Server,
+	grpcServer *grpc.Server,
+	healthChecker *health.Checker,
+	tlsReloader *tlsconfig.Reloader,
+) {
+	grpcMux := gapi.NewGatewayMux()
 
-	err = pb.RegisterSimpleBankHandlerServer(ctx, grpcMux, server)
+	var conn *grpc.ClientConn
+	var err error
+	switch config.GatewayMode {
+	case util.GatewayInProcess:
+		err = pb.RegisterSimpleBankHandlerServer(ctx, grpcMux, server)
+	case util.GatewayDial:
+		conn = dialLoopback(ctx, config.GRPCServerAddress, config, tlsReloader)
+		err = pb.RegisterSimpleBankHandler(ctx, grpcMux, conn)
+	case util.GatewayMultiplex:
+		conn = dialLoopback(ctx, config.HTTPServerAddress, config, tlsReloader)
+		err = pb.RegisterSimpleBankHandler(ctx, grpcMux, conn)
+	}
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot register handler server")
 	}
@@ -147,17 +398,144 @@ func runGatewayServer(config util.Config, store db.Store, taskDistributor worker
 	}
 
 	swaggerHandler := http.StripPrefix("/swagger/", http.FileServer(statikFS))
-	mux.Handle("/swagger/", swaggerHandler)
+	mux.Handle("/swagger/", gapi.SwaggerSecurityHeaders(swaggerHandler))
+
+	corsPolicy := gapi.CORSPolicy{
+		AllowedOrigins:   config.CORSAllowedOrigins,
+		AllowedMethods:   config.CORSAllowedMethods,
+		AllowedHeaders:   config.CORSAllowedHeaders,
+		AllowCredentials: config.CORSAllowCredentials,
+		MaxAge:           config.CORSMaxAge,
+	}
+	browserHandler := gapi.HttpSecurityHeaders(config.HSTSMaxAge, gapi.HttpCORS(corsPolicy, mux))
+
+	// the probes and metrics are served outside of the logger, they are called every few seconds
+	rootMux := http.NewServeMux()
+	rootMux.Handle("/healthz", healthChecker.LivenessHandler())
+	rootMux.Handle("/readyz", healthChecker.ReadinessHandler())
+	rootMux.Handle("/metrics", promhttp.Handler())
+	// the span is named after the route once the gateway has matched the request
+	tracedHandler := otelhttp.NewHandler(gapi.HttpRequestID(gapi.HttpMetrics(gapi.HttpLogger(browserHandler))), "gateway",
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return r.Method
+		}),
+	)
+	rootMux.Handle("/", tracedHandler)
+
+	var handler http.Handler = rootMux
+	clientAuth := tls.NoClientCert
+	if config.GatewayMode == util.GatewayMultiplex {
+		handler = gapi.Multiplex(grpcServer, rootMux)
+		clientAuth = clientAuthType(config)
+		// gRPC needs HTTP/2, which is negotiated by TLS when it is enabled
+		if tlsReloader == nil {
+			handler = h2c.NewHandler(handler, &http2.Server{})
+		}
+	}
+
+	httpServer := &http.Server{
+		Handler: handler,
+		Addr:    config.HTTPServerAddress,
+	}
+	if tlsReloader != nil {
+		httpServer.TLSConfig = tlsReloader.ServerConfig(clientAuth, "h2", "http/1.1")
+	}
 
 	listener, err := net.Listen("tcp", config.HTTPServerAddress)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot create listener")
 	}
 
-	log.Info().Msgf("start HTTP gateway server at %s", listener.Addr().String())
-	handler := gapi.HttpLogger(mux)
-	err = http.Serve(listener, handler)
+	waitGroup.Go(func() error {
+		log.Info().Str("mode", config.GatewayMode).Msgf("start HTTP gateway server at %s", listener.Addr().String())
+		if httpServer.TLSConfig != nil {
+			// the certificate is served by the TLS config
+			err = httpServer.ServeTLS(listener, "", "")
+		} else {
+			err = httpServer.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("HTTP gateway server failed to serve: %w", err)
+		}
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown HTTP gateway server")
+		healthChecker.Drain()
+
+		// the parent ctx is already canceled, the timeout bounds how long requests may drain
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.HTTPShutdownTimeout)
+		defer cancel()
+
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Warn().Err(err).Msg("HTTP requests still running after the shutdown timeout, closing them")
+			httpServer.Close()
+		}
+
+		// the connection to the gRPC server is only closed once the requests using it are done
+		if conn != nil {
+			conn.Close()
+		}
+
+		log.Info().Msg("HTTP gateway server is stopped")
+		return nil
+	})
+}
+
+func runTLSReloader(ctx context.Context, waitGroup *errgroup.Group, config util.Config, tlsReloader *tlsconfig.Reloader) {
+	if tlsReloader == nil {
+		return
+	}
+
+	waitGroup.Go(func() error {
+		log.Info().Msg("start TLS certificate reloader")
+		tlsReloader.Run(ctx, config.TLSReloadInterval)
+
+		log.Info().Msg("TLS certificate reloader is stopped")
+		return nil
+	})
+}
+
+func runHealthChecker(ctx context.Context, waitGroup *errgroup.Group, config util.Config, healthChecker *health.Checker) {
+	waitGroup.Go(func() error {
+		log.Info().Msg("start health checker")
+		healthChecker.Run(ctx, config.HealthCheckInterval)
+		healthChecker.Drain()
+
+		log.Info().Msg("health checker is stopped")
+		return nil
+	})
+}
+
+// shutdownTracing flushes the spans that have not been exported yet
+func shutdownTracing(tracerProvider *sdktrace.TracerProvider) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancel()
+
+	err := tracerProvider.Shutdown(ctx)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start HTTP gateway server")
+		log.Warn().Err(err).Msg("cannot flush traces")
+		return
+	}
+	log.Info().Msg("tracer provider is stopped")
+}
+
+// closeDB closes the connection pool once the components are stopped.
+// Close waits for the connections in use to be released, so it is given up on after the timeout.
+func closeDB(connPool *pgxpool.Pool, timeout time.Duration) {
+	closed := make(chan struct{})
+	go func() {
+		connPool.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		log.Info().Msg("db connection pool is closed")
+	case <-time.After(timeout):
+		log.Warn().Msg("db connections still in use after the close timeout")
 	}
 }
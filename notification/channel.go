@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"context"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/mail"
+)
+
+// EmailChannel delivers notifications by email
+type EmailChannel struct {
+	mailer mail.EmailSender
+}
+
+// NewEmailChannel creates a new channel that sends notifications with mailer
+func NewEmailChannel(mailer mail.EmailSender) Channel {
+	return &EmailChannel{
+		mailer: mailer,
+	}
+}
+
+func (channel *EmailChannel) Name() string {
+	return ChannelEmail
+}
+
+func (channel *EmailChannel) Send(ctx context.Context, notification Notification) error {
+	to := []string{notification.Email}
+	return channel.mailer.SendEmail(notification.Title, notification.Content, to, nil, nil, nil)
+}
+
+// InAppChannel stores notifications in the user's in-app inbox
+type InAppChannel struct {
+	store db.Store
+}
+
+// NewInAppChannel creates a new channel that saves notifications to the database
+func NewInAppChannel(store db.Store) Channel {
+	return &InAppChannel{
+		store: store,
+	}
+}
+
+func (channel *InAppChannel) Name() string {
+	return ChannelInApp
+}
+
+func (channel *InAppChannel) Send(ctx context.Context, notification Notification) error {
+	_, err := channel.store.CreateNotification(ctx, db.CreateNotificationParams{
+		Username: notification.Username,
+		Event:    notification.Event,
+		Title:    notification.Title,
+		Content:  notification.Content,
+	})
+	return err
+}
@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+)
+
+// Events that users can be notified about
+const (
+	EventTransferSent     = "transfer.sent"
+	EventTransferReceived = "transfer.received"
+)
+
+// Channels through which notifications are delivered
+const (
+	ChannelEmail = "email"
+	ChannelInApp = "in_app"
+)
+
+// IsSupportedEvent returns true if users can be notified about the event
+func IsSupportedEvent(event string) bool {
+	switch event {
+	case EventTransferSent, EventTransferReceived:
+		return true
+	}
+	return false
+}
+
+// IsSupportedChannel returns true if notifications can be delivered through the channel
+func IsSupportedChannel(channel string) bool {
+	switch channel {
+	case ChannelEmail, ChannelInApp:
+		return true
+	}
+	return false
+}
+
+// Notification is a message about an event addressed to a single user
+type Notification struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Event    string `json:"event"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+}
+
+// Channel delivers notifications through one medium, such as email or the in-app inbox
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, notification Notification) error
+}
+
+// Dispatcher sends notifications through the channels the recipient has enabled for the event.
+// Channels are enabled by default, until the user opts out in their preferences.
+// Each channel is sent on its own, so that a failing channel can be retried without repeating the others.
+type Dispatcher struct {
+	store    db.Store
+	channels []Channel
+}
+
+// NewDispatcher creates a new dispatcher that delivers through the given channels
+func NewDispatcher(store db.Store, channels ...Channel) *Dispatcher {
+	return &Dispatcher{
+		store:    store,
+		channels: channels,
+	}
+}
+
+// EnabledChannels returns the names of the channels through which username wants to be notified about event
+func (dispatcher *Dispatcher) EnabledChannels(ctx context.Context, username string, event string) ([]string, error) {
+	preferences, err := dispatcher.store.ListNotificationPreferences(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification preferences: %w", err)
+	}
+
+	var channels []string
+	for _, channel := range dispatcher.channels {
+		if IsEnabled(preferences, event, channel.Name()) {
+			channels = append(channels, channel.Name())
+		}
+	}
+	return channels, nil
+}
+
+// Send sends the notification through a single channel, regardless of the recipient's preferences
+func (dispatcher *Dispatcher) Send(ctx context.Context, channelName string, notification Notification) error {
+	for _, channel := range dispatcher.channels {
+		if channel.Name() != channelName {
+			continue
+		}
+
+		if err := channel.Send(ctx, notification); err != nil {
+			return fmt.Errorf("failed to send %s notification: %w", channelName, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown notification channel %q", channelName)
+}
+
+// IsEnabled reports whether the event should be delivered through the channel
+func IsEnabled(preferences []db.NotificationPreference, event string, channel string) bool {
+	for _, preference := range preferences {
+		if preference.Event == event && preference.Channel == channel {
+			return preference.Enabled
+		}
+	}
+	return true
+}
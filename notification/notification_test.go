@@ -0,0 +1,98 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChannel struct {
+	name string
+	err  error
+	sent []Notification
+}
+
+func (channel *fakeChannel) Name() string {
+	return channel.name
+}
+
+func (channel *fakeChannel) Send(ctx context.Context, notification Notification) error {
+	channel.sent = append(channel.sent, notification)
+	return channel.err
+}
+
+func randomNotification() Notification {
+	return Notification{
+		Username: util.RandomOwner(),
+		Email:    util.RandomEmail(),
+		Event:    EventTransferReceived,
+		Title:    "Money received",
+		Content:  "You received 10 USD",
+	}
+}
+
+func TestEnabledChannelsDefaultsToAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	n := randomNotification()
+	store.EXPECT().
+		ListNotificationPreferences(gomock.Any(), gomock.Eq(n.Username)).
+		Times(1).
+		Return([]db.NotificationPreference{}, nil)
+
+	dispatcher := NewDispatcher(store, &fakeChannel{name: ChannelEmail}, &fakeChannel{name: ChannelInApp})
+
+	channels, err := dispatcher.EnabledChannels(context.Background(), n.Username, n.Event)
+	require.NoError(t, err)
+	require.Equal(t, []string{ChannelEmail, ChannelInApp}, channels)
+}
+
+func TestEnabledChannelsSkipsDisabledChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	n := randomNotification()
+	store.EXPECT().
+		ListNotificationPreferences(gomock.Any(), gomock.Eq(n.Username)).
+		Times(1).
+		Return([]db.NotificationPreference{
+			{Username: n.Username, Event: n.Event, Channel: ChannelEmail, Enabled: false},
+			{Username: n.Username, Event: EventTransferSent, Channel: ChannelInApp, Enabled: false},
+		}, nil)
+
+	dispatcher := NewDispatcher(store, &fakeChannel{name: ChannelEmail}, &fakeChannel{name: ChannelInApp})
+
+	channels, err := dispatcher.EnabledChannels(context.Background(), n.Username, n.Event)
+	require.NoError(t, err)
+	require.Equal(t, []string{ChannelInApp}, channels)
+}
+
+func TestSend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	n := randomNotification()
+	email := &fakeChannel{name: ChannelEmail, err: fmt.Errorf("smtp unavailable")}
+	inApp := &fakeChannel{name: ChannelInApp}
+	dispatcher := NewDispatcher(store, email, inApp)
+
+	err := dispatcher.Send(context.Background(), ChannelEmail, n)
+	require.ErrorContains(t, err, "smtp unavailable")
+	require.Len(t, email.sent, 1)
+	require.Empty(t, inApp.sent)
+
+	err = dispatcher.Send(context.Background(), ChannelInApp, n)
+	require.NoError(t, err)
+	require.Equal(t, []Notification{n}, inApp.sent)
+	require.Len(t, email.sent, 1)
+
+	err = dispatcher.Send(context.Background(), "sms", n)
+	require.ErrorContains(t, err, "unknown notification channel")
+}
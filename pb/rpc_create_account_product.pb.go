@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v4.24.3
+// source: rpc_create_account_product.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateAccountProductRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name            string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	InterestRateBps int32  `protobuf:"varint,2,opt,name=interest_rate_bps,json=interestRateBps,proto3" json:"interest_rate_bps,omitempty"`
+	TransferFeeFlat int64  `protobuf:"varint,3,opt,name=transfer_fee_flat,json=transferFeeFlat,proto3" json:"transfer_fee_flat,omitempty"`
+	TransferFeeBps  int32  `protobuf:"varint,4,opt,name=transfer_fee_bps,json=transferFeeBps,proto3" json:"transfer_fee_bps,omitempty"`
+}
+
+func (x *CreateAccountProductRequest) Reset() {
+	*x = CreateAccountProductRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_create_account_product_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateAccountProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountProductRequest) ProtoMessage() {}
+
+func (x *CreateAccountProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_create_account_product_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccountProductRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_create_account_product_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateAccountProductRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAccountProductRequest) GetInterestRateBps() int32 {
+	if x != nil {
+		return x.InterestRateBps
+	}
+	return 0
+}
+
+func (x *CreateAccountProductRequest) GetTransferFeeFlat() int64 {
+	if x != nil {
+		return x.TransferFeeFlat
+	}
+	return 0
+}
+
+func (x *CreateAccountProductRequest) GetTransferFeeBps() int32 {
+	if x != nil {
+		return x.TransferFeeBps
+	}
+	return 0
+}
+
+type CreateAccountProductResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Product *AccountProduct `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+}
+
+func (x *CreateAccountProductResponse) Reset() {
+	*x = CreateAccountProductResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_create_account_product_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateAccountProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountProductResponse) ProtoMessage() {}
+
+func (x *CreateAccountProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_create_account_product_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountProductResponse.ProtoReflect.Descriptor instead.
+func (*CreateAccountProductResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_create_account_product_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateAccountProductResponse) GetProduct() *AccountProduct {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+var File_rpc_create_account_product_proto protoreflect.FileDescriptor
+
+var file_rpc_create_account_product_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x72, 0x70, 0x63, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x15, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f,
+	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb3, 0x01,
+	0x0a, 0x1b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50,
+	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x2a, 0x0a, 0x11, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x5f, 0x62, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x52, 0x61, 0x74, 0x65, 0x42, 0x70, 0x73, 0x12, 0x2a, 0x0a,
+	0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x66, 0x6c,
+	0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x46, 0x65, 0x65, 0x46, 0x6c, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x62, 0x70, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x46, 0x65, 0x65,
+	0x42, 0x70, 0x73, 0x22, 0x4c, 0x0a, 0x1c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x74, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x61, 0x6e, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x74, 0x65, 0x72, 0x30, 0x30, 0x37, 0x2f, 0x67, 0x6f,
+	0x2d, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_create_account_product_proto_rawDescOnce sync.Once
+	file_rpc_create_account_product_proto_rawDescData = file_rpc_create_account_product_proto_rawDesc
+)
+
+func file_rpc_create_account_product_proto_rawDescGZIP() []byte {
+	file_rpc_create_account_product_proto_rawDescOnce.Do(func() {
+		file_rpc_create_account_product_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_create_account_product_proto_rawDescData)
+	})
+	return file_rpc_create_account_product_proto_rawDescData
+}
+
+var file_rpc_create_account_product_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_create_account_product_proto_goTypes = []interface{}{
+	(*CreateAccountProductRequest)(nil),  // 0: pb.CreateAccountProductRequest
+	(*CreateAccountProductResponse)(nil), // 1: pb.CreateAccountProductResponse
+	(*AccountProduct)(nil),               // 2: pb.AccountProduct
+}
+var file_rpc_create_account_product_proto_depIdxs = []int32{
+	2, // 0: pb.CreateAccountProductResponse.product:type_name -> pb.AccountProduct
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_create_account_product_proto_init() }
+func file_rpc_create_account_product_proto_init() {
+	if File_rpc_create_account_product_proto != nil {
+		return
+	}
+	file_account_product_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_create_account_product_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateAccountProductRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_create_account_product_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateAccountProductResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_create_account_product_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_create_account_product_proto_goTypes,
+		DependencyIndexes: file_rpc_create_account_product_proto_depIdxs,
+		MessageInfos:      file_rpc_create_account_product_proto_msgTypes,
+	}.Build()
+	File_rpc_create_account_product_proto = out.File
+	file_rpc_create_account_product_proto_rawDesc = nil
+	file_rpc_create_account_product_proto_goTypes = nil
+	file_rpc_create_account_product_proto_depIdxs = nil
+}
@@ -0,0 +1,238 @@
+package problems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ContentType is the media type of problem responses, defined by RFC 7807
+const ContentType = "application/problem+json"
+
+// typePrefix makes a problem type URI out of its code
+const typePrefix = "urn:simple-bank:problem:"
+
+// internalDetail replaces the detail of server errors, which may hold SQL or infrastructure messages
+const internalDetail = "an internal error occurred"
+
+// Code identifies a kind of problem. Codes are stable, clients should rely on them rather than on details.
+type Code string
+
+const (
+	CodeInvalidArgument    Code = "invalid_argument"
+	CodeFailedPrecondition Code = "failed_precondition"
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodePermissionDenied   Code = "permission_denied"
+	CodeNotFound           Code = "not_found"
+	CodeMethodNotAllowed   Code = "method_not_allowed"
+	CodeAlreadyExists      Code = "already_exists"
+	CodeConflict           Code = "conflict"
+	CodeReferenceNotFound  Code = "reference_not_found"
+	CodeRateLimited        Code = "rate_limited"
+	CodeCanceled           Code = "canceled"
+	CodeInternal           Code = "internal"
+	CodeUnimplemented      Code = "unimplemented"
+	CodeUpstreamError      Code = "upstream_error"
+	CodeUnavailable        Code = "unavailable"
+	CodeTimeout            Code = "timeout"
+)
+
+type codeInfo struct {
+	code   Code
+	status int
+	title  string
+}
+
+// codeInfos lists the codes with their status and title.
+// The first code of a status is the one given to errors that only have a status.
+var codeInfos = []codeInfo{
+	{CodeInvalidArgument, http.StatusBadRequest, "Invalid argument"},
+	{CodeFailedPrecondition, http.StatusBadRequest, "Failed precondition"},
+	{CodeUnauthenticated, http.StatusUnauthorized, "Unauthenticated"},
+	{CodePermissionDenied, http.StatusForbidden, "Permission denied"},
+	{CodeNotFound, http.StatusNotFound, "Not found"},
+	{CodeMethodNotAllowed, http.StatusMethodNotAllowed, "Method not allowed"},
+	{CodeAlreadyExists, http.StatusConflict, "Already exists"},
+	{CodeConflict, http.StatusConflict, "Conflict"},
+	{CodeReferenceNotFound, http.StatusUnprocessableEntity, "Referenced resource not found"},
+	{CodeRateLimited, http.StatusTooManyRequests, "Rate limit exceeded"},
+	{CodeCanceled, 499, "Request canceled"},
+	{CodeInternal, http.StatusInternalServerError, "Internal error"},
+	{CodeUnimplemented, http.StatusNotImplemented, "Not implemented"},
+	{CodeUpstreamError, http.StatusBadGateway, "Upstream error"},
+	{CodeUnavailable, http.StatusServiceUnavailable, "Service unavailable"},
+	{CodeTimeout, http.StatusGatewayTimeout, "Timeout"},
+}
+
+func lookupCode(code Code) codeInfo {
+	for _, info := range codeInfos {
+		if info.code == code {
+			return info
+		}
+	}
+	return lookupCode(CodeInternal)
+}
+
+// CodeForStatus returns the code of the errors that only have an HTTP status
+func CodeForStatus(status int) Code {
+	for _, info := range codeInfos {
+		if info.status == status {
+			return info.code
+		}
+	}
+	if status >= 400 && status < 500 {
+		return CodeInvalidArgument
+	}
+	return CodeInternal
+}
+
+// Problem is the body of every error response of the HTTP APIs, as defined by RFC 7807,
+// extended with a stable code, the request id, and the invalid parameters of validation errors.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          Code           `json:"code"`
+	RequestID     string         `json:"request_id,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+	// RetryAfter is the number of seconds to wait before retrying, it is sent as the Retry-After header
+	RetryAfter int `json:"-"`
+}
+
+// InvalidParam explains why a parameter of the request is invalid
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// New creates a problem of code, with the status and title of the code
+func New(code Code, detail string) *Problem {
+	info := lookupCode(code)
+	return &Problem{
+		Type:   typePrefix + string(code),
+		Title:  info.title,
+		Status: info.status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// FromError maps the errors that mean the same thing wherever they happen:
+// missing records, unique and foreign key violations, and validation errors.
+// It returns false for the other errors, whose meaning depends on the caller.
+func FromError(err error) (*Problem, bool) {
+	var validationErrors validator.ValidationErrors
+
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return New(CodeNotFound, "record not found"), true
+	case db.ErrorCode(err) == db.UniqueViolation:
+		return New(CodeAlreadyExists, "a record with the same unique values already exists"), true
+	case db.ErrorCode(err) == db.ForeignKeyViolation:
+		return New(CodeReferenceNotFound, "a referenced record does not exist"), true
+	case errors.As(err, &validationErrors):
+		p := New(CodeInvalidArgument, "invalid parameters")
+		for _, fieldErr := range validationErrors {
+			p.InvalidParams = append(p.InvalidParams, InvalidParam{
+				Name:   fieldErr.Field(),
+				Reason: validationReason(fieldErr),
+			})
+		}
+		return p, true
+	}
+	return nil, false
+}
+
+func validationReason(fieldErr validator.FieldError) string {
+	if fieldErr.Param() != "" {
+		return fmt.Sprintf("must satisfy %s=%s", fieldErr.Tag(), fieldErr.Param())
+	}
+	return fmt.Sprintf("must satisfy %s", fieldErr.Tag())
+}
+
+// grpcCodes maps the codes of gRPC statuses, in the same way as the status codes of the gateway
+var grpcCodes = map[codes.Code]Code{
+	codes.InvalidArgument:    CodeInvalidArgument,
+	codes.OutOfRange:         CodeInvalidArgument,
+	codes.FailedPrecondition: CodeFailedPrecondition,
+	codes.Unauthenticated:    CodeUnauthenticated,
+	codes.PermissionDenied:   CodePermissionDenied,
+	codes.NotFound:           CodeNotFound,
+	codes.AlreadyExists:      CodeAlreadyExists,
+	codes.Aborted:            CodeConflict,
+	codes.ResourceExhausted:  CodeRateLimited,
+	codes.Canceled:           CodeCanceled,
+	codes.Unimplemented:      CodeUnimplemented,
+	codes.Unavailable:        CodeUnavailable,
+	codes.DeadlineExceeded:   CodeTimeout,
+}
+
+// FromStatus maps a gRPC status, along with its bad request and retry details
+func FromStatus(st *status.Status) *Problem {
+	code, ok := grpcCodes[st.Code()]
+	if !ok {
+		code = CodeInternal
+	}
+
+	p := New(code, st.Message())
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				p.InvalidParams = append(p.InvalidParams, InvalidParam{
+					Name:   violation.GetField(),
+					Reason: violation.GetDescription(),
+				})
+			}
+		case *errdetails.RetryInfo:
+			p.RetryAfter = int(math.Ceil(detail.GetRetryDelay().AsDuration().Seconds()))
+		}
+	}
+	return p
+}
+
+// Internal reports whether the problem is an error of the server, whose detail must not be returned
+func (p *Problem) Internal() bool {
+	return p.Status >= http.StatusInternalServerError
+}
+
+// SafeDetail returns the detail that can be sent to clients, the detail of server errors is replaced by a generic message
+func (p *Problem) SafeDetail() string {
+	if p.Internal() {
+		return internalDetail
+	}
+	return p.Detail
+}
+
+// Write writes the problem as the response. The detail of server errors is replaced by a generic message,
+// callers should log the original error.
+func (p *Problem) Write(w http.ResponseWriter) {
+	p.Detail = p.SafeDetail()
+
+	w.Header().Set("Content-Type", ContentType)
+	if p.RetryAfter > 0 {
+		w.Header().Set("Retry-After", fmt.Sprint(p.RetryAfter))
+	}
+	if p.Status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, internalDetail, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(p.Status)
+	w.Write(body)
+}
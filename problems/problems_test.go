@@ -0,0 +1,150 @@
+package problems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestFromError(t *testing.T) {
+	type request struct {
+		Username string `validate:"required,alphanum"`
+		Password string `validate:"min=6"`
+	}
+	validationErr := validator.New().Struct(request{Username: "bob#", Password: "abc"})
+
+	testCases := []struct {
+		name          string
+		err           error
+		ok            bool
+		code          Code
+		status        int
+		invalidParams []InvalidParam
+	}{
+		{
+			name:   "RecordNotFound",
+			err:    fmt.Errorf("get account: %w", db.ErrRecordNotFound),
+			ok:     true,
+			code:   CodeNotFound,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "UniqueViolation",
+			err:    db.ErrUniqueViolation,
+			ok:     true,
+			code:   CodeAlreadyExists,
+			status: http.StatusConflict,
+		},
+		{
+			name:   "ForeignKeyViolation",
+			err:    &pgconn.PgError{Code: db.ForeignKeyViolation},
+			ok:     true,
+			code:   CodeReferenceNotFound,
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "ValidationErrors",
+			err:    validationErr,
+			ok:     true,
+			code:   CodeInvalidArgument,
+			status: http.StatusBadRequest,
+			invalidParams: []InvalidParam{
+				{Name: "Username", Reason: "must satisfy alphanum"},
+				{Name: "Password", Reason: "must satisfy min=6"},
+			},
+		},
+		{
+			name: "OtherError",
+			err:  errors.New("insufficient funds"),
+			ok:   false,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			p, ok := FromError(tc.err)
+			require.Equal(t, tc.ok, ok)
+			if !ok {
+				return
+			}
+			require.Equal(t, tc.code, p.Code)
+			require.Equal(t, tc.status, p.Status)
+			require.Equal(t, tc.invalidParams, p.InvalidParams)
+			require.NotContains(t, p.Detail, "SQLSTATE")
+		})
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	st, err := status.New(codes.ResourceExhausted, "rate limit exceeded").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)},
+	)
+	require.NoError(t, err)
+
+	p := FromStatus(st)
+	require.Equal(t, CodeRateLimited, p.Code)
+	require.Equal(t, http.StatusTooManyRequests, p.Status)
+	require.Equal(t, 2, p.RetryAfter)
+
+	p = FromStatus(status.New(codes.DataLoss, "corrupted"))
+	require.Equal(t, CodeInternal, p.Code)
+	require.Equal(t, http.StatusInternalServerError, p.Status)
+}
+
+func TestCodeForStatus(t *testing.T) {
+	require.Equal(t, CodeInvalidArgument, CodeForStatus(http.StatusBadRequest))
+	require.Equal(t, CodeAlreadyExists, CodeForStatus(http.StatusConflict))
+	require.Equal(t, CodeUpstreamError, CodeForStatus(http.StatusBadGateway))
+	require.Equal(t, CodeInvalidArgument, CodeForStatus(http.StatusTeapot))
+	require.Equal(t, CodeInternal, CodeForStatus(http.StatusInsufficientStorage))
+}
+
+func TestSafeDetail(t *testing.T) {
+	require.Equal(t, internalDetail, New(CodeInternal, "pq: relation \"accounts\" does not exist").SafeDetail())
+	require.Equal(t, "insufficient funds", New(CodeInvalidArgument, "insufficient funds").SafeDetail())
+}
+
+func TestWrite(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	p := New(CodeInternal, "pq: relation \"accounts\" does not exist")
+	p.RequestID = "req-1"
+	p.Write(recorder)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	require.Equal(t, ContentType, recorder.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, map[string]interface{}{
+		"type":       "urn:simple-bank:problem:internal",
+		"title":      "Internal error",
+		"status":     float64(http.StatusInternalServerError),
+		"detail":     internalDetail,
+		"code":       "internal",
+		"request_id": "req-1",
+	}, body)
+
+	recorder = httptest.NewRecorder()
+	p = New(CodeRateLimited, "rate limit exceeded")
+	p.RetryAfter = 5
+	p.Write(recorder)
+
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "5", recorder.Header().Get("Retry-After"))
+	require.Contains(t, recorder.Body.String(), "rate limit exceeded")
+}
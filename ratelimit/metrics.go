@@ -0,0 +1,25 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var rejectedRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "rate_limit",
+		Name:      "rejected_total",
+		Help:      "Number of requests rejected for exceeding their rate limit, by method.",
+	},
+	[]string{"method"},
+)
+
+var storeFallbacks = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "rate_limit",
+		Name:      "store_fallbacks_total",
+		Help:      "Number of requests counted locally because the shared rate limit store failed.",
+	},
+)
@@ -0,0 +1,196 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyKind selects what identifies the caller a rule counts requests for
+type KeyKind string
+
+const (
+	KeyIP     KeyKind = "ip"
+	KeyUser   KeyKind = "user"
+	KeyAPIKey KeyKind = "api_key"
+)
+
+// Limit allows Requests requests every Per, in bursts of up to Requests
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Rule is the limit of a method, and the key it is counted by.
+// The zero rule does not limit anything.
+type Rule struct {
+	Limit Limit
+	Key   KeyKind
+}
+
+// Unlimited reports whether the rule lets every request through
+func (rule Rule) Unlimited() bool {
+	return rule.Limit.Requests <= 0
+}
+
+// Identity describes the caller of a request
+type Identity struct {
+	ClientIP string
+	Username string // empty when the request is not authenticated
+	APIKey   string // empty when the request has no issued API key, see APIKeys
+}
+
+// APIKeys are the API keys issued to partners, stored by hash.
+// Only issued keys identify a caller, otherwise callers could get a new bucket with every random key.
+type APIKeys map[[sha256.Size]byte]struct{}
+
+// NewAPIKeys creates the set of issued keys
+func NewAPIKeys(keys []string) APIKeys {
+	apiKeys := make(APIKeys, len(keys))
+	for _, key := range keys {
+		apiKeys[sha256.Sum256([]byte(key))] = struct{}{}
+	}
+	return apiKeys
+}
+
+// Authenticate returns key when it was issued, and an empty key otherwise,
+// so that the requests of unknown keys are counted by client IP.
+func (apiKeys APIKeys) Authenticate(key string) string {
+	if key == "" {
+		return ""
+	}
+	if _, ok := apiKeys[sha256.Sum256([]byte(key))]; !ok {
+		return ""
+	}
+	return key
+}
+
+// Result tells whether a request is allowed, and when to retry if it is not
+type Result struct {
+	Allowed    bool
+	Limit      Limit
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store counts the requests of every key, Allow takes one token from the bucket of key
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Limiter applies the rule of each method to its callers
+type Limiter struct {
+	store       Store
+	defaultRule Rule
+	rules       map[string]Rule
+}
+
+// NewLimiter creates a limiter that applies rules by method name, and defaultRule to the other methods
+func NewLimiter(store Store, defaultRule Rule, rules map[string]Rule) *Limiter {
+	return &Limiter{
+		store:       store,
+		defaultRule: defaultRule,
+		rules:       rules,
+	}
+}
+
+// Allow takes a token from the bucket of the caller for method
+func (limiter *Limiter) Allow(ctx context.Context, method string, identity Identity) (Result, error) {
+	rule, ok := limiter.rules[method]
+	if !ok {
+		rule = limiter.defaultRule
+	}
+	if rule.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	result, err := limiter.store.Allow(ctx, bucketKey(method, rule.Key, identity), rule.Limit)
+	if err != nil {
+		return Result{}, err
+	}
+	if !result.Allowed {
+		rejectedRequests.WithLabelValues(method).Inc()
+	}
+	return result, nil
+}
+
+// bucketKey names the bucket of the caller for method.
+// Requests without a username or an API key are counted by client IP,
+// and API keys are hashed so that they are never written to the store.
+func bucketKey(method string, kind KeyKind, identity Identity) string {
+	switch {
+	case kind == KeyUser && identity.Username != "":
+		return fmt.Sprintf("%s:user:%s", method, identity.Username)
+	case kind == KeyAPIKey && identity.APIKey != "":
+		sum := sha256.Sum256([]byte(identity.APIKey))
+		return fmt.Sprintf("%s:api_key:%s", method, hex.EncodeToString(sum[:16]))
+	default:
+		return fmt.Sprintf("%s:ip:%s", method, identity.ClientIP)
+	}
+}
+
+// ParseRule parses a rule written as "requests/period@key", such as "5/1m@ip".
+// The key defaults to ip, and "none" is a rule that does not limit anything.
+func ParseRule(spec string) (Rule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "none" {
+		return Rule{}, nil
+	}
+
+	rule := Rule{Key: KeyIP}
+	if i := strings.LastIndexByte(spec, '@'); i >= 0 {
+		rule.Key = KeyKind(spec[i+1:])
+		spec = spec[:i]
+	}
+	switch rule.Key {
+	case KeyIP, KeyUser, KeyAPIKey:
+	default:
+		return Rule{}, fmt.Errorf("unknown key %q, must be ip, user or api_key", rule.Key)
+	}
+
+	requests, period, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("limit %q must be written as requests/period", spec)
+	}
+
+	var err error
+	rule.Limit.Requests, err = strconv.Atoi(requests)
+	if err != nil || rule.Limit.Requests <= 0 {
+		return Rule{}, fmt.Errorf("invalid number of requests %q: must be a positive integer", requests)
+	}
+
+	rule.Limit.Per, err = time.ParseDuration(period)
+	if err != nil || rule.Limit.Per <= 0 {
+		return Rule{}, fmt.Errorf("invalid period %q: must be a positive duration", period)
+	}
+
+	return rule, nil
+}
+
+// ParseRules parses a comma separated list of method=rule, such as "LoginUser=5/1m@ip,CreateUser=3/1m@ip"
+func ParseRules(specs string) (map[string]Rule, error) {
+	rules := make(map[string]Rule)
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		method, ruleSpec, ok := strings.Cut(spec, "=")
+		method = strings.TrimSpace(method)
+		if !ok || method == "" {
+			return nil, fmt.Errorf("rule %q must be written as method=rule", spec)
+		}
+
+		rule, err := ParseRule(ruleSpec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule of %s: %w", method, err)
+		}
+		rules[method] = rule
+	}
+	return rules, nil
+}
@@ -0,0 +1,225 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     string
+		expected Rule
+		isErr    bool
+	}{
+		{
+			name:     "DefaultKey",
+			spec:     "5/1m",
+			expected: Rule{Limit: Limit{Requests: 5, Per: time.Minute}, Key: KeyIP},
+		},
+		{
+			name:     "UserKey",
+			spec:     " 20/1s@user ",
+			expected: Rule{Limit: Limit{Requests: 20, Per: time.Second}, Key: KeyUser},
+		},
+		{
+			name:     "APIKey",
+			spec:     "100/1h@api_key",
+			expected: Rule{Limit: Limit{Requests: 100, Per: time.Hour}, Key: KeyAPIKey},
+		},
+		{
+			name:     "None",
+			spec:     "none",
+			expected: Rule{},
+		},
+		{
+			name:  "UnknownKey",
+			spec:  "5/1m@session",
+			isErr: true,
+		},
+		{
+			name:  "MissingPeriod",
+			spec:  "5",
+			isErr: true,
+		},
+		{
+			name:  "ZeroRequests",
+			spec:  "0/1m",
+			isErr: true,
+		},
+		{
+			name:  "InvalidPeriod",
+			spec:  "5/minute",
+			isErr: true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := ParseRule(tc.spec)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, rule)
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("LoginUser=5/1m@ip, CreateUser=3/1m,,ListAccounts=none")
+	require.NoError(t, err)
+	require.Equal(t, map[string]Rule{
+		"LoginUser":    {Limit: Limit{Requests: 5, Per: time.Minute}, Key: KeyIP},
+		"CreateUser":   {Limit: Limit{Requests: 3, Per: time.Minute}, Key: KeyIP},
+		"ListAccounts": {},
+	}, rules)
+
+	_, err = ParseRules("LoginUser")
+	require.Error(t, err)
+
+	_, err = ParseRules("LoginUser=5/1m@nobody")
+	require.Error(t, err)
+}
+
+func TestBucketKey(t *testing.T) {
+	identity := Identity{ClientIP: "10.0.0.1", Username: "alice", APIKey: "secret-key"}
+
+	require.Equal(t, "LoginUser:ip:10.0.0.1", bucketKey("LoginUser", KeyIP, identity))
+	require.Equal(t, "GetAccount:user:alice", bucketKey("GetAccount", KeyUser, identity))
+
+	key := bucketKey("GetAccount", KeyAPIKey, identity)
+	require.NotContains(t, key, identity.APIKey)
+	require.Equal(t, key, bucketKey("GetAccount", KeyAPIKey, identity))
+
+	// anonymous callers are counted by client IP
+	require.Equal(t, "GetAccount:ip:10.0.0.1", bucketKey("GetAccount", KeyUser, Identity{ClientIP: "10.0.0.1"}))
+	require.Equal(t, "GetAccount:ip:10.0.0.1", bucketKey("GetAccount", KeyAPIKey, Identity{ClientIP: "10.0.0.1"}))
+}
+
+func TestAPIKeys(t *testing.T) {
+	apiKeys := NewAPIKeys([]string{"key-1", "key-2"})
+
+	require.Equal(t, "key-1", apiKeys.Authenticate("key-1"))
+	require.Equal(t, "key-2", apiKeys.Authenticate("key-2"))
+	require.Empty(t, apiKeys.Authenticate("key-3"))
+	require.Empty(t, apiKeys.Authenticate(""))
+
+	// without issued keys every caller is counted by client IP
+	require.Empty(t, NewAPIKeys(nil).Authenticate("key-1"))
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(
+		NewLocalStore(),
+		Rule{Limit: Limit{Requests: 2, Per: time.Minute}, Key: KeyUser},
+		map[string]Rule{
+			"LoginUser":  {Limit: Limit{Requests: 1, Per: time.Minute}, Key: KeyIP},
+			"GetAccount": {},
+		},
+	)
+	ctx := context.Background()
+	alice := Identity{ClientIP: "10.0.0.1", Username: "alice"}
+	bob := Identity{ClientIP: "10.0.0.1", Username: "bob"}
+
+	result, err := limiter.Allow(ctx, "LoginUser", alice)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// LoginUser is counted by IP, which alice and bob share
+	result, err = limiter.Allow(ctx, "LoginUser", bob)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Positive(t, result.RetryAfter)
+
+	// the default rule is counted by user
+	for i := 0; i < 2; i++ {
+		result, err = limiter.Allow(ctx, "ListAccounts", alice)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+	result, err = limiter.Allow(ctx, "ListAccounts", alice)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+
+	result, err = limiter.Allow(ctx, "ListAccounts", bob)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// GetAccount is not limited
+	for i := 0; i < 10; i++ {
+		result, err = limiter.Allow(ctx, "GetAccount", alice)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewLocalStore()
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Per: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Allow(ctx, "key", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Second, result.RetryAfter)
+
+	// one token is refilled every second
+	now = now.Add(500 * time.Millisecond)
+	result, err = store.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	result, err = store.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// other keys have their own bucket
+	result, err = store.Allow(ctx, "other", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// buckets that refilled are swept
+	now = now.Add(time.Hour)
+	_, err = store.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	require.Len(t, store.buckets, 1)
+}
+
+type failingStore struct{}
+
+func (failingStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errors.New("connection refused")
+}
+
+func TestFallbackStore(t *testing.T) {
+	store := NewFallbackStore(failingStore{}, NewLocalStore())
+	limit := Limit{Requests: 1, Per: time.Minute}
+
+	result, err := store.Allow(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	result, err = store.Allow(context.Background(), "key", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisKeyPrefix namespaces the buckets in Redis
+const redisKeyPrefix = "rate_limit:"
+
+// tokenBucketScript refills and takes a token from the bucket in KEYS[1] atomically.
+// ARGV[1] is the burst and ARGV[2] the microseconds it takes to refill one token.
+// The clock of Redis is used, so that all instances of the service agree on the time.
+// It returns whether the token was taken, the tokens left, and the microseconds to wait for the next one.
+var tokenBucketScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local per_token = tonumber(ARGV[2])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+
+if now > updated then
+	tokens = math.min(burst, tokens + (now - updated) / per_token)
+end
+
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry_after = math.ceil((1 - tokens) * per_token)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * per_token / 1000))
+return {allowed, math.floor(tokens), retry_after}
+`)
+
+// RedisStore keeps the buckets in Redis, so that all instances of the service share them
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisStore creates a store that keeps its buckets in the Redis of client
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{
+		client: client,
+	}
+}
+
+func (store *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	perToken := limit.Per / time.Duration(limit.Requests)
+	if perToken < time.Microsecond {
+		perToken = time.Microsecond
+	}
+
+	values, err := tokenBucketScript.Run(ctx, store.client, []string{redisKeyPrefix + key}, limit.Requests, perToken.Microseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("cannot take token from redis: %w", err)
+	}
+	if len(values) != 3 {
+		return Result{}, fmt.Errorf("unexpected token bucket reply %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	store := NewRedisStore(client)
+	ctx := context.Background()
+	limit := Limit{Requests: 2, Per: 2 * time.Second}
+
+	for i := 1; i >= 0; i-- {
+		result, err := store.Allow(ctx, "LoginUser:ip:10.0.0.1", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Allow(ctx, "LoginUser:ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Positive(t, result.RetryAfter)
+	require.LessOrEqual(t, result.RetryAfter, time.Second)
+
+	require.True(t, server.Exists(redisKeyPrefix+"LoginUser:ip:10.0.0.1"))
+	require.Positive(t, server.TTL(redisKeyPrefix+"LoginUser:ip:10.0.0.1"))
+
+	result, err = store.Allow(ctx, "LoginUser:ip:10.0.0.2", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
+
+func TestRedisStoreDown(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+	server.Close()
+
+	_, err := NewRedisStore(client).Allow(context.Background(), "key", Limit{Requests: 1, Per: time.Second})
+	require.Error(t, err)
+}
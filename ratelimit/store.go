@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/antimatter007/go-backend/requestid"
+)
+
+// takeToken refills a bucket that held tokens elapsed ago, and takes one token from it if it can.
+// It returns the tokens left in the bucket.
+func takeToken(limit Limit, tokens float64, elapsed time.Duration) (float64, Result) {
+	burst := float64(limit.Requests)
+	perToken := limit.Per / time.Duration(limit.Requests)
+
+	if elapsed > 0 {
+		tokens = math.Min(burst, tokens+float64(elapsed)/float64(perToken))
+	}
+
+	result := Result{Limit: limit}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - tokens) * float64(perToken)))
+	}
+	result.Remaining = int(tokens)
+	return tokens, result
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// LocalStore keeps the buckets in memory, so each instance of the service counts its own requests
+type LocalStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// localSweepInterval is how often the full buckets are removed from a local store
+const localSweepInterval = time.Minute
+
+// NewLocalStore creates an empty in-memory store
+func NewLocalStore() *LocalStore {
+	return &LocalStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (store *LocalStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := store.now()
+	store.sweep(now)
+
+	b, ok := store.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updated: now}
+		store.buckets[key] = b
+	}
+
+	var result Result
+	b.tokens, result = takeToken(limit, b.tokens, now.Sub(b.updated))
+	b.updated = now
+	b.limit = limit
+	return result, nil
+}
+
+// sweep removes the buckets that have refilled, a new bucket starts full anyway
+func (store *LocalStore) sweep(now time.Time) {
+	if now.Sub(store.lastSweep) < localSweepInterval {
+		return
+	}
+	store.lastSweep = now
+
+	for key, b := range store.buckets {
+		if now.Sub(b.updated) >= b.limit.Per {
+			delete(store.buckets, key)
+		}
+	}
+}
+
+// FallbackStore counts requests in a primary store, and in a fallback store while the primary one fails.
+// It is meant to keep rate limiting working from memory when Redis is unavailable.
+type FallbackStore struct {
+	primary  Store
+	fallback Store
+}
+
+// NewFallbackStore creates a store that falls back to fallback on the errors of primary
+func NewFallbackStore(primary Store, fallback Store) *FallbackStore {
+	return &FallbackStore{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (store *FallbackStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	result, err := store.primary.Allow(ctx, key, limit)
+	if err == nil {
+		return result, nil
+	}
+
+	storeFallbacks.Inc()
+	requestid.Logger(ctx).Warn().Err(err).Msg("rate limit store failed, counting requests locally")
+	return store.fallback.Allow(ctx, key, limit)
+}
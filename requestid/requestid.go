@@ -0,0 +1,76 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/metadata"
+)
+
+// Header carries the request id of HTTP requests and responses
+const Header = "X-Request-ID"
+
+// MetadataKey carries the request id in gRPC metadata, the gateway forwards Header under this key
+const MetadataKey = "x-request-id"
+
+// maxLength bounds the request ids accepted from clients
+const maxLength = 128
+
+type contextKey struct{}
+
+// New generates a request id
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether a request id sent by a client can be used as is.
+// Ids are written to the logs and to response headers, so only short ids of plain characters are accepted.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx that carries the request id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id carried by ctx, or by its incoming gRPC metadata
+// for the calls that the gateway makes without going through the interceptors.
+// It returns an empty string when there is none.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 && Valid(values[0]) {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// Logger returns the global logger with the request id of ctx attached to every line
+func Logger(ctx context.Context) *zerolog.Logger {
+	id := FromContext(ctx)
+	if id == "" {
+		return &log.Logger
+	}
+
+	logger := log.With().Str("request_id", id).Logger()
+	return &logger
+}
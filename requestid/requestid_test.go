@@ -0,0 +1,68 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestValid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		id    string
+		valid bool
+	}{
+		{"UUID", New(), true},
+		{"Plain", "req_123.abc:4", true},
+		{"Empty", "", false},
+		{"TooLong", strings.Repeat("a", maxLength+1), false},
+		{"Space", "req 123", false},
+		{"Newline", "req\n{\"level\":\"error\"}", false},
+		{"NonASCII", "réq", false},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.valid, Valid(tc.id))
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	require.Empty(t, FromContext(context.Background()))
+
+	ctx := NewContext(context.Background(), "req-1")
+	require.Equal(t, "req-1", FromContext(ctx))
+
+	// the gateway calls the handlers with the id in the incoming metadata only
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "req-2"))
+	require.Equal(t, "req-2", FromContext(ctx))
+
+	// the context value set by an interceptor wins over the metadata
+	require.Equal(t, "req-3", FromContext(NewContext(ctx, "req-3")))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "bad id"))
+	require.Empty(t, FromContext(ctx))
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	previous := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = previous }()
+
+	Logger(NewContext(context.Background(), "req-1")).Info().Msg("with id")
+	require.Contains(t, buf.String(), `"request_id":"req-1"`)
+
+	buf.Reset()
+	Logger(context.Background()).Info().Msg("without id")
+	require.NotContains(t, buf.String(), "request_id")
+}
@@ -0,0 +1,190 @@
+package tlsconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Reloader holds a server certificate, and optionally a pool of client CAs, loaded from files.
+// The files are reloaded when they change, so that renewed certificates are served by new
+// connections without a restart. Connections that are already established keep their certificate.
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mutex       sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	modTimes    map[string]time.Time
+}
+
+// NewReloader loads the key pair in certFile and keyFile, and the client CAs in clientCAFile if it is not empty
+func NewReloader(certFile string, keyFile string, clientCAFile string) (*Reloader, error) {
+	reloader := &Reloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+
+	_, err := reloader.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+func (reloader *Reloader) files() []string {
+	files := []string{reloader.certFile, reloader.keyFile}
+	if reloader.clientCAFile != "" {
+		files = append(files, reloader.clientCAFile)
+	}
+	return files
+}
+
+// Reload loads the files again if any of them changed since they were last loaded, and reports whether it did.
+// The files are kept as they were if they cannot be loaded, such as while a key pair is half renewed.
+func (reloader *Reloader) Reload() (bool, error) {
+	modTimes := make(map[string]time.Time)
+	for _, file := range reloader.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, fmt.Errorf("cannot stat %s: %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	reloader.mutex.RLock()
+	changed := !sameModTimes(reloader.modTimes, modTimes)
+	reloader.mutex.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("cannot load key pair: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if reloader.clientCAFile != "" {
+		pem, err := os.ReadFile(reloader.clientCAFile)
+		if err != nil {
+			return false, fmt.Errorf("cannot read client CAs: %w", err)
+		}
+
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("no certificate found in %s", reloader.clientCAFile)
+		}
+	}
+
+	reloader.mutex.Lock()
+	defer reloader.mutex.Unlock()
+
+	reloader.certificate = &certificate
+	reloader.clientCAs = clientCAs
+	reloader.modTimes = modTimes
+	return true, nil
+}
+
+func sameModTimes(a map[string]time.Time, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for file, modTime := range a {
+		if !modTime.Equal(b[file]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Run reloads the files every interval until ctx is done.
+// Files are polled rather than watched, as Kubernetes updates mounted secrets by swapping symlinks.
+func (reloader *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := reloader.Reload()
+		if err != nil {
+			log.Error().Err(err).Msg("cannot reload TLS certificates, serving the previous ones")
+			continue
+		}
+		if reloaded {
+			log.Info().Str("cert_file", reloader.certFile).Msg("reloaded TLS certificates")
+		}
+	}
+}
+
+// ServerConfig creates the TLS configuration of a server that negotiates nextProtos with ALPN,
+// such as "h2" for gRPC. Every handshake uses the files as they were last loaded.
+// Client certificates are verified against the client CAs according to clientAuth.
+func (reloader *Reloader) ServerConfig(clientAuth tls.ClientAuthType, nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			reloader.mutex.RLock()
+			defer reloader.mutex.RUnlock()
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*reloader.certificate},
+				ClientAuth:   clientAuth,
+				ClientCAs:    reloader.clientCAs,
+			}, nil
+		},
+	}
+}
+
+// LoopbackClientConfig creates the TLS configuration of a client that calls this server over loopback,
+// such as the gateway. The server is authenticated by its exact certificate rather than by a CA,
+// as the loopback address is not one of its names. The client presents the same certificate when
+// presentCertificate is set, for servers that require client certificates; it must then be issued
+// by a client CA and allow client authentication.
+func (reloader *Reloader) LoopbackClientConfig(presentCertificate bool) *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// the chain is not verified against CAs, VerifyConnection compares the certificate instead
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			certificate := reloader.currentCertificate()
+			if len(state.PeerCertificates) == 0 || !bytes.Equal(state.PeerCertificates[0].Raw, certificate.Certificate[0]) {
+				return errors.New("server certificate is not the one loaded by this process")
+			}
+			return nil
+		},
+	}
+
+	if presentCertificate {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return reloader.currentCertificate(), nil
+		}
+	}
+	return config
+}
+
+func (reloader *Reloader) currentCertificate() *tls.Certificate {
+	reloader.mutex.RLock()
+	defer reloader.mutex.RUnlock()
+
+	return reloader.certificate
+}
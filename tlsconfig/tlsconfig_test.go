@@ -0,0 +1,277 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// newCertificate issues a certificate signed by issuer, or a self-signed CA when issuer is nil
+func newCertificate(t *testing.T, issuer *testCertificate, commonName string, serial int64) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+
+	parent, signer := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, signer = issuer.cert, issuer.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func (c *testCertificate) keyPEM(t *testing.T) []byte {
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func (c *testCertificate) tlsCertificate(t *testing.T) tls.Certificate {
+	certificate, err := tls.X509KeyPair(c.pem, c.keyPEM(t))
+	require.NoError(t, err)
+	return certificate
+}
+
+// writeFiles writes the files of a key pair, and moves their modification time forward
+// so that the change is seen even within the resolution of the file system clock
+func writeFiles(t *testing.T, dir string, c *testCertificate, ca *testCertificate, modTime time.Time) (string, string, string) {
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	require.NoError(t, os.WriteFile(certFile, c.pem, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, c.keyPEM(t), 0o600))
+	require.NoError(t, os.WriteFile(caFile, ca.pem, 0o600))
+	for _, file := range []string{certFile, keyFile, caFile} {
+		require.NoError(t, os.Chtimes(file, modTime, modTime))
+	}
+	return certFile, keyFile, caFile
+}
+
+// serverSerial returns the serial number of the certificate served by config
+func serverSerial(t *testing.T, config *tls.Config, roots *x509.CertPool) int64 {
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	go func() {
+		defer serverConn.Close()
+		tls.Server(serverConn, config).Handshake()
+	}()
+
+	client := tls.Client(clientConn, &tls.Config{RootCAs: roots, ServerName: "localhost"})
+	require.NoError(t, client.Handshake())
+	return client.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCertificate(t, nil, "test-ca", 1)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	modTime := time.Now().Add(-time.Hour)
+	certFile, keyFile, caFile := writeFiles(t, dir, newCertificate(t, ca, "server", 2), ca, modTime)
+
+	reloader, err := NewReloader(certFile, keyFile, caFile)
+	require.NoError(t, err)
+	config := reloader.ServerConfig(tls.NoClientCert)
+	require.Equal(t, int64(2), serverSerial(t, config, roots))
+
+	// nothing changed
+	reloaded, err := reloader.Reload()
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	// a renewed certificate is served by the next connections
+	modTime = modTime.Add(time.Minute)
+	writeFiles(t, dir, newCertificate(t, ca, "server", 3), ca, modTime)
+	reloaded, err = reloader.Reload()
+	require.NoError(t, err)
+	require.True(t, reloaded)
+	require.Equal(t, int64(3), serverSerial(t, config, roots))
+
+	// a key that does not match the certificate keeps the previous pair
+	other := newCertificate(t, ca, "server", 4)
+	require.NoError(t, os.WriteFile(keyFile, other.keyPEM(t), 0o600))
+	require.NoError(t, os.Chtimes(keyFile, modTime.Add(time.Minute), modTime.Add(time.Minute)))
+	reloaded, err = reloader.Reload()
+	require.Error(t, err)
+	require.False(t, reloaded)
+	require.Equal(t, int64(3), serverSerial(t, config, roots))
+}
+
+func TestNewReloaderMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewReloader(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), "")
+	require.Error(t, err)
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCertificate(t, nil, "test-ca", 1)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	certFile, keyFile, caFile := writeFiles(t, dir, newCertificate(t, ca, "server", 2), ca, time.Now())
+	reloader, err := NewReloader(certFile, keyFile, caFile)
+	require.NoError(t, err)
+
+	// the interceptor records the identity of the verified client certificate
+	clientNames := make(chan string, 1)
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		name := ""
+		if p, ok := peer.FromContext(ctx); ok {
+			if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+				name = tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+			}
+		}
+		clientNames <- name
+		return handler(ctx, req)
+	}
+
+	creds := credentials.NewTLS(reloader.ServerConfig(tls.VerifyClientCertIfGiven, "h2"))
+	grpcServer := grpc.NewServer(grpc.Creds(creds), grpc.UnaryInterceptor(interceptor))
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	check := func(certificate *tls.Certificate) error {
+		clientCreds := credentials.NewTLS(&tls.Config{
+			RootCAs:    roots,
+			ServerName: "localhost",
+			// sends the certificate even when the server does not list its CA
+			GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				if certificate == nil {
+					return &tls.Certificate{}, nil
+				}
+				return certificate, nil
+			},
+		})
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(clientCreds))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+
+	// internal callers authenticate with a certificate issued by the client CA
+	internal := newCertificate(t, ca, "internal-service", 5).tlsCertificate(t)
+	require.NoError(t, check(&internal))
+	require.Equal(t, "internal-service", <-clientNames)
+
+	// other callers may connect without one
+	require.NoError(t, check(nil))
+	require.Equal(t, "", <-clientNames)
+
+	// certificates of other CAs are rejected
+	otherCA := newCertificate(t, nil, "other-ca", 6)
+	intruder := newCertificate(t, otherCA, "intruder", 7).tlsCertificate(t)
+	require.Error(t, check(&intruder))
+	require.Empty(t, clientNames)
+}
+
+// handshake connects a client to a server over loopback, and returns the error of either side
+func handshake(t *testing.T, serverConfig *tls.Config, clientConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverErr <- tls.Server(conn, serverConfig).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// with TLS 1.3 the client is done before the server has verified its certificate
+	return <-serverErr
+}
+
+func TestLoopbackClientConfig(t *testing.T) {
+	ca := newCertificate(t, nil, "test-ca", 1)
+
+	certFile, keyFile, caFile := writeFiles(t, t.TempDir(), newCertificate(t, ca, "server", 2), ca, time.Now())
+	reloader, err := NewReloader(certFile, keyFile, caFile)
+	require.NoError(t, err)
+
+	otherFile, otherKeyFile, _ := writeFiles(t, t.TempDir(), newCertificate(t, ca, "server", 3), ca, time.Now())
+	other, err := NewReloader(otherFile, otherKeyFile, "")
+	require.NoError(t, err)
+
+	// the server is recognized by its certificate, without a CA or a server name
+	err = handshake(t, reloader.ServerConfig(tls.NoClientCert), reloader.LoopbackClientConfig(false))
+	require.NoError(t, err)
+
+	// another certificate, even from the same CA, is rejected
+	err = handshake(t, other.ServerConfig(tls.NoClientCert), reloader.LoopbackClientConfig(false))
+	require.Error(t, err)
+
+	// servers that require client certificates get the same certificate
+	err = handshake(t, reloader.ServerConfig(tls.RequireAndVerifyClientCert), reloader.LoopbackClientConfig(true))
+	require.NoError(t, err)
+	err = handshake(t, reloader.ServerConfig(tls.RequireAndVerifyClientCert), reloader.LoopbackClientConfig(false))
+	require.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/antimatter007/go-backend/tracing"
+
+// sqlcNamePrefix starts the first line of every query generated by sqlc
+const sqlcNamePrefix = "-- name: "
+
+// QueryTracer creates a span for every query run through pgx.
+// The arguments are left out of the spans, as they hold personal and financial data.
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+// NewQueryTracer creates a query tracer that uses the global tracer provider
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{
+		tracer: otel.Tracer(instrumentationName),
+	}
+}
+
+func (tracer *QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name, operation := queryName(data.SQL)
+	ctx, _ = tracer.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperation(operation),
+			semconv.DBStatement(data.SQL),
+		),
+	)
+	return ctx
+}
+
+func (tracer *QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+	span.End()
+}
+
+// queryName names the span of a query after its sqlc query, such as GetUser,
+// or after its SQL command for the statements that pgx runs itself, such as begin.
+func queryName(sql string) (name string, operation string) {
+	sql = strings.TrimSpace(sql)
+
+	if strings.HasPrefix(sql, sqlcNamePrefix) {
+		fields := strings.Fields(strings.TrimPrefix(sql, sqlcNamePrefix))
+		if len(fields) > 0 {
+			name = fields[0]
+		}
+
+		// the statement itself follows the name comment
+		if i := strings.IndexByte(sql, '\n'); i >= 0 {
+			sql = strings.TrimSpace(sql[i+1:])
+		}
+	}
+
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	if name == "" {
+		name = operation
+	}
+	return name, operation
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestQueryName(t *testing.T) {
+	testCases := []struct {
+		name              string
+		sql               string
+		expectedName      string
+		expectedOperation string
+	}{
+		{
+			name:              "Sqlc",
+			sql:               "-- name: GetUser :one\nSELECT username, role FROM users\nWHERE username = $1 LIMIT 1\n",
+			expectedName:      "GetUser",
+			expectedOperation: "SELECT",
+		},
+		{
+			name:              "SqlcWithCTE",
+			sql:               "-- name: AccrueInterest :one\nwith accounts as (select 1)\nselect count(*) from accounts",
+			expectedName:      "AccrueInterest",
+			expectedOperation: "WITH",
+		},
+		{
+			name:              "Statement",
+			sql:               "begin",
+			expectedName:      "BEGIN",
+			expectedOperation: "BEGIN",
+		},
+		{
+			name:              "Empty",
+			sql:               "  ",
+			expectedName:      "",
+			expectedOperation: "",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			name, operation := queryName(tc.sql)
+			require.Equal(t, tc.expectedName, name)
+			require.Equal(t, tc.expectedOperation, operation)
+		})
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	tracer := &QueryTracer{tracer: provider.Tracer(instrumentationName)}
+	parentCtx, parent := provider.Tracer("test").Start(context.Background(), "CreateUser")
+
+	sql := "-- name: CreateUser :one\nINSERT INTO users (username) VALUES ($1) RETURNING *"
+	ctx := tracer.TraceQueryStart(parentCtx, nil, pgx.TraceQueryStartData{SQL: sql, Args: []any{"alice"}})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	ctx = tracer.TraceQueryStart(parentCtx, nil, pgx.TraceQueryStartData{SQL: "commit"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("connection reset")})
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	query := spans[0]
+	require.Equal(t, "CreateUser", query.Name)
+	require.Equal(t, trace.SpanKindClient, query.SpanKind)
+	require.Equal(t, parent.SpanContext().SpanID(), query.Parent.SpanID())
+	require.Contains(t, query.Attributes, semconv.DBStatement(sql))
+	require.Contains(t, query.Attributes, semconv.DBOperation("INSERT"))
+	require.Equal(t, codes.Unset, query.Status.Code)
+	for _, attr := range query.Attributes {
+		require.NotContains(t, attr.Value.Emit(), "alice")
+	}
+
+	commit := spans[1]
+	require.Equal(t, "COMMIT", commit.Name)
+	require.Equal(t, codes.Error, commit.Status.Code)
+	require.Equal(t, "connection reset", commit.Status.Description)
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antimatter007/go-backend/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// NewTracerProvider creates the tracer provider described by config and installs it globally,
+// along with the W3C trace context and baggage propagators.
+// Spans are exported to the OTLP collector at config.OTLPEndpoint. Without an endpoint they are
+// still created, so that trace ids reach the logs and the task payloads, but never exported.
+// The provider must be shut down to flush the spans that are still buffered.
+func NewTracerProvider(ctx context.Context, config util.Config) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	if config.OTLPEndpoint != "" {
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(config.OTLPEndpoint),
+		}
+		if config.OTLPInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		var err error
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create OTLP exporter: %w", err)
+		}
+	}
+
+	provider, err := newTracerProvider(config, exporter)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return provider, nil
+}
+
+// newTracerProvider creates a tracer provider that sends the spans to exporter, if any
+func newTracerProvider(config util.Config, exporter sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(config.TracingServiceName),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create tracing resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.TracingSampleRatio))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
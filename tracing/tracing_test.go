@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antimatter007/go-backend/util"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func TestNewTracerProviderWithoutEndpoint(t *testing.T) {
+	config := util.Config{
+		TracingServiceName: "simple-bank-test",
+		TracingSampleRatio: 1,
+	}
+
+	provider, err := NewTracerProvider(context.Background(), config)
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	// spans are created so that their context propagates, even though none is exported
+	ctx, span := otel.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+	require.True(t, span.SpanContext().IsValid())
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	require.Contains(t, carrier, "traceparent")
+}
+
+func TestTracerProviderExportsSpans(t *testing.T) {
+	testCases := []struct {
+		name          string
+		sampleRatio   float64
+		expectedSpans int
+	}{
+		{
+			name:          "Sampled",
+			sampleRatio:   1,
+			expectedSpans: 1,
+		},
+		{
+			name:          "NotSampled",
+			sampleRatio:   0,
+			expectedSpans: 0,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			config := util.Config{
+				TracingServiceName: "simple-bank-test",
+				TracingSampleRatio: tc.sampleRatio,
+			}
+
+			exporter := tracetest.NewInMemoryExporter()
+			provider, err := newTracerProvider(config, exporter)
+			require.NoError(t, err)
+			defer provider.Shutdown(context.Background())
+
+			_, span := provider.Tracer("test").Start(context.Background(), "request")
+			span.End()
+
+			err = provider.ForceFlush(context.Background())
+			require.NoError(t, err)
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, tc.expectedSpans)
+			for _, s := range spans {
+				require.Equal(t, "request", s.Name)
+				require.Contains(t, s.Resource.Attributes(), semconv.ServiceName(config.TracingServiceName))
+			}
+		})
+	}
+}
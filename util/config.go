@@ -3,13 +3,27 @@ package util
 import (
 	"fmt"
 	"log"
+	"net"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/antimatter007/go-backend/ratelimit"
 	"github.com/joho/godotenv"
 )
 
+// Ways for the gateway to reach the gRPC handlers
+const (
+	// GatewayInProcess calls the handlers directly, skipping the gRPC interceptors
+	GatewayInProcess = "in_process"
+	// GatewayDial calls the gRPC server over loopback, through its interceptors
+	GatewayDial = "dial"
+	// GatewayMultiplex serves gRPC and the gateway on the HTTP port, the gateway calls the gRPC server through it
+	GatewayMultiplex = "multiplex"
+)
+
 // Config stores all configuration of the application.
 type Config struct {
 	Environment          string        // Application environment (development, production, etc.)
@@ -26,6 +40,69 @@ type Config struct {
 	EmailSenderName      string        // Name of the email sender
 	EmailSenderAddress   string        // Email address of the sender
 	EmailSenderPassword  string        // Password for the sender's email account
+
+	GRPCShutdownTimeout   time.Duration // Time allowed for in-flight gRPC calls to finish on shutdown
+	HTTPShutdownTimeout   time.Duration // Time allowed for in-flight gateway requests to finish on shutdown
+	WorkerShutdownTimeout time.Duration // Time allowed for running tasks to finish on shutdown before they are requeued
+	DBCloseTimeout        time.Duration // Time allowed for database connections in use to be released on shutdown
+
+	HealthCheckInterval time.Duration // Interval between the readiness checks published to the gRPC health service
+	HealthCheckTimeout  time.Duration // Time allowed for all readiness checks to complete
+
+	CleanupSessionsCronSpec     string // Cron spec for purging expired or blocked sessions
+	CleanupVerifyEmailsCronSpec string // Cron spec for purging expired verification codes
+	CleanupBatchSize            int32  // Maximum number of rows removed per delete statement
+
+	WebhookTimeout              time.Duration // Time allowed for a webhook endpoint to respond
+	WebhookAllowPrivateNetworks bool          // Deliver webhooks to loopback and private addresses, for local development only
+
+	ScheduledTransfersCronSpec  string // Cron spec for executing due scheduled transfers
+	ScheduledTransfersBatchSize int32  // Maximum number of due scheduled transfers loaded at once
+
+	FXRatesURL      string        // URL of the exchange rate service, takes precedence over FXRatesFile
+	FXRatesFile     string        // Path of a JSON file with static exchange rates
+	FXRatesCacheTTL time.Duration // How long rates fetched from FXRatesURL are reused
+	FXQuoteTTL      time.Duration // How long a quoted exchange rate stays locked
+	FXSpreadBps     int32         // Spread charged on conversions, in basis points
+
+	SupportedCurrencies []string // ISO 4217 codes of the currencies accounts may use
+
+	HoldTTL              time.Duration // Default and maximum lifetime of a hold
+	HoldsExpiryCronSpec  string        // Cron spec for releasing expired holds
+	HoldsExpiryBatchSize int32         // Maximum number of holds expired per statement
+
+	ReconciliationCronSpec  string // Cron spec for comparing account balances with the ledger
+	ReconciliationBatchSize int32  // Number of accounts reconciled per statement
+
+	InterestCronSpec  string // Cron spec for accruing the interest of the previous day and posting past months
+	InterestBatchSize int32  // Number of accounts accrued per statement and posted per listing
+
+	OTLPEndpoint       string  // host:port of the OTLP gRPC collector receiving traces, tracing is not exported when empty
+	OTLPInsecure       bool    // Connect to the OTLP collector without TLS
+	TracingServiceName string  // Service name recorded on every span
+	TracingSampleRatio float64 // Fraction of new traces that are sampled, traces started upstream follow the caller's decision
+
+	RateLimitEnabled bool                      // Reject the requests of clients exceeding their limits
+	RateLimitDefault ratelimit.Rule            // Limit of the methods without a rule of their own
+	RateLimitRules   map[string]ratelimit.Rule // Limits by method name, such as LoginUser
+	APIKeys          []string                  // API keys issued to partners, @api_key rules count requests by the issued keys only
+	TrustedProxies   []string                  // Addresses or CIDRs of the proxies whose X-Forwarded-For the HTTP server trusts, none when empty
+
+	TLSCertFile           string            // PEM certificate served by the gRPC and gateway listeners, they serve plaintext when empty
+	TLSKeyFile            string            // PEM private key of TLSCertFile
+	TLSClientCAFile       string            // PEM CAs that issue the client certificates of internal callers on the gRPC port
+	TLSClientCertRequired bool              // Reject gRPC callers without a client certificate instead of only verifying the given ones
+	TLSClientRoles        map[string]string // Roles of internal callers by client certificate identity, such as spiffe://simple-bank/ops=admin
+	TLSReloadInterval     time.Duration     // Interval between checks for renewed certificate files
+
+	GatewayMode string // How the gateway reaches the gRPC handlers: in_process, dial or multiplex
+
+	CORSAllowedOrigins   []string      // Origins whose browser requests may call the gateway, * for any, none when empty
+	CORSAllowedMethods   []string      // Methods that cross-origin requests may use
+	CORSAllowedHeaders   []string      // Request headers that cross-origin requests may send
+	CORSAllowCredentials bool          // Let cross-origin requests send cookies and authorization headers
+	CORSMaxAge           time.Duration // How long browsers may cache a preflight response
+	HSTSMaxAge           time.Duration // Strict-Transport-Security max-age of HTTPS responses, HSTS is disabled when 0
 }
 
 // LoadConfig loads configuration from environment variables.
@@ -73,6 +150,221 @@ func LoadConfig(path string) (Config, error) {
 		return config, fmt.Errorf("invalid REFRESH_TOKEN_DURATION: %w", err)
 	}
 
+	// Graceful shutdown, the servers drain in parallel and the database is closed after them
+	config.GRPCShutdownTimeout, err = time.ParseDuration(getEnv("GRPC_SHUTDOWN_TIMEOUT", "15s"))
+	if err != nil || config.GRPCShutdownTimeout <= 0 {
+		return config, fmt.Errorf("invalid GRPC_SHUTDOWN_TIMEOUT: must be a positive duration")
+	}
+	config.HTTPShutdownTimeout, err = time.ParseDuration(getEnv("HTTP_SHUTDOWN_TIMEOUT", "15s"))
+	if err != nil || config.HTTPShutdownTimeout <= 0 {
+		return config, fmt.Errorf("invalid HTTP_SHUTDOWN_TIMEOUT: must be a positive duration")
+	}
+	config.WorkerShutdownTimeout, err = time.ParseDuration(getEnv("WORKER_SHUTDOWN_TIMEOUT", "15s"))
+	if err != nil || config.WorkerShutdownTimeout <= 0 {
+		return config, fmt.Errorf("invalid WORKER_SHUTDOWN_TIMEOUT: must be a positive duration")
+	}
+	config.DBCloseTimeout, err = time.ParseDuration(getEnv("DB_CLOSE_TIMEOUT", "5s"))
+	if err != nil || config.DBCloseTimeout <= 0 {
+		return config, fmt.Errorf("invalid DB_CLOSE_TIMEOUT: must be a positive duration")
+	}
+
+	// Health checks of Postgres, Redis and the migration state
+	config.HealthCheckInterval, err = time.ParseDuration(getEnv("HEALTH_CHECK_INTERVAL", "10s"))
+	if err != nil || config.HealthCheckInterval <= 0 {
+		return config, fmt.Errorf("invalid HEALTH_CHECK_INTERVAL: must be a positive duration")
+	}
+	config.HealthCheckTimeout, err = time.ParseDuration(getEnv("HEALTH_CHECK_TIMEOUT", "3s"))
+	if err != nil || config.HealthCheckTimeout <= 0 {
+		return config, fmt.Errorf("invalid HEALTH_CHECK_TIMEOUT: must be a positive duration")
+	}
+
+	// Periodic cleanup jobs
+	config.CleanupSessionsCronSpec = getEnv("CLEANUP_SESSIONS_CRON", "0 * * * *")
+	config.CleanupVerifyEmailsCronSpec = getEnv("CLEANUP_VERIFY_EMAILS_CRON", "30 * * * *")
+	cleanupBatchSize, err := strconv.ParseInt(getEnv("CLEANUP_BATCH_SIZE", "1000"), 10, 32)
+	if err != nil || cleanupBatchSize <= 0 {
+		return config, fmt.Errorf("invalid CLEANUP_BATCH_SIZE: must be a positive integer")
+	}
+	config.CleanupBatchSize = int32(cleanupBatchSize)
+
+	// Outgoing webhooks
+	config.WebhookTimeout, err = time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"))
+	if err != nil {
+		return config, fmt.Errorf("invalid WEBHOOK_TIMEOUT: %w", err)
+	}
+	config.WebhookAllowPrivateNetworks, err = strconv.ParseBool(getEnv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", "false"))
+	if err != nil {
+		return config, fmt.Errorf("invalid WEBHOOK_ALLOW_PRIVATE_NETWORKS: %w", err)
+	}
+
+	// Scheduled transfers
+	config.ScheduledTransfersCronSpec = getEnv("SCHEDULED_TRANSFERS_CRON", "* * * * *")
+	scheduledTransfersBatchSize, err := strconv.ParseInt(getEnv("SCHEDULED_TRANSFERS_BATCH_SIZE", "100"), 10, 32)
+	if err != nil || scheduledTransfersBatchSize <= 0 {
+		return config, fmt.Errorf("invalid SCHEDULED_TRANSFERS_BATCH_SIZE: must be a positive integer")
+	}
+	config.ScheduledTransfersBatchSize = int32(scheduledTransfersBatchSize)
+
+	// Currency conversion
+	config.FXRatesURL = os.Getenv("FX_RATES_URL")
+	config.FXRatesFile = getEnv("FX_RATES_FILE", "fx_rates.json")
+	config.FXRatesCacheTTL, err = time.ParseDuration(getEnv("FX_RATES_CACHE_TTL", "1m"))
+	if err != nil {
+		return config, fmt.Errorf("invalid FX_RATES_CACHE_TTL: %w", err)
+	}
+	config.FXQuoteTTL, err = time.ParseDuration(getEnv("FX_QUOTE_TTL", "30s"))
+	if err != nil {
+		return config, fmt.Errorf("invalid FX_QUOTE_TTL: %w", err)
+	}
+	fxSpreadBps, err := strconv.ParseInt(getEnv("FX_SPREAD_BPS", "50"), 10, 32)
+	if err != nil || fxSpreadBps < 0 || fxSpreadBps >= 10000 {
+		return config, fmt.Errorf("invalid FX_SPREAD_BPS: must be between 0 and 9999")
+	}
+	config.FXSpreadBps = int32(fxSpreadBps)
+
+	// Currencies
+	for _, code := range strings.Split(getEnv("SUPPORTED_CURRENCIES", strings.Join(DefaultSupportedCurrencies, ",")), ",") {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		if _, ok := LookupCurrency(code); !ok {
+			return config, fmt.Errorf("invalid SUPPORTED_CURRENCIES: unknown currency %q", code)
+		}
+		config.SupportedCurrencies = append(config.SupportedCurrencies, code)
+	}
+	if len(config.SupportedCurrencies) == 0 {
+		return config, fmt.Errorf("invalid SUPPORTED_CURRENCIES: at least one currency must be supported")
+	}
+
+	// Holds
+	config.HoldTTL, err = time.ParseDuration(getEnv("HOLD_TTL", "168h"))
+	if err != nil || config.HoldTTL <= 0 {
+		return config, fmt.Errorf("invalid HOLD_TTL: must be a positive duration")
+	}
+	config.HoldsExpiryCronSpec = getEnv("HOLDS_EXPIRY_CRON", "* * * * *")
+	holdsExpiryBatchSize, err := strconv.ParseInt(getEnv("HOLDS_EXPIRY_BATCH_SIZE", "1000"), 10, 32)
+	if err != nil || holdsExpiryBatchSize <= 0 {
+		return config, fmt.Errorf("invalid HOLDS_EXPIRY_BATCH_SIZE: must be a positive integer")
+	}
+	config.HoldsExpiryBatchSize = int32(holdsExpiryBatchSize)
+
+	// Reconciliation
+	config.ReconciliationCronSpec = getEnv("RECONCILIATION_CRON", "0 2 * * *")
+	reconciliationBatchSize, err := strconv.ParseInt(getEnv("RECONCILIATION_BATCH_SIZE", "1000"), 10, 32)
+	if err != nil || reconciliationBatchSize <= 0 {
+		return config, fmt.Errorf("invalid RECONCILIATION_BATCH_SIZE: must be a positive integer")
+	}
+	config.ReconciliationBatchSize = int32(reconciliationBatchSize)
+
+	// Interest
+	config.InterestCronSpec = getEnv("INTEREST_CRON", "0 1 * * *")
+	interestBatchSize, err := strconv.ParseInt(getEnv("INTEREST_BATCH_SIZE", "1000"), 10, 32)
+	if err != nil || interestBatchSize <= 0 {
+		return config, fmt.Errorf("invalid INTEREST_BATCH_SIZE: must be a positive integer")
+	}
+	config.InterestBatchSize = int32(interestBatchSize)
+
+	// Tracing, exported over OTLP when an endpoint is set
+	config.OTLPEndpoint = getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	config.OTLPInsecure, err = strconv.ParseBool(getEnv("OTEL_EXPORTER_OTLP_INSECURE", "false"))
+	if err != nil {
+		return config, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_INSECURE: %w", err)
+	}
+	config.TracingServiceName = getEnv("OTEL_SERVICE_NAME", "simple-bank")
+	config.TracingSampleRatio, err = strconv.ParseFloat(getEnv("OTEL_TRACES_SAMPLER_RATIO", "1"), 64)
+	if err != nil || config.TracingSampleRatio < 0 || config.TracingSampleRatio > 1 {
+		return config, fmt.Errorf("invalid OTEL_TRACES_SAMPLER_RATIO: must be between 0 and 1")
+	}
+
+	// Rate limits, shared by all instances through Redis
+	config.RateLimitEnabled, err = strconv.ParseBool(getEnv("RATE_LIMIT_ENABLED", "true"))
+	if err != nil {
+		return config, fmt.Errorf("invalid RATE_LIMIT_ENABLED: %w", err)
+	}
+	config.RateLimitDefault, err = ratelimit.ParseRule(getEnv("RATE_LIMIT_DEFAULT", "20/1s@user"))
+	if err != nil {
+		return config, fmt.Errorf("invalid RATE_LIMIT_DEFAULT: %w", err)
+	}
+	config.RateLimitRules, err = ratelimit.ParseRules(getEnv("RATE_LIMITS",
+		"CreateUser=5/1m@ip,LoginUser=10/1m@ip,VerifyEmail=10/1m@ip,RenewAccessToken=30/1m@ip"))
+	if err != nil {
+		return config, fmt.Errorf("invalid RATE_LIMITS: %w", err)
+	}
+	config.APIKeys = splitList(getEnv("API_KEYS", ""))
+	config.TrustedProxies = splitList(getEnv("TRUSTED_PROXIES", ""))
+	for _, proxy := range config.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return config, fmt.Errorf("invalid TRUSTED_PROXIES: %q is not an address or a CIDR", proxy)
+		}
+	}
+
+	// TLS of the listeners, and mutual TLS of the internal callers of the gRPC port
+	config.TLSCertFile = getEnv("TLS_CERT_FILE", "")
+	config.TLSKeyFile = getEnv("TLS_KEY_FILE", "")
+	config.TLSClientCAFile = getEnv("TLS_CLIENT_CA_FILE", "")
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		return config, fmt.Errorf("invalid TLS_CERT_FILE and TLS_KEY_FILE: both must be set to enable TLS")
+	}
+	if config.TLSClientCAFile != "" && config.TLSCertFile == "" {
+		return config, fmt.Errorf("invalid TLS_CLIENT_CA_FILE: client certificates require TLS_CERT_FILE")
+	}
+	config.TLSClientCertRequired, err = strconv.ParseBool(getEnv("TLS_CLIENT_CERT_REQUIRED", "false"))
+	if err != nil {
+		return config, fmt.Errorf("invalid TLS_CLIENT_CERT_REQUIRED: %w", err)
+	}
+	if config.TLSClientCertRequired && config.TLSClientCAFile == "" {
+		return config, fmt.Errorf("invalid TLS_CLIENT_CERT_REQUIRED: client certificates require TLS_CLIENT_CA_FILE")
+	}
+	config.TLSClientRoles, err = parseClientRoles(getEnv("TLS_CLIENT_ROLES", ""))
+	if err != nil {
+		return config, fmt.Errorf("invalid TLS_CLIENT_ROLES: %w", err)
+	}
+	if len(config.TLSClientRoles) > 0 && config.TLSClientCAFile == "" {
+		return config, fmt.Errorf("invalid TLS_CLIENT_ROLES: client certificates require TLS_CLIENT_CA_FILE")
+	}
+	config.TLSReloadInterval, err = time.ParseDuration(getEnv("TLS_RELOAD_INTERVAL", "30s"))
+	if err != nil || config.TLSReloadInterval <= 0 {
+		return config, fmt.Errorf("invalid TLS_RELOAD_INTERVAL: must be a positive duration")
+	}
+
+	// Gateway
+	config.GatewayMode = getEnv("GATEWAY_MODE", GatewayDial)
+	switch config.GatewayMode {
+	case GatewayInProcess, GatewayDial, GatewayMultiplex:
+	default:
+		return config, fmt.Errorf("invalid GATEWAY_MODE: must be in_process, dial or multiplex")
+	}
+
+	// Browser access to the gateway
+	config.CORSAllowedOrigins = splitList(getEnv("CORS_ALLOWED_ORIGINS", ""))
+	config.CORSAllowedMethods = splitList(getEnv("CORS_ALLOWED_METHODS", "GET,POST,PATCH,DELETE"))
+	config.CORSAllowedHeaders = splitList(getEnv("CORS_ALLOWED_HEADERS", "Authorization,Content-Type,X-Request-ID"))
+	config.CORSAllowCredentials, err = strconv.ParseBool(getEnv("CORS_ALLOW_CREDENTIALS", "false"))
+	if err != nil {
+		return config, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS: %w", err)
+	}
+	for _, origin := range config.CORSAllowedOrigins {
+		if origin == "*" {
+			if config.CORSAllowCredentials {
+				return config, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS: * cannot be used with CORS_ALLOW_CREDENTIALS")
+			}
+			continue
+		}
+		originURL, err := url.Parse(origin)
+		if err != nil || originURL.Scheme == "" || originURL.Host == "" {
+			return config, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS: %q is not an origin such as https://app.example.com", origin)
+		}
+	}
+	config.CORSMaxAge, err = time.ParseDuration(getEnv("CORS_MAX_AGE", "10m"))
+	if err != nil || config.CORSMaxAge < 0 {
+		return config, fmt.Errorf("invalid CORS_MAX_AGE: must be a duration")
+	}
+	config.HSTSMaxAge, err = time.ParseDuration(getEnv("HSTS_MAX_AGE", "8760h"))
+	if err != nil || config.HSTSMaxAge < 0 {
+		return config, fmt.Errorf("invalid HSTS_MAX_AGE: must be a duration")
+	}
+
 	// Parse Redis URL
 	if config.RedisURL == "" {
 		return config, fmt.Errorf("REDIS_URL is not set")
@@ -137,6 +429,54 @@ func LoadConfig(path string) (Config, error) {
 		fmt.Printf("RefreshTokenDuration: %s\n", config.RefreshTokenDuration)
 		fmt.Printf("EmailSenderName: %s\n", config.EmailSenderName)
 		fmt.Printf("EmailSenderAddress: %s\n", config.EmailSenderAddress)
+		fmt.Printf("CleanupSessionsCronSpec: %s\n", config.CleanupSessionsCronSpec)
+		fmt.Printf("CleanupVerifyEmailsCronSpec: %s\n", config.CleanupVerifyEmailsCronSpec)
+		fmt.Printf("CleanupBatchSize: %d\n", config.CleanupBatchSize)
+		fmt.Printf("GRPCShutdownTimeout: %s\n", config.GRPCShutdownTimeout)
+		fmt.Printf("HTTPShutdownTimeout: %s\n", config.HTTPShutdownTimeout)
+		fmt.Printf("WorkerShutdownTimeout: %s\n", config.WorkerShutdownTimeout)
+		fmt.Printf("DBCloseTimeout: %s\n", config.DBCloseTimeout)
+		fmt.Printf("HealthCheckInterval: %s\n", config.HealthCheckInterval)
+		fmt.Printf("HealthCheckTimeout: %s\n", config.HealthCheckTimeout)
+		fmt.Printf("WebhookTimeout: %s\n", config.WebhookTimeout)
+		fmt.Printf("WebhookAllowPrivateNetworks: %t\n", config.WebhookAllowPrivateNetworks)
+		fmt.Printf("ScheduledTransfersCronSpec: %s\n", config.ScheduledTransfersCronSpec)
+		fmt.Printf("ScheduledTransfersBatchSize: %d\n", config.ScheduledTransfersBatchSize)
+		fmt.Printf("FXRatesURL: %s\n", config.FXRatesURL)
+		fmt.Printf("FXRatesFile: %s\n", config.FXRatesFile)
+		fmt.Printf("FXRatesCacheTTL: %s\n", config.FXRatesCacheTTL)
+		fmt.Printf("FXQuoteTTL: %s\n", config.FXQuoteTTL)
+		fmt.Printf("FXSpreadBps: %d\n", config.FXSpreadBps)
+		fmt.Printf("SupportedCurrencies: %s\n", strings.Join(config.SupportedCurrencies, ","))
+		fmt.Printf("HoldTTL: %s\n", config.HoldTTL)
+		fmt.Printf("HoldsExpiryCronSpec: %s\n", config.HoldsExpiryCronSpec)
+		fmt.Printf("HoldsExpiryBatchSize: %d\n", config.HoldsExpiryBatchSize)
+		fmt.Printf("ReconciliationCronSpec: %s\n", config.ReconciliationCronSpec)
+		fmt.Printf("ReconciliationBatchSize: %d\n", config.ReconciliationBatchSize)
+		fmt.Printf("InterestCronSpec: %s\n", config.InterestCronSpec)
+		fmt.Printf("InterestBatchSize: %d\n", config.InterestBatchSize)
+		fmt.Printf("OTLPEndpoint: %s\n", config.OTLPEndpoint)
+		fmt.Printf("OTLPInsecure: %t\n", config.OTLPInsecure)
+		fmt.Printf("TracingServiceName: %s\n", config.TracingServiceName)
+		fmt.Printf("TracingSampleRatio: %g\n", config.TracingSampleRatio)
+		fmt.Printf("RateLimitEnabled: %t\n", config.RateLimitEnabled)
+		fmt.Printf("RateLimitDefault: %+v\n", config.RateLimitDefault)
+		fmt.Printf("RateLimitRules: %+v\n", config.RateLimitRules)
+		fmt.Printf("APIKeys: %d issued\n", len(config.APIKeys))
+		fmt.Printf("TrustedProxies: %v\n", config.TrustedProxies)
+		fmt.Printf("TLSCertFile: %s\n", config.TLSCertFile)
+		fmt.Printf("TLSKeyFile: %s\n", config.TLSKeyFile)
+		fmt.Printf("TLSClientCAFile: %s\n", config.TLSClientCAFile)
+		fmt.Printf("TLSClientCertRequired: %t\n", config.TLSClientCertRequired)
+		fmt.Printf("TLSClientRoles: %v\n", config.TLSClientRoles)
+		fmt.Printf("TLSReloadInterval: %s\n", config.TLSReloadInterval)
+		fmt.Printf("GatewayMode: %s\n", config.GatewayMode)
+		fmt.Printf("CORSAllowedOrigins: %v\n", config.CORSAllowedOrigins)
+		fmt.Printf("CORSAllowedMethods: %v\n", config.CORSAllowedMethods)
+		fmt.Printf("CORSAllowedHeaders: %v\n", config.CORSAllowedHeaders)
+		fmt.Printf("CORSAllowCredentials: %t\n", config.CORSAllowCredentials)
+		fmt.Printf("CORSMaxAge: %s\n", config.CORSMaxAge)
+		fmt.Printf("HSTSMaxAge: %s\n", config.HSTSMaxAge)
 		// Do not print EmailSenderPassword or RedisPassword
 	}
 
@@ -160,3 +500,43 @@ func parseRedisURL(redisURL string, config *Config) error {
 
 	return nil
 }
+
+// getEnv returns the value of an environment variable, or fallback if it is unset or empty.
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// parseClientRoles parses a comma separated list of identity=role pairs.
+// Identities may contain "=", the role is taken after the last one.
+func parseClientRoles(value string) (map[string]string, error) {
+	roles := map[string]string{}
+	for _, item := range splitList(value) {
+		separator := strings.LastIndex(item, "=")
+		if separator <= 0 {
+			return nil, fmt.Errorf("%q is not an identity=role pair", item)
+		}
+
+		identity, role := strings.TrimSpace(item[:separator]), strings.TrimSpace(item[separator+1:])
+		switch role {
+		case DepositorRole, BankerRole, AdminRole:
+		default:
+			return nil, fmt.Errorf("unknown role %q for %s", role, identity)
+		}
+		roles[identity] = role
+	}
+	return roles, nil
+}
+
+// splitList splits a comma separated list, dropping blank items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,17 +1,110 @@
 package util
 
-// Constants for all supported currencies
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Constants for commonly used currencies
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
+	GBP = "GBP"
+	JPY = "JPY"
+	BHD = "BHD"
+)
+
+// DefaultSupportedCurrencies are the currencies accepted when none are configured
+var DefaultSupportedCurrencies = []string{USD, EUR, CAD}
+
+// Currency is an ISO 4217 currency. Exponent is the number of decimal places of its minor unit:
+// an amount of 100 is 1.00 USD (exponent 2), 100 JPY (exponent 0) or 0.100 BHD (exponent 3).
+type Currency struct {
+	Code     string
+	Exponent int
+}
+
+// currencies is the registry of known ISO 4217 currencies
+var currencies = map[string]Currency{}
+
+func init() {
+	for _, currency := range []Currency{
+		{"AED", 2}, {"ARS", 2}, {"AUD", 2}, {BHD, 3}, {"BRL", 2},
+		{CAD, 2}, {"CHF", 2}, {"CLP", 0}, {"CNY", 2}, {"CZK", 2},
+		{"DKK", 2}, {EUR, 2}, {GBP, 2}, {"HKD", 2}, {"HUF", 2},
+		{"IDR", 2}, {"ILS", 2}, {"INR", 2}, {"ISK", 0}, {"JOD", 3},
+		{JPY, 0}, {"KRW", 0}, {"KWD", 3}, {"MXN", 2}, {"MYR", 2},
+		{"NOK", 2}, {"NZD", 2}, {"OMR", 3}, {"PHP", 2}, {"PLN", 2},
+		{"RON", 2}, {"SAR", 2}, {"SEK", 2}, {"SGD", 2}, {"THB", 2},
+		{"TND", 3}, {"TRY", 2}, {"TWD", 2}, {USD, 2}, {"VND", 0},
+		{"ZAR", 2},
+	} {
+		currencies[currency.Code] = currency
+	}
+
+	if err := SetSupportedCurrencies(DefaultSupportedCurrencies); err != nil {
+		panic(err)
+	}
+}
+
+var (
+	supportedMu         sync.RWMutex
+	supportedCurrencies map[string]Currency
 )
 
+// LookupCurrency returns the registered currency with the given code, supported or not
+func LookupCurrency(code string) (Currency, bool) {
+	currency, ok := currencies[code]
+	return currency, ok
+}
+
+// SetSupportedCurrencies replaces the set of currencies that accounts and transfers may use.
+// Every code must be a registered ISO 4217 currency.
+func SetSupportedCurrencies(codes []string) error {
+	if len(codes) == 0 {
+		return fmt.Errorf("at least one currency must be supported")
+	}
+
+	supported := make(map[string]Currency, len(codes))
+	for _, code := range codes {
+		currency, ok := LookupCurrency(code)
+		if !ok {
+			return fmt.Errorf("unknown currency %q", code)
+		}
+		supported[code] = currency
+	}
+
+	supportedMu.Lock()
+	defer supportedMu.Unlock()
+	supportedCurrencies = supported
+	return nil
+}
+
+// SupportedCurrency returns the currency with the given code if it is supported
+func SupportedCurrency(code string) (Currency, bool) {
+	supportedMu.RLock()
+	defer supportedMu.RUnlock()
+	currency, ok := supportedCurrencies[code]
+	return currency, ok
+}
+
+// SupportedCurrencies returns the codes of all supported currencies in alphabetical order
+func SupportedCurrencies() []string {
+	supportedMu.RLock()
+	defer supportedMu.RUnlock()
+
+	codes := make([]string, 0, len(supportedCurrencies))
+	for code := range supportedCurrencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
 // IsSupportedCurrency returns true if the currency is supported
 func IsSupportedCurrency(currency string) bool {
-	switch currency {
-	case USD, EUR, CAD:
-		return true
-	}
-	return false
+	_, ok := SupportedCurrency(currency)
+	return ok
 }
@@ -0,0 +1,109 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrInvalidAmount       = errors.New("invalid amount")
+)
+
+// Money is an amount in the minor unit of a supported currency, such as cents for USD
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// NewMoney creates money from an amount in minor units of a supported currency
+func NewMoney(amount int64, currencyCode string) (Money, error) {
+	currency, ok := SupportedCurrency(currencyCode)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currencyCode)
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// ParseMoney parses a decimal string such as "12.34" into money of a supported currency.
+// It rejects more decimal places than the currency's minor unit has.
+func ParseMoney(value string, currencyCode string) (Money, error) {
+	money, err := NewMoney(0, currencyCode)
+	if err != nil {
+		return Money{}, err
+	}
+
+	sign := int64(1)
+	digits := strings.TrimSpace(value)
+	if strings.HasPrefix(digits, "-") {
+		sign = -1
+		digits = digits[1:]
+	}
+
+	whole, fraction, hasPoint := strings.Cut(digits, ".")
+	if whole == "" || (hasPoint && fraction == "") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if len(fraction) > money.Currency.Exponent {
+		return Money{}, fmt.Errorf("%w: %s has %d decimal places", ErrInvalidAmount, currencyCode, money.Currency.Exponent)
+	}
+	fraction += strings.Repeat("0", money.Currency.Exponent-len(fraction))
+
+	for _, c := range whole + fraction {
+		if c < '0' || c > '9' {
+			return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+		}
+	}
+
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	money.Amount = sign * amount
+	return money, nil
+}
+
+// Validate checks that the amount is positive, as required for transfers
+func (money Money) Validate() error {
+	if money.Amount <= 0 {
+		return fmt.Errorf("%w: must be positive", ErrInvalidAmount)
+	}
+	return nil
+}
+
+// Decimal formats the amount in major units, such as "12.34" for 1234 cents
+func (money Money) Decimal() string {
+	if money.Currency.Exponent == 0 {
+		return strconv.FormatInt(money.Amount, 10)
+	}
+
+	sign := ""
+	amount := uint64(money.Amount)
+	if money.Amount < 0 {
+		sign = "-"
+		amount = uint64(-(money.Amount + 1)) + 1
+	}
+
+	scale := uint64(math.Pow10(money.Currency.Exponent))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, money.Currency.Exponent, amount%scale)
+}
+
+// String formats the money with its currency code, such as "12.34 USD"
+func (money Money) String() string {
+	return money.Decimal() + " " + money.Currency.Code
+}
+
+// FormatAmount formats an amount in minor units of a currency as a decimal string.
+// Amounts of unknown currencies are formatted as plain integers.
+func FormatAmount(amount int64, currencyCode string) string {
+	currency, ok := LookupCurrency(currencyCode)
+	if !ok {
+		return strconv.FormatInt(amount, 10)
+	}
+	return Money{Amount: amount, Currency: currency}.Decimal()
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	err := SetSupportedCurrencies([]string{USD, JPY, BHD})
+	require.NoError(t, err)
+	defer SetSupportedCurrencies(DefaultSupportedCurrencies)
+
+	testCases := []struct {
+		value    string
+		currency string
+		amount   int64
+		decimal  string
+		err      error
+	}{
+		{"12.34", USD, 1234, "12.34", nil},
+		{"12.3", USD, 1230, "12.30", nil},
+		{"12", USD, 1200, "12.00", nil},
+		{"0.05", USD, 5, "0.05", nil},
+		{"-1.50", USD, -150, "-1.50", nil},
+		{"100", JPY, 100, "100", nil},
+		{"1.234", BHD, 1234, "1.234", nil},
+		{"12.345", USD, 0, "", ErrInvalidAmount},
+		{"1.5", JPY, 0, "", ErrInvalidAmount},
+		{"12.", USD, 0, "", ErrInvalidAmount},
+		{"1e3", USD, 0, "", ErrInvalidAmount},
+		{"", USD, 0, "", ErrInvalidAmount},
+		{"99999999999999999999", USD, 0, "", ErrInvalidAmount},
+		{"1.00", EUR, 0, "", ErrUnsupportedCurrency},
+		{"1.00", "XYZ", 0, "", ErrUnsupportedCurrency},
+	}
+
+	for _, tc := range testCases {
+		money, err := ParseMoney(tc.value, tc.currency)
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err, tc.value)
+			continue
+		}
+
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.amount, money.Amount)
+		require.Equal(t, tc.decimal, money.Decimal())
+		require.Equal(t, tc.decimal+" "+tc.currency, money.String())
+	}
+}
+
+func TestMoneyValidate(t *testing.T) {
+	money, err := NewMoney(1, USD)
+	require.NoError(t, err)
+	require.NoError(t, money.Validate())
+
+	money, err = NewMoney(0, USD)
+	require.NoError(t, err)
+	require.ErrorIs(t, money.Validate(), ErrInvalidAmount)
+
+	_, err = NewMoney(1, JPY)
+	require.ErrorIs(t, err, ErrUnsupportedCurrency)
+}
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "12.34", FormatAmount(1234, USD))
+	require.Equal(t, "1234", FormatAmount(1234, JPY))
+	require.Equal(t, "1.234", FormatAmount(1234, BHD))
+	require.Equal(t, "-0.01", FormatAmount(-1, EUR))
+	require.Equal(t, "-92233720368547758.08", FormatAmount(-9223372036854775808, USD))
+	require.Equal(t, "1234", FormatAmount(1234, "XYZ"))
+}
+
+func TestSetSupportedCurrencies(t *testing.T) {
+	defer SetSupportedCurrencies(DefaultSupportedCurrencies)
+
+	require.Equal(t, []string{CAD, EUR, USD}, SupportedCurrencies())
+	require.False(t, IsSupportedCurrency(GBP))
+
+	err := SetSupportedCurrencies([]string{USD, GBP})
+	require.NoError(t, err)
+	require.True(t, IsSupportedCurrency(GBP))
+	require.False(t, IsSupportedCurrency(EUR))
+
+	err = SetSupportedCurrencies([]string{USD, "XYZ"})
+	require.Error(t, err)
+	require.True(t, IsSupportedCurrency(GBP))
+
+	err = SetSupportedCurrencies(nil)
+	require.Error(t, err)
+}
@@ -38,7 +38,7 @@ func RandomOwner() string {
 
 // RandomMoney generates a random amount of money
 func RandomMoney() int64 {
-	return RandomInt(0, 1000)
+	return RandomInt(100, 1000)
 }
 
 // RandomCurrency generates a random currency code
@@ -0,0 +1,71 @@
+package util
+
+import "time"
+
+// Constants for all supported recurrences of a scheduled transfer
+const (
+	RecurrenceOnce    = "once"
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// IsSupportedRecurrence returns true if the recurrence is supported
+func IsSupportedRecurrence(recurrence string) bool {
+	switch recurrence {
+	case RecurrenceOnce, RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+		return true
+	}
+	return false
+}
+
+// NextOccurrence returns the first occurrence of a schedule that is strictly after the given time.
+// Occurrences are always computed from the anchor, so a monthly schedule anchored on the 31st
+// runs on the last day of shorter months and goes back to the 31st afterwards.
+// It returns false when the schedule has no occurrence left.
+func NextOccurrence(recurrence string, anchor time.Time, after time.Time) (time.Time, bool) {
+	if anchor.After(after) {
+		return anchor, IsSupportedRecurrence(recurrence)
+	}
+
+	var occurrence func(n int) time.Time
+	var n int
+
+	switch recurrence {
+	case RecurrenceDaily:
+		occurrence = func(n int) time.Time { return anchor.AddDate(0, 0, n) }
+		n = int(after.Sub(anchor) / (24 * time.Hour))
+	case RecurrenceWeekly:
+		occurrence = func(n int) time.Time { return anchor.AddDate(0, 0, 7*n) }
+		n = int(after.Sub(anchor) / (7 * 24 * time.Hour))
+	case RecurrenceMonthly:
+		occurrence = func(n int) time.Time { return addMonthsClamped(anchor, n) }
+		after := after.In(anchor.Location())
+		n = (after.Year()-anchor.Year())*12 + int(after.Month()-anchor.Month())
+	default:
+		return time.Time{}, false
+	}
+
+	// n is only an estimate because of daylight saving time and month lengths
+	for n > 0 && occurrence(n-1).After(after) {
+		n--
+	}
+	for !occurrence(n).After(after) {
+		n++
+	}
+
+	return occurrence(n), true
+}
+
+// addMonthsClamped adds months to t, keeping its day of month unless the target month is shorter.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	firstOfMonth := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
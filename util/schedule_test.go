@@ -0,0 +1,122 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+}
+
+func TestNextOccurrence(t *testing.T) {
+	testCases := []struct {
+		name       string
+		recurrence string
+		anchor     time.Time
+		after      time.Time
+		next       time.Time
+		ok         bool
+	}{
+		{
+			name:       "OnceBeforeAnchor",
+			recurrence: RecurrenceOnce,
+			anchor:     date(2024, time.March, 10),
+			after:      date(2024, time.March, 1),
+			next:       date(2024, time.March, 10),
+			ok:         true,
+		},
+		{
+			name:       "OnceAfterAnchor",
+			recurrence: RecurrenceOnce,
+			anchor:     date(2024, time.March, 10),
+			after:      date(2024, time.March, 10),
+			ok:         false,
+		},
+		{
+			name:       "Daily",
+			recurrence: RecurrenceDaily,
+			anchor:     date(2024, time.March, 10),
+			after:      date(2024, time.March, 10),
+			next:       date(2024, time.March, 11),
+			ok:         true,
+		},
+		{
+			name:       "DailySkipsMissedRuns",
+			recurrence: RecurrenceDaily,
+			anchor:     date(2024, time.March, 10),
+			after:      date(2024, time.March, 20).Add(time.Hour),
+			next:       date(2024, time.March, 21),
+			ok:         true,
+		},
+		{
+			name:       "Weekly",
+			recurrence: RecurrenceWeekly,
+			anchor:     date(2024, time.March, 10),
+			after:      date(2024, time.March, 12),
+			next:       date(2024, time.March, 17),
+			ok:         true,
+		},
+		{
+			name:       "Monthly",
+			recurrence: RecurrenceMonthly,
+			anchor:     date(2024, time.January, 1),
+			after:      date(2024, time.January, 1),
+			next:       date(2024, time.February, 1),
+			ok:         true,
+		},
+		{
+			name:       "MonthlyEndOfMonthLeapYear",
+			recurrence: RecurrenceMonthly,
+			anchor:     date(2024, time.January, 31),
+			after:      date(2024, time.January, 31),
+			next:       date(2024, time.February, 29),
+			ok:         true,
+		},
+		{
+			name:       "MonthlyEndOfMonth",
+			recurrence: RecurrenceMonthly,
+			anchor:     date(2023, time.January, 31),
+			after:      date(2023, time.March, 31),
+			next:       date(2023, time.April, 30),
+			ok:         true,
+		},
+		{
+			name:       "MonthlyBackToAnchorDay",
+			recurrence: RecurrenceMonthly,
+			anchor:     date(2023, time.January, 31),
+			after:      date(2023, time.February, 28),
+			next:       date(2023, time.March, 31),
+			ok:         true,
+		},
+		{
+			name:       "MonthlyAcrossYear",
+			recurrence: RecurrenceMonthly,
+			anchor:     date(2023, time.March, 15),
+			after:      date(2023, time.December, 20),
+			next:       date(2024, time.January, 15),
+			ok:         true,
+		},
+		{
+			name:       "UnsupportedRecurrence",
+			recurrence: "yearly",
+			anchor:     date(2024, time.March, 10),
+			after:      date(2024, time.April, 10),
+			ok:         false,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			next, ok := NextOccurrence(tc.recurrence, tc.anchor, tc.after)
+			require.Equal(t, tc.ok, ok)
+			if tc.ok {
+				require.True(t, next.Equal(tc.next), "expected %s, got %s", tc.next, next)
+			}
+		})
+	}
+}
@@ -3,7 +3,11 @@ package val
 import (
 	"fmt"
 	"net/mail"
+	"net/url"
 	"regexp"
+	"strings"
+
+	"github.com/antimatter007/go-backend/util"
 )
 
 var (
@@ -63,3 +67,71 @@ func ValidateEmailId(value int64) error {
 func ValidateSecretCode(value string) error {
 	return ValidateString(value, 32, 128)
 }
+
+func ValidateID(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func ValidatePageID(value int32) error {
+	if value < 1 {
+		return fmt.Errorf("must be greater than or equal to 1")
+	}
+	return nil
+}
+
+func ValidatePageSize(value int32) error {
+	if value < 5 || value > 50 {
+		return fmt.Errorf("must be between 5 and 50")
+	}
+	return nil
+}
+
+func ValidateWebhookURL(value string) error {
+	if err := ValidateString(value, 10, 2048); err != nil {
+		return err
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("is not a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("must use http or https")
+	}
+	return nil
+}
+
+func ValidateAmount(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func ValidateCurrency(value string) error {
+	if !util.IsSupportedCurrency(value) {
+		return fmt.Errorf("must be one of %s", strings.Join(util.SupportedCurrencies(), ", "))
+	}
+	return nil
+}
+
+// ValidateMoney checks an amount in minor units of a currency that is known to be supported
+func ValidateMoney(amount int64, currency string) error {
+	money, err := util.NewMoney(amount, currency)
+	if err != nil {
+		return err
+	}
+	if err := money.Validate(); err != nil {
+		return fmt.Errorf("must be a positive number of minor units of %s", currency)
+	}
+	return nil
+}
+
+func ValidateRecurrence(value string) error {
+	if !util.IsSupportedRecurrence(value) {
+		return fmt.Errorf("must be one of once, daily, weekly or monthly")
+	}
+	return nil
+}
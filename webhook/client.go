@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Headers sent along with every delivery
+const (
+	IDHeader    = "Webhook-Id"
+	EventHeader = "Webhook-Event"
+)
+
+// maxResponseBody limits how much of the receiver's response is read, so that the connection can be reused.
+// The body itself is never kept: endpoints are registered by customers and may point anywhere.
+const maxResponseBody = 512
+
+// ErrForbiddenAddress is returned for endpoints that resolve to loopback, private or link-local addresses
+var ErrForbiddenAddress = errors.New("endpoint address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range, which is not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicIP reports whether ip may receive webhooks, internal services and cloud metadata endpoints may not
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// dialPublicOnly checks the address that is actually dialed, after DNS resolution,
+// so that a host name cannot be rebound to an internal address after it was validated
+func dialPublicOnly(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// Request is a single signed delivery attempt
+type Request struct {
+	DeliveryID int64
+	Event      string
+	URL        string
+	Secret     string
+	Payload    []byte
+}
+
+// Client posts signed payloads to webhook endpoints
+type Client interface {
+	Send(ctx context.Context, req Request) (statusCode int, err error)
+}
+
+// HTTPClient delivers webhooks over HTTP
+type HTTPClient struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewHTTPClient creates a new webhook client that gives up on a delivery after timeout.
+// Endpoints on loopback, private or link-local addresses are refused unless allowPrivateNetworks is set,
+// which is only meant for local development. Redirects are never followed.
+func NewHTTPClient(timeout time.Duration, allowPrivateNetworks bool) Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivateNetworks {
+		dialer.Control = dialPublicOnly
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// a proxy would be dialed instead of the endpoint, escaping the address check
+	transport.Proxy = nil
+
+	return &HTTPClient{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			// the redirect response is returned as is, and reported as a failed delivery
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send posts the payload and returns the response status code.
+// Any status code outside of the 2xx range is reported as an error, without the response body.
+func (c *HTTPClient) Send(ctx context.Context, req Request) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "simple-bank-webhook/1.0")
+	httpReq.Header.Set(IDHeader, strconv.FormatInt(req.DeliveryID, 10))
+	httpReq.Header.Set(EventHeader, req.Event)
+	httpReq.Header.Set(SignatureHeader, Sign(req.Secret, c.now(), req.Payload))
+
+	rsp, err := c.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(rsp.Body, maxResponseBody))
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return rsp.StatusCode, fmt.Errorf("endpoint responded with status %d", rsp.StatusCode)
+	}
+
+	return rsp.StatusCode, nil
+}
@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antimatter007/go-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClientSend(t *testing.T) {
+	secret := util.RandomString(32)
+	payload := []byte(`{"id":1,"event":"transfer.created"}`)
+
+	var received *http.Request
+	var receivedBody []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+
+		err := Verify(secret, r.Header.Get(SignatureHeader), receivedBody, time.Minute, time.Now())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	client := NewHTTPClient(time.Second, true)
+	statusCode, err := client.Send(context.Background(), Request{
+		DeliveryID: 42,
+		Event:      EventTransferCreated,
+		URL:        receiver.URL,
+		Secret:     secret,
+		Payload:    payload,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, statusCode)
+
+	require.NotNil(t, received)
+	require.Equal(t, http.MethodPost, received.Method)
+	require.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	require.Equal(t, "42", received.Header.Get(IDHeader))
+	require.Equal(t, EventTransferCreated, received.Header.Get(EventHeader))
+	require.Equal(t, payload, receivedBody)
+}
+
+func TestHTTPClientSendErrorStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("boom"))
+	}))
+	defer receiver.Close()
+
+	client := NewHTTPClient(time.Second, true)
+	statusCode, err := client.Send(context.Background(), Request{
+		DeliveryID: 1,
+		Event:      EventAccountCreated,
+		URL:        receiver.URL,
+		Secret:     util.RandomString(32),
+		Payload:    []byte(`{}`),
+	})
+	require.EqualError(t, err, "endpoint responded with status 500")
+	require.NotContains(t, err.Error(), "boom")
+	require.Equal(t, http.StatusInternalServerError, statusCode)
+}
+
+func TestHTTPClientSendPrivateAddress(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	client := NewHTTPClient(time.Second, false)
+	statusCode, err := client.Send(context.Background(), Request{
+		DeliveryID: 1,
+		Event:      EventAccountCreated,
+		URL:        receiver.URL,
+		Secret:     util.RandomString(32),
+		Payload:    []byte(`{}`),
+	})
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+	require.Zero(t, statusCode)
+	require.False(t, called)
+}
+
+func TestHTTPClientSendRedirect(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer receiver.Close()
+
+	client := NewHTTPClient(time.Second, true)
+	statusCode, err := client.Send(context.Background(), Request{
+		DeliveryID: 1,
+		Event:      EventAccountCreated,
+		URL:        receiver.URL,
+		Secret:     util.RandomString(32),
+		Payload:    []byte(`{}`),
+	})
+	require.EqualError(t, err, "endpoint responded with status 307")
+	require.Equal(t, http.StatusTemporaryRedirect, statusCode)
+	require.False(t, redirected)
+}
+
+func TestPublicIP(t *testing.T) {
+	testCases := []struct {
+		ip     string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.public, publicIP(net.ParseIP(tc.ip)), tc.ip)
+	}
+}
+
+func TestHTTPClientSendTimeout(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer receiver.Close()
+
+	client := NewHTTPClient(50*time.Millisecond, true)
+	statusCode, err := client.Send(context.Background(), Request{
+		DeliveryID: 1,
+		Event:      EventAccountCreated,
+		URL:        receiver.URL,
+		Secret:     util.RandomString(32),
+		Payload:    []byte(`{}`),
+	})
+	require.Error(t, err)
+	require.Zero(t, statusCode)
+}
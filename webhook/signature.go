@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the HTTP header that carries the payload signature
+const SignatureHeader = "Webhook-Signature"
+
+var (
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrExpiredSignature = errors.New("webhook signature has expired")
+)
+
+// Sign computes the signature header value for a payload sent at the given time.
+// The format is "t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<payload>">".
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, computeSignature(secret, ts, payload))
+}
+
+// Verify checks that the signature header matches the payload and was created
+// no longer than tolerance before now. Receivers use it to authenticate deliveries.
+func Verify(secret string, header string, payload []byte, tolerance time.Duration, now time.Time) error {
+	var ts, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return ErrInvalidSignature
+		}
+
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	if ts == "" || signature == "" {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected := computeSignature(secret, ts, payload)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrInvalidSignature
+	}
+
+	if now.Sub(time.Unix(unix, 0)) > tolerance {
+		return ErrExpiredSignature
+	}
+
+	return nil
+}
+
+func computeSignature(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
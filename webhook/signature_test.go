@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antimatter007/go-backend/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := util.RandomString(32)
+	payload := []byte(`{"event":"transfer.created"}`)
+	now := time.Now()
+
+	header := Sign(secret, now, payload)
+	require.Regexp(t, `^t=\d+,v1=[0-9a-f]{64}$`, header)
+
+	err := Verify(secret, header, payload, time.Minute, now)
+	require.NoError(t, err)
+}
+
+func TestVerifyInvalidSignature(t *testing.T) {
+	secret := util.RandomString(32)
+	payload := []byte(`{"event":"transfer.created"}`)
+	now := time.Now()
+	header := Sign(secret, now, payload)
+
+	testCases := []struct {
+		name    string
+		secret  string
+		header  string
+		payload []byte
+	}{
+		{
+			name:    "WrongSecret",
+			secret:  util.RandomString(32),
+			header:  header,
+			payload: payload,
+		},
+		{
+			name:    "TamperedPayload",
+			secret:  secret,
+			header:  header,
+			payload: []byte(`{"event":"account.created"}`),
+		},
+		{
+			name:    "MissingTimestamp",
+			secret:  secret,
+			header:  "v1=abc",
+			payload: payload,
+		},
+		{
+			name:    "Malformed",
+			secret:  secret,
+			header:  "garbage",
+			payload: payload,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.secret, tc.header, tc.payload, time.Minute, now)
+			require.ErrorIs(t, err, ErrInvalidSignature)
+		})
+	}
+}
+
+func TestVerifyExpiredSignature(t *testing.T) {
+	secret := util.RandomString(32)
+	payload := []byte(`{}`)
+	now := time.Now()
+
+	header := Sign(secret, now.Add(-10*time.Minute), payload)
+	err := Verify(secret, header, payload, 5*time.Minute, now)
+	require.ErrorIs(t, err, ErrExpiredSignature)
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, 10*time.Second, Backoff(0))
+	require.Equal(t, 20*time.Second, Backoff(1))
+	require.Equal(t, 80*time.Second, Backoff(3))
+	require.Equal(t, 6*time.Hour, Backoff(20))
+}
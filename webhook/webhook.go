@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Events that can be delivered to webhook endpoints
+const (
+	EventTransferCreated   = "transfer.created"
+	EventAccountCreated    = "account.created"
+	EventUserEmailVerified = "user.email_verified"
+)
+
+// Delivery statuses recorded in the delivery log
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// IsSupportedEvent returns true if endpoints can subscribe to the event
+func IsSupportedEvent(event string) bool {
+	switch event {
+	case EventTransferCreated, EventAccountCreated, EventUserEmailVerified:
+		return true
+	}
+	return false
+}
+
+// Envelope is the JSON body posted to webhook endpoints
+type Envelope struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewSecret generates a random secret used to sign payloads for one endpoint
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+const (
+	minBackoff = 10 * time.Second
+	maxBackoff = 6 * time.Hour
+)
+
+// Backoff returns how long to wait before the next delivery attempt.
+// The delay doubles with each retry, starting at 10 seconds and capped at 6 hours.
+func Backoff(retried int) time.Duration {
+	delay := minBackoff
+	for i := 0; i < retried; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
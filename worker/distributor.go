@@ -12,6 +12,26 @@ type TaskDistributor interface {
 		payload *PayloadSendVerifyEmail,
 		opts ...asynq.Option,
 	) error
+	DistributeTaskSendTransferNotification(
+		ctx context.Context,
+		payload *PayloadSendTransferNotification,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskDeliverNotification(
+		ctx context.Context,
+		payload *PayloadDeliverNotification,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskPublishWebhookEvent(
+		ctx context.Context,
+		payload *PayloadPublishWebhookEvent,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskDeliverWebhook(
+		ctx context.Context,
+		payload *PayloadDeliverWebhook,
+		opts ...asynq.Option,
+	) error
 }
 
 type RedisTaskDistributor struct {
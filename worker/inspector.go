@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"github.com/hibiken/asynq"
+)
+
+// Resolutions of a permanently failed task, as recorded in the failed_tasks table
+const (
+	FailedTaskArchived = "archived"
+	FailedTaskRequeued = "requeued"
+	FailedTaskDeleted  = "deleted"
+)
+
+// TaskInspector gives operators access to the tasks that asynq has archived
+// after they ran out of retries or were told not to retry.
+type TaskInspector interface {
+	ListArchivedTasks(queue string, page int, pageSize int) ([]*asynq.TaskInfo, error)
+	GetTaskInfo(queue string, id string) (*asynq.TaskInfo, error)
+	RunTask(queue string, id string) error
+	DeleteTask(queue string, id string) error
+	Ping() error
+}
+
+type RedisTaskInspector struct {
+	inspector *asynq.Inspector
+}
+
+func NewRedisTaskInspector(redisOpt asynq.RedisClientOpt) TaskInspector {
+	inspector := asynq.NewInspector(redisOpt)
+	return &RedisTaskInspector{
+		inspector: inspector,
+	}
+}
+
+func (inspector *RedisTaskInspector) ListArchivedTasks(queue string, page int, pageSize int) ([]*asynq.TaskInfo, error) {
+	return inspector.inspector.ListArchivedTasks(queue, asynq.Page(page), asynq.PageSize(pageSize))
+}
+
+func (inspector *RedisTaskInspector) GetTaskInfo(queue string, id string) (*asynq.TaskInfo, error) {
+	return inspector.inspector.GetTaskInfo(queue, id)
+}
+
+func (inspector *RedisTaskInspector) RunTask(queue string, id string) error {
+	return inspector.inspector.RunTask(queue, id)
+}
+
+func (inspector *RedisTaskInspector) DeleteTask(queue string, id string) error {
+	return inspector.inspector.DeleteTask(queue, id)
+}
+
+// Ping checks that Redis can be reached by listing the queues
+func (inspector *RedisTaskInspector) Ping() error {
+	_, err := inspector.inspector.Queues()
+	return err
+}
@@ -0,0 +1,196 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+var cleanupDeletedRows = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "worker",
+		Name:      "cleanup_deleted_rows_total",
+		Help:      "Number of rows removed by the periodic cleanup tasks.",
+	},
+	[]string{"table"},
+)
+
+var scheduledTransferRuns = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "worker",
+		Name:      "scheduled_transfer_runs_total",
+		Help:      "Number of scheduled transfer runs executed, by outcome.",
+	},
+	[]string{"status"},
+)
+
+var expiredHolds = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "worker",
+		Name:      "expired_holds_total",
+		Help:      "Number of holds released by the periodic expiry task.",
+	},
+)
+
+var reconciliationRuns = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "worker",
+		Name:      "reconciliation_runs_total",
+		Help:      "Number of balance reconciliations run, by outcome.",
+	},
+	[]string{"status"},
+)
+
+var reconciliationDiscrepancies = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: "simple_bank",
+		Subsystem: "worker",
+		Name:      "reconciliation_discrepancies",
+		Help:      "Number of accounts whose balance didn't match the ledger in the last completed reconciliation.",
+	},
+)
+
+var interestPostings = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "worker",
+		Name:      "interest_postings_total",
+		Help:      "Number of accounts whose accrued interest was posted.",
+	},
+)
+
+var interestPaid = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "worker",
+		Name:      "interest_paid_total",
+		Help:      "Interest paid out to accounts, in minor units of their currency.",
+	},
+	[]string{"currency"},
+)
+
+var tasksProcessed = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "simple_bank",
+		Subsystem: "worker",
+		Name:      "tasks_processed_total",
+		Help:      "Number of task attempts processed, by task type and outcome.",
+	},
+	[]string{"type", "status"},
+)
+
+var taskDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "simple_bank",
+		Subsystem: "worker",
+		Name:      "task_duration_seconds",
+		Help:      "Time taken to process a task attempt, by task type.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"type"},
+)
+
+// Outcomes of a task attempt, as recorded by tasksProcessed
+const (
+	taskSucceeded = "succeeded"
+	taskFailed    = "failed"
+	taskSkipped   = "skipped"
+)
+
+// taskMetrics records the outcome and duration of every task attempt.
+// Tasks are labelled by the pattern of their handler, and the ones without a handler as unknown,
+// to keep the type label bounded.
+func taskMetrics(mux *asynq.ServeMux) asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			taskType := "unknown"
+			if _, pattern := mux.Handler(task); pattern != "" {
+				taskType = pattern
+			}
+
+			startTime := time.Now()
+			err := next.ProcessTask(ctx, task)
+			taskDuration.WithLabelValues(taskType).Observe(time.Since(startTime).Seconds())
+
+			outcome := taskSucceeded
+			if err != nil {
+				outcome = taskFailed
+				// a skipped retry is a task that can never succeed, such as one whose payload is invalid
+				if errors.Is(err, asynq.SkipRetry) {
+					outcome = taskSkipped
+				}
+			}
+			tasksProcessed.WithLabelValues(taskType, outcome).Inc()
+
+			return err
+		})
+	}
+}
+
+// queueCollector exports the number of tasks in every asynq queue by state
+type queueCollector struct {
+	inspector *asynq.Inspector
+
+	size    *prometheus.Desc
+	latency *prometheus.Desc
+}
+
+// NewQueueCollector creates a collector of the asynq queue depths, read from Redis on every scrape
+func NewQueueCollector(redisOpt asynq.RedisClientOpt) prometheus.Collector {
+	return &queueCollector{
+		inspector: asynq.NewInspector(redisOpt),
+		size: prometheus.NewDesc(
+			prometheus.BuildFQName("simple_bank", "worker", "queue_tasks"),
+			"Number of tasks in the queue, by state.",
+			[]string{"queue", "state"}, nil,
+		),
+		latency: prometheus.NewDesc(
+			prometheus.BuildFQName("simple_bank", "worker", "queue_latency_seconds"),
+			"Time the oldest pending task of the queue has been waiting.",
+			[]string{"queue"}, nil,
+		),
+	}
+}
+
+func (collector *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.size
+	ch <- collector.latency
+}
+
+func (collector *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	queues, err := collector.inspector.Queues()
+	if err != nil {
+		log.Error().Err(err).Msg("cannot list queues for metrics")
+		return
+	}
+
+	for _, queue := range queues {
+		info, err := collector.inspector.GetQueueInfo(queue)
+		if err != nil {
+			log.Error().Err(err).Str("queue", queue).Msg("cannot get queue info for metrics")
+			continue
+		}
+
+		states := map[string]int{
+			"pending":   info.Pending,
+			"active":    info.Active,
+			"scheduled": info.Scheduled,
+			"retry":     info.Retry,
+			"archived":  info.Archived,
+			"completed": info.Completed,
+		}
+		for state, size := range states {
+			ch <- prometheus.MustNewConstMetric(collector.size, prometheus.GaugeValue, float64(size), queue, state)
+		}
+		ch <- prometheus.MustNewConstMetric(collector.latency, prometheus.GaugeValue, info.Latency.Seconds(), queue)
+	}
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskMetrics(t *testing.T) {
+	testCases := []struct {
+		name      string
+		taskType  string
+		err       error
+		typeLabel string
+		status    string
+	}{
+		{
+			name:      "Succeeded",
+			taskType:  TaskExpireHolds,
+			typeLabel: TaskExpireHolds,
+			status:    taskSucceeded,
+		},
+		{
+			name:      "Failed",
+			taskType:  TaskExpireHolds,
+			err:       errors.New("db is down"),
+			typeLabel: TaskExpireHolds,
+			status:    taskFailed,
+		},
+		{
+			name:      "Skipped",
+			taskType:  TaskExpireHolds,
+			err:       fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry),
+			typeLabel: TaskExpireHolds,
+			status:    taskSkipped,
+		},
+		{
+			name:      "UnknownType",
+			taskType:  "task:no_such_task",
+			err:       errors.New("handler not found"),
+			typeLabel: "unknown",
+			status:    taskFailed,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			mux := asynq.NewServeMux()
+			mux.HandleFunc(TaskExpireHolds, func(ctx context.Context, task *asynq.Task) error {
+				return tc.err
+			})
+			handler := taskMetrics(mux)(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+				return tc.err
+			}))
+
+			counter := tasksProcessed.WithLabelValues(tc.typeLabel, tc.status)
+			before := testutil.ToFloat64(counter)
+
+			err := handler.ProcessTask(context.Background(), asynq.NewTask(tc.taskType, nil))
+			require.Equal(t, tc.err, err)
+			require.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
@@ -1,41 +1,118 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: "github.com/antimatter007/go-backend/worker" (interfaces: TaskDistributor)
+// Source: github.com/antimatter007/go-backend/worker (interfaces: TaskDistributor)
 
 // Package mockwk is a generated GoMock package.
 package mockwk
 
 import (
 	context "context"
+	reflect "reflect"
+
+	worker "github.com/antimatter007/go-backend/worker"
 	gomock "github.com/golang/mock/gomock"
 	asynq "github.com/hibiken/asynq"
-	worker "github.com/antimatter007/go-backend/worker"
-	reflect "reflect"
 )
 
-// MockTaskDistributor is a mock of TaskDistributor interface
+// MockTaskDistributor is a mock of TaskDistributor interface.
 type MockTaskDistributor struct {
 	ctrl     *gomock.Controller
 	recorder *MockTaskDistributorMockRecorder
 }
 
-// MockTaskDistributorMockRecorder is the mock recorder for MockTaskDistributor
+// MockTaskDistributorMockRecorder is the mock recorder for MockTaskDistributor.
 type MockTaskDistributorMockRecorder struct {
 	mock *MockTaskDistributor
 }
 
-// NewMockTaskDistributor creates a new mock instance
+// NewMockTaskDistributor creates a new mock instance.
 func NewMockTaskDistributor(ctrl *gomock.Controller) *MockTaskDistributor {
 	mock := &MockTaskDistributor{ctrl: ctrl}
 	mock.recorder = &MockTaskDistributorMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockTaskDistributor) EXPECT() *MockTaskDistributorMockRecorder {
 	return m.recorder
 }
 
-// DistributeTaskSendVerifyEmail mocks base method
+// DistributeTaskDeliverNotification mocks base method.
+func (m *MockTaskDistributor) DistributeTaskDeliverNotification(arg0 context.Context, arg1 *worker.PayloadDeliverNotification, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskDeliverNotification", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskDeliverNotification indicates an expected call of DistributeTaskDeliverNotification.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskDeliverNotification(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskDeliverNotification", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskDeliverNotification), varargs...)
+}
+
+// DistributeTaskDeliverWebhook mocks base method.
+func (m *MockTaskDistributor) DistributeTaskDeliverWebhook(arg0 context.Context, arg1 *worker.PayloadDeliverWebhook, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskDeliverWebhook", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskDeliverWebhook indicates an expected call of DistributeTaskDeliverWebhook.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskDeliverWebhook(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskDeliverWebhook", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskDeliverWebhook), varargs...)
+}
+
+// DistributeTaskPublishWebhookEvent mocks base method.
+func (m *MockTaskDistributor) DistributeTaskPublishWebhookEvent(arg0 context.Context, arg1 *worker.PayloadPublishWebhookEvent, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskPublishWebhookEvent", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskPublishWebhookEvent indicates an expected call of DistributeTaskPublishWebhookEvent.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskPublishWebhookEvent(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskPublishWebhookEvent", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskPublishWebhookEvent), varargs...)
+}
+
+// DistributeTaskSendTransferNotification mocks base method.
+func (m *MockTaskDistributor) DistributeTaskSendTransferNotification(arg0 context.Context, arg1 *worker.PayloadSendTransferNotification, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskSendTransferNotification", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskSendTransferNotification indicates an expected call of DistributeTaskSendTransferNotification.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskSendTransferNotification(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskSendTransferNotification", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskSendTransferNotification), varargs...)
+}
+
+// DistributeTaskSendVerifyEmail mocks base method.
 func (m *MockTaskDistributor) DistributeTaskSendVerifyEmail(arg0 context.Context, arg1 *worker.PayloadSendVerifyEmail, arg2 ...asynq.Option) error {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
@@ -47,7 +124,7 @@ func (m *MockTaskDistributor) DistributeTaskSendVerifyEmail(arg0 context.Context
 	return ret0
 }
 
-// DistributeTaskSendVerifyEmail indicates an expected call of DistributeTaskSendVerifyEmail
+// DistributeTaskSendVerifyEmail indicates an expected call of DistributeTaskSendVerifyEmail.
 func (mr *MockTaskDistributorMockRecorder) DistributeTaskSendVerifyEmail(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/antimatter007/go-backend/worker (interfaces: TaskInspector)
+
+// Package mockwk is a generated GoMock package.
+package mockwk
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	asynq "github.com/hibiken/asynq"
+)
+
+// MockTaskInspector is a mock of TaskInspector interface.
+type MockTaskInspector struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskInspectorMockRecorder
+}
+
+// MockTaskInspectorMockRecorder is the mock recorder for MockTaskInspector.
+type MockTaskInspectorMockRecorder struct {
+	mock *MockTaskInspector
+}
+
+// NewMockTaskInspector creates a new mock instance.
+func NewMockTaskInspector(ctrl *gomock.Controller) *MockTaskInspector {
+	mock := &MockTaskInspector{ctrl: ctrl}
+	mock.recorder = &MockTaskInspectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskInspector) EXPECT() *MockTaskInspectorMockRecorder {
+	return m.recorder
+}
+
+// DeleteTask mocks base method.
+func (m *MockTaskInspector) DeleteTask(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockTaskInspectorMockRecorder) DeleteTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskInspector)(nil).DeleteTask), arg0, arg1)
+}
+
+// GetTaskInfo mocks base method.
+func (m *MockTaskInspector) GetTaskInfo(arg0, arg1 string) (*asynq.TaskInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskInfo", arg0, arg1)
+	ret0, _ := ret[0].(*asynq.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskInfo indicates an expected call of GetTaskInfo.
+func (mr *MockTaskInspectorMockRecorder) GetTaskInfo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskInfo", reflect.TypeOf((*MockTaskInspector)(nil).GetTaskInfo), arg0, arg1)
+}
+
+// ListArchivedTasks mocks base method.
+func (m *MockTaskInspector) ListArchivedTasks(arg0 string, arg1, arg2 int) ([]*asynq.TaskInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArchivedTasks", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*asynq.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArchivedTasks indicates an expected call of ListArchivedTasks.
+func (mr *MockTaskInspectorMockRecorder) ListArchivedTasks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchivedTasks", reflect.TypeOf((*MockTaskInspector)(nil).ListArchivedTasks), arg0, arg1, arg2)
+}
+
+// Ping mocks base method.
+func (m *MockTaskInspector) Ping() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockTaskInspectorMockRecorder) Ping() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockTaskInspector)(nil).Ping))
+}
+
+// RunTask mocks base method.
+func (m *MockTaskInspector) RunTask(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunTask", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunTask indicates an expected call of RunTask.
+func (mr *MockTaskInspectorMockRecorder) RunTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunTask", reflect.TypeOf((*MockTaskInspector)(nil).RunTask), arg0, arg1)
+}
@@ -2,12 +2,16 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	db "github.com/antimatter007/go-backend/db/sqlc"
 	"github.com/antimatter007/go-backend/mail"
+	"github.com/antimatter007/go-backend/notification"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/antimatter007/go-backend/webhook"
 	"github.com/go-redis/redis/v8"
 	"github.com/hibiken/asynq"
-	"github.com/rs/zerolog/log"
 )
 
 const (
@@ -17,45 +21,148 @@ const (
 
 type TaskProcessor interface {
 	Start() error
+	Shutdown()
 	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendTransferNotification(ctx context.Context, task *asynq.Task) error
+	ProcessTaskDeliverNotification(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPublishWebhookEvent(ctx context.Context, task *asynq.Task) error
+	ProcessTaskDeliverWebhook(ctx context.Context, task *asynq.Task) error
+	ProcessTaskCleanupExpiredSessions(ctx context.Context, task *asynq.Task) error
+	ProcessTaskCleanupExpiredVerifyEmails(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExecuteScheduledTransfers(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExpireHolds(ctx context.Context, task *asynq.Task) error
+	ProcessTaskReconcileBalances(ctx context.Context, task *asynq.Task) error
+	ProcessTaskAccrueInterest(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
-	server *asynq.Server
-	store  db.Store
-	mailer mail.EmailSender
+	server        *asynq.Server
+	store         db.Store
+	mailer        mail.EmailSender
+	notifier      *notification.Dispatcher
+	distributor   TaskDistributor
+	webhookClient webhook.Client
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer mail.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(
+	redisOpt asynq.RedisClientOpt,
+	store db.Store,
+	mailer mail.EmailSender,
+	distributor TaskDistributor,
+	webhookClient webhook.Client,
+	shutdownTimeout time.Duration,
+) TaskProcessor {
 	logger := NewLogger()
 	redis.SetLogger(logger)
 
-	server := asynq.NewServer(
+	notifier := notification.NewDispatcher(
+		store,
+		notification.NewEmailChannel(mailer),
+		notification.NewInAppChannel(store),
+	)
+
+	processor := &RedisTaskProcessor{
+		store:         store,
+		mailer:        mailer,
+		notifier:      notifier,
+		distributor:   distributor,
+		webhookClient: webhookClient,
+	}
+
+	processor.server = asynq.NewServer(
 		redisOpt,
 		asynq.Config{
 			Queues: map[string]int{
 				QueueCritical: 10,
 				QueueDefault:  5,
 			},
-			ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
-				log.Error().Err(err).Str("type", task.Type()).
-					Bytes("payload", task.Payload()).Msg("process task failed")
-			}),
-			Logger: logger,
+			ErrorHandler:   asynq.ErrorHandlerFunc(processor.handleError),
+			RetryDelayFunc: retryDelay,
+			Logger:         logger,
+			// running tasks that don't finish in time are requeued and run again by another worker
+			ShutdownTimeout: shutdownTimeout,
 		},
 	)
 
-	return &RedisTaskProcessor{
-		server: server,
-		store:  store,
-		mailer: mailer,
+	return processor
+}
+
+// handleError logs every failed attempt, and records a summary row in Postgres
+// once asynq archives the task, so that permanent failures can be reported on.
+func (processor *RedisTaskProcessor) handleError(ctx context.Context, task *asynq.Task, err error) {
+	requestid.Logger(ctx).Error().Err(err).Str("type", task.Type()).
+		Bytes("payload", task.Payload()).Msg("process task failed")
+
+	if !errors.Is(err, asynq.SkipRetry) && !isLastAttempt(ctx) {
+		return
+	}
+
+	taskID, _ := asynq.GetTaskID(ctx)
+	queue, _ := asynq.GetQueueName(ctx)
+	retried, _ := asynq.GetRetryCount(ctx)
+
+	_, dbErr := processor.store.UpsertFailedTask(ctx, db.UpsertFailedTaskParams{
+		TaskID:    taskID,
+		Queue:     queue,
+		Type:      task.Type(),
+		Payload:   task.Payload(),
+		LastError: err.Error(),
+		Retried:   int32(retried),
+	})
+	if dbErr != nil {
+		requestid.Logger(ctx).Error().Err(dbErr).Str("type", task.Type()).Str("task_id", taskID).
+			Msg("failed to record failed task")
+		return
+	}
+
+	requestid.Logger(ctx).Warn().Str("type", task.Type()).Str("task_id", taskID).Str("queue", queue).
+		Msg("task failed permanently and was archived")
+}
+
+// isLastAttempt reports whether asynq will give up on the task if the current attempt fails
+func isLastAttempt(ctx context.Context) bool {
+	retried, ok := asynq.GetRetryCount(ctx)
+	if !ok {
+		return true
 	}
+
+	maxRetry, ok := asynq.GetMaxRetry(ctx)
+	if !ok {
+		return true
+	}
+
+	return retried >= maxRetry
+}
+
+// retryDelay backs off exponentially for webhook deliveries, so that an endpoint
+// which is down is not hammered, and uses the asynq default for every other task.
+func retryDelay(retried int, err error, task *asynq.Task) time.Duration {
+	if task.Type() == TaskDeliverWebhook {
+		return webhook.Backoff(retried)
+	}
+	return asynq.DefaultRetryDelayFunc(retried, err, task)
 }
 
 func (processor *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 
 	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(TaskSendTransferNotification, processor.ProcessTaskSendTransferNotification)
+	mux.HandleFunc(TaskDeliverNotification, processor.ProcessTaskDeliverNotification)
+	mux.HandleFunc(TaskPublishWebhookEvent, processor.ProcessTaskPublishWebhookEvent)
+	mux.HandleFunc(TaskDeliverWebhook, processor.ProcessTaskDeliverWebhook)
+	mux.HandleFunc(TaskCleanupExpiredSessions, processor.ProcessTaskCleanupExpiredSessions)
+	mux.HandleFunc(TaskCleanupExpiredVerifyEmails, processor.ProcessTaskCleanupExpiredVerifyEmails)
+	mux.HandleFunc(TaskExecuteScheduledTransfers, processor.ProcessTaskExecuteScheduledTransfers)
+	mux.HandleFunc(TaskExpireHolds, processor.ProcessTaskExpireHolds)
+	mux.HandleFunc(TaskReconcileBalances, processor.ProcessTaskReconcileBalances)
+	mux.HandleFunc(TaskAccrueInterest, processor.ProcessTaskAccrueInterest)
+	mux.Use(taskRequestID, taskTracing(mux), taskMetrics(mux))
 
 	return processor.server.Start(mux)
 }
+
+// Shutdown stops pulling new tasks and waits for the running ones to finish, up to the shutdown timeout
+func (processor *RedisTaskProcessor) Shutdown() {
+	processor.server.Shutdown()
+}
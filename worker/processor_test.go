@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+)
+
+func TestHandleErrorRecordsSkippedTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	task := asynq.NewTask(TaskSendVerifyEmail, []byte(`{"username":"alice"}`))
+	err := fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+
+	store.EXPECT().
+		UpsertFailedTask(gomock.Any(), gomock.Eq(db.UpsertFailedTaskParams{
+			Type:      TaskSendVerifyEmail,
+			Payload:   task.Payload(),
+			LastError: err.Error(),
+		})).
+		Times(1).
+		Return(db.FailedTask{}, nil)
+
+	processor := &RedisTaskProcessor{store: store}
+	processor.handleError(context.Background(), task, err)
+}
+
+func TestHandleErrorIgnoresFailedStoreWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		UpsertFailedTask(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.FailedTask{}, errors.New("connection refused"))
+
+	processor := &RedisTaskProcessor{store: store}
+	processor.handleError(context.Background(), asynq.NewTask(TaskSendVerifyEmail, nil), asynq.SkipRetry)
+}
@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/hibiken/asynq"
+)
+
+// requestIDPayload reads the request id of any task payload that has one
+type requestIDPayload struct {
+	RequestID string `json:"request_id"`
+}
+
+// taskRequestID puts the request id found in the payload of a task in its context.
+// Tasks that no request enqueued, such as the periodic ones, use their task id instead,
+// so that the lines logged by one run can still be told apart.
+func taskRequestID(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		var payload requestIDPayload
+		if err := json.Unmarshal(task.Payload(), &payload); err != nil || !requestid.Valid(payload.RequestID) {
+			payload.RequestID, _ = asynq.GetTaskID(ctx)
+		}
+
+		if payload.RequestID != "" {
+			ctx = requestid.NewContext(ctx, payload.RequestID)
+		}
+		return next.ProcessTask(ctx, task)
+	})
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRequestID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		payload    string
+		expectedID string
+	}{
+		{
+			name:       "FromPayload",
+			payload:    `{"username":"alice","request_id":"req-1"}`,
+			expectedID: "req-1",
+		},
+		{
+			name:    "NoRequestID",
+			payload: `{"batch_size":10}`,
+		},
+		{
+			name:    "InvalidRequestID",
+			payload: `{"request_id":"req 1\n"}`,
+		},
+		{
+			name:    "NotJSON",
+			payload: `not json`,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var id string
+			handler := taskRequestID(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+				id = requestid.FromContext(ctx)
+				return nil
+			}))
+
+			// outside of an asynq server the task has no id to fall back on
+			err := handler.ProcessTask(context.Background(), asynq.NewTask(TaskSendVerifyEmail, []byte(tc.payload)))
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedID, id)
+		})
+	}
+}
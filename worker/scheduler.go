@@ -0,0 +1,140 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antimatter007/go-backend/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// PeriodicTask is a task that the scheduler enqueues on a cron schedule.
+// An empty CronSpec disables the task.
+type PeriodicTask struct {
+	CronSpec string
+	Task     *asynq.Task
+	Opts     []asynq.Option
+}
+
+type TaskScheduler interface {
+	Start() error
+	Shutdown()
+}
+
+type RedisTaskScheduler struct {
+	scheduler *asynq.Scheduler
+	tasks     []PeriodicTask
+}
+
+func NewRedisTaskScheduler(redisOpt asynq.RedisClientOpt, tasks []PeriodicTask) TaskScheduler {
+	scheduler := asynq.NewScheduler(
+		redisOpt,
+		&asynq.SchedulerOpts{
+			Logger: NewLogger(),
+			PostEnqueueFunc: func(info *asynq.TaskInfo, err error) {
+				if err != nil {
+					log.Error().Err(err).Msg("failed to enqueue periodic task")
+					return
+				}
+				log.Info().Str("type", info.Type).Str("queue", info.Queue).Msg("enqueued periodic task")
+			},
+		},
+	)
+
+	return &RedisTaskScheduler{
+		scheduler: scheduler,
+		tasks:     tasks,
+	}
+}
+
+func (scheduler *RedisTaskScheduler) Start() error {
+	for _, periodicTask := range scheduler.tasks {
+		if periodicTask.CronSpec == "" {
+			log.Info().Str("type", periodicTask.Task.Type()).Msg("periodic task disabled")
+			continue
+		}
+
+		entryID, err := scheduler.scheduler.Register(periodicTask.CronSpec, periodicTask.Task, periodicTask.Opts...)
+		if err != nil {
+			return fmt.Errorf("failed to register periodic task %s: %w", periodicTask.Task.Type(), err)
+		}
+
+		log.Info().Str("type", periodicTask.Task.Type()).Str("cron", periodicTask.CronSpec).
+			Str("entry_id", entryID).Msg("registered periodic task")
+	}
+
+	return scheduler.scheduler.Start()
+}
+
+func (scheduler *RedisTaskScheduler) Shutdown() {
+	scheduler.scheduler.Shutdown()
+}
+
+// NewPeriodicTasks returns all periodic tasks of the application, configured from config.
+func NewPeriodicTasks(config util.Config) ([]PeriodicTask, error) {
+	cleanupSessions, err := newCleanupTask(TaskCleanupExpiredSessions, config.CleanupBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanupVerifyEmails, err := newCleanupTask(TaskCleanupExpiredVerifyEmails, config.CleanupBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	executeScheduledTransfers, err := newExecuteScheduledTransfersTask(config.ScheduledTransfersBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	expireHolds, err := newExpireHoldsTask(config.HoldsExpiryBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	reconcileBalances, err := newReconcileBalancesTask(config.ReconciliationBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	accrueInterest, err := newAccrueInterestTask(config.InterestBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []PeriodicTask{
+		{
+			CronSpec: config.CleanupSessionsCronSpec,
+			Task:     cleanupSessions,
+			Opts:     []asynq.Option{asynq.Queue(QueueDefault), asynq.MaxRetry(3)},
+		},
+		{
+			CronSpec: config.CleanupVerifyEmailsCronSpec,
+			Task:     cleanupVerifyEmails,
+			Opts:     []asynq.Option{asynq.Queue(QueueDefault), asynq.MaxRetry(3)},
+		},
+		{
+			CronSpec: config.ScheduledTransfersCronSpec,
+			Task:     executeScheduledTransfers,
+			Opts:     []asynq.Option{asynq.Queue(QueueCritical), asynq.MaxRetry(3)},
+		},
+		{
+			CronSpec: config.HoldsExpiryCronSpec,
+			Task:     expireHolds,
+			Opts:     []asynq.Option{asynq.Queue(QueueDefault), asynq.MaxRetry(3)},
+		},
+		{
+			CronSpec: config.ReconciliationCronSpec,
+			Task:     reconcileBalances,
+			Opts:     []asynq.Option{asynq.Queue(QueueDefault), asynq.MaxRetry(3), asynq.Unique(time.Hour)},
+		},
+		{
+			CronSpec: config.InterestCronSpec,
+			Task:     accrueInterest,
+			Opts:     []asynq.Option{asynq.Queue(QueueCritical), asynq.MaxRetry(5), asynq.Unique(time.Hour)},
+		},
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const TaskAccrueInterest = "task:accrue_interest"
+
+type PayloadAccrueInterest struct {
+	BatchSize int32 `json:"batch_size"`
+}
+
+func newAccrueInterestTask(batchSize int32) (*asynq.Task, error) {
+	jsonPayload, err := json.Marshal(&PayloadAccrueInterest{BatchSize: batchSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	return asynq.NewTask(TaskAccrueInterest, jsonPayload), nil
+}
+
+// ProcessTaskAccrueInterest accrues the interest of the previous day on every active account whose product pays interest,
+// then pays out the interest accrued in earlier months. Accruals are unique per account and day,
+// so a retried or repeated run never accrues a day twice. Days are calendar days in UTC.
+func (processor *RedisTaskProcessor) ProcessTaskAccrueInterest(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadAccrueInterest
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+	if payload.BatchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d: %w", payload.BatchSize, asynq.SkipRetry)
+	}
+
+	accrualDate, monthStart := interestDates(time.Now())
+
+	accounts, err := processor.accrueInterest(ctx, payload.BatchSize, accrualDate)
+	if err != nil {
+		return err
+	}
+
+	posted, err := processor.postInterest(ctx, payload.BatchSize, monthStart)
+	if err != nil {
+		return err
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Time("accrual_date", accrualDate.Time).
+		Int64("accounts", accounts).Int64("posted", posted).Msg("processed task")
+	return nil
+}
+
+// interestDates returns the day before now, which is accrued, and the first day of the month of now,
+// before which accruals are paid out. On the first of a month the last day of the previous month
+// is accrued before the month is paid out.
+func interestDates(now time.Time) (accrualDate pgtype.Date, monthStart pgtype.Date) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return pgtype.Date{Time: day, Valid: true}, pgtype.Date{Time: month, Valid: true}
+}
+
+// accrueInterest accrues a day of interest batch by batch in account id order and returns the number of accounts checked
+func (processor *RedisTaskProcessor) accrueInterest(ctx context.Context, batchSize int32, accrualDate pgtype.Date) (int64, error) {
+	var afterID, total int64
+	for {
+		batch, err := processor.store.AccrueInterest(ctx, db.AccrueInterestParams{
+			AfterID:     afterID,
+			Limit:       batchSize,
+			AccrualDate: accrualDate,
+		})
+		if err != nil {
+			return total, fmt.Errorf("failed to accrue interest after account %d: %w", afterID, err)
+		}
+
+		total += int64(batch.Accounts)
+		if batch.Accounts < batchSize {
+			return total, nil
+		}
+		afterID = batch.LastAccountID
+
+		if err := ctx.Err(); err != nil {
+			return total, fmt.Errorf("interest accrual interrupted after %d accounts: %w", total, err)
+		}
+	}
+}
+
+// postInterest pays out the interest accrued before monthStart on every account and returns the number of accounts posted.
+// Each account is posted in its own transaction, which marks its accruals as posted.
+func (processor *RedisTaskProcessor) postInterest(ctx context.Context, batchSize int32, monthStart pgtype.Date) (int64, error) {
+	var total int64
+	for {
+		accountIDs, err := processor.store.ListUnpostedInterestAccounts(ctx, db.ListUnpostedInterestAccountsParams{
+			Before: monthStart,
+			Limit:  batchSize,
+		})
+		if err != nil {
+			return total, fmt.Errorf("failed to list accounts with unposted interest: %w", err)
+		}
+
+		for _, accountID := range accountIDs {
+			result, err := processor.store.PostInterestTx(ctx, db.PostInterestTxParams{
+				AccountID: accountID,
+				Before:    monthStart,
+			})
+			if err != nil {
+				return total, fmt.Errorf("failed to post interest of account %d: %w", accountID, err)
+			}
+
+			total++
+			interestPostings.Inc()
+			interestPaid.WithLabelValues(result.Account.Currency).Add(float64(result.Amount))
+		}
+
+		if len(accountIDs) < int(batchSize) {
+			return total, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return total, fmt.Errorf("interest posting interrupted after %d accounts: %w", total, err)
+		}
+	}
+}
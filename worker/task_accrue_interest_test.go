@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAccrueInterestTask(t *testing.T, batchSize int32) *asynq.Task {
+	payload, err := json.Marshal(&PayloadAccrueInterest{BatchSize: batchSize})
+	require.NoError(t, err)
+	return asynq.NewTask(TaskAccrueInterest, payload)
+}
+
+func TestInterestDates(t *testing.T) {
+	accrualDate, monthStart := interestDates(time.Date(2024, time.March, 1, 1, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), accrualDate.Time)
+	require.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), monthStart.Time)
+
+	accrualDate, monthStart = interestDates(time.Date(2024, time.March, 15, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600)))
+	require.Equal(t, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), accrualDate.Time)
+	require.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), monthStart.Time)
+}
+
+func TestProcessTaskAccrueInterest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	accrualDate, monthStart := interestDates(time.Now())
+
+	gomock.InOrder(
+		store.EXPECT().
+			AccrueInterest(gomock.Any(), gomock.Eq(db.AccrueInterestParams{AfterID: 0, Limit: 2, AccrualDate: accrualDate})).
+			Times(1).
+			Return(db.AccrueInterestRow{Accounts: 2, LastAccountID: 5}, nil),
+		store.EXPECT().
+			AccrueInterest(gomock.Any(), gomock.Eq(db.AccrueInterestParams{AfterID: 5, Limit: 2, AccrualDate: accrualDate})).
+			Times(1).
+			Return(db.AccrueInterestRow{Accounts: 1, LastAccountID: 9}, nil),
+		store.EXPECT().
+			ListUnpostedInterestAccounts(gomock.Any(), gomock.Eq(db.ListUnpostedInterestAccountsParams{Before: monthStart, Limit: 2})).
+			Times(1).
+			Return([]int64{5}, nil),
+		store.EXPECT().
+			PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{AccountID: 5, Before: monthStart})).
+			Times(1).
+			Return(db.PostInterestTxResult{Account: db.Account{ID: 5, Currency: util.USD}, Amount: 12}, nil),
+	)
+
+	processor := &RedisTaskProcessor{store: store}
+	err := processor.ProcessTaskAccrueInterest(context.Background(), newTestAccrueInterestTask(t, 2))
+	require.NoError(t, err)
+}
+
+func TestProcessTaskAccrueInterestError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		AccrueInterest(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.AccrueInterestRow{}, nil)
+	store.EXPECT().
+		ListUnpostedInterestAccounts(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]int64{5, 7}, nil)
+
+	// the task stops at the first failed posting, and a retry picks up the accounts left
+	store.EXPECT().
+		PostInterestTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.PostInterestTxResult{}, errors.New("connection refused"))
+
+	processor := &RedisTaskProcessor{store: store}
+	err := processor.ProcessTaskAccrueInterest(context.Background(), newTestAccrueInterestTask(t, 10))
+	require.ErrorContains(t, err, "account 5")
+
+	err = processor.ProcessTaskAccrueInterest(context.Background(), newTestAccrueInterestTask(t, 0))
+	require.ErrorIs(t, err, asynq.SkipRetry)
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskCleanupExpiredSessions     = "task:cleanup_expired_sessions"
+	TaskCleanupExpiredVerifyEmails = "task:cleanup_expired_verify_emails"
+)
+
+type PayloadCleanup struct {
+	BatchSize int32 `json:"batch_size"`
+}
+
+func newCleanupTask(taskType string, batchSize int32) (*asynq.Task, error) {
+	jsonPayload, err := json.Marshal(&PayloadCleanup{BatchSize: batchSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	return asynq.NewTask(taskType, jsonPayload), nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskCleanupExpiredSessions(ctx context.Context, task *asynq.Task) error {
+	return processCleanupTask(ctx, task, "sessions", processor.store.DeleteExpiredSessions)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskCleanupExpiredVerifyEmails(ctx context.Context, task *asynq.Task) error {
+	return processCleanupTask(ctx, task, "verify_emails", processor.store.DeleteExpiredVerifyEmails)
+}
+
+// processCleanupTask keeps deleting batches of rows until a batch comes back short,
+// so that a large backlog never ends up in a single long-running statement.
+func processCleanupTask(
+	ctx context.Context,
+	task *asynq.Task,
+	table string,
+	deleteBatch func(ctx context.Context, limit int32) (int64, error),
+) error {
+	var payload PayloadCleanup
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+	if payload.BatchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d: %w", payload.BatchSize, asynq.SkipRetry)
+	}
+
+	var total int64
+	for {
+		deleted, err := deleteBatch(ctx, payload.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to delete expired %s: %w", table, err)
+		}
+
+		total += deleted
+		cleanupDeletedRows.WithLabelValues(table).Add(float64(deleted))
+
+		if deleted < int64(payload.BatchSize) {
+			break
+		}
+
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("cleanup of %s interrupted after %d rows: %w", table, total, err)
+		}
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Str("table", table).
+		Int64("deleted", total).Msg("processed task")
+	return nil
+}
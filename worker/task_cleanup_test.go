@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCleanupTask(t *testing.T, batchSize int32) *asynq.Task {
+	payload, err := json.Marshal(&PayloadCleanup{BatchSize: batchSize})
+	require.NoError(t, err)
+	return asynq.NewTask(TaskCleanupExpiredSessions, payload)
+}
+
+func TestProcessCleanupTaskDeletesInBatches(t *testing.T) {
+	remaining := int64(25)
+	calls := 0
+
+	deleteBatch := func(ctx context.Context, limit int32) (int64, error) {
+		calls++
+		deleted := remaining
+		if deleted > int64(limit) {
+			deleted = int64(limit)
+		}
+		remaining -= deleted
+		return deleted, nil
+	}
+
+	err := processCleanupTask(context.Background(), newTestCleanupTask(t, 10), "sessions", deleteBatch)
+	require.NoError(t, err)
+	require.Zero(t, remaining)
+	require.Equal(t, 3, calls)
+}
+
+func TestProcessCleanupTaskError(t *testing.T) {
+	deleteBatch := func(ctx context.Context, limit int32) (int64, error) {
+		return 0, errors.New("connection refused")
+	}
+
+	err := processCleanupTask(context.Background(), newTestCleanupTask(t, 10), "sessions", deleteBatch)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, asynq.SkipRetry))
+}
+
+func TestProcessCleanupTaskInvalidPayload(t *testing.T) {
+	deleteBatch := func(ctx context.Context, limit int32) (int64, error) {
+		t.Fatal("delete must not be called")
+		return 0, nil
+	}
+
+	task := asynq.NewTask(TaskCleanupExpiredSessions, []byte("invalid"))
+	err := processCleanupTask(context.Background(), task, "sessions", deleteBatch)
+	require.ErrorIs(t, err, asynq.SkipRetry)
+
+	err = processCleanupTask(context.Background(), newTestCleanupTask(t, 0), "sessions", deleteBatch)
+	require.ErrorIs(t, err, asynq.SkipRetry)
+}
@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/antimatter007/go-backend/notification"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskDeliverNotification = "task:deliver_notification"
+
+// notificationMaxRetry matches the retries of the transfer notification task
+const notificationMaxRetry = 10
+
+// notificationRetention keeps delivered notifications in asynq, so that their task id stays taken
+// and a retried transfer notification task cannot enqueue them again
+const notificationRetention = 24 * time.Hour
+
+type PayloadDeliverNotification struct {
+	TransferID   int64                     `json:"transfer_id"`
+	Channel      string                    `json:"channel"`
+	Notification notification.Notification `json:"notification"`
+	// RequestID is carried over from the transfer notification task, set by the distributor
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// notificationTaskID identifies the delivery of a transfer notification to a recipient through a channel
+func notificationTaskID(transferID int64, n notification.Notification, channel string) string {
+	return fmt.Sprintf("notification:%d:%s:%s:%s", transferID, n.Event, n.Username, channel)
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskDeliverNotification(
+	ctx context.Context,
+	payload *PayloadDeliverNotification,
+	opts ...asynq.Option,
+) error {
+	payload.RequestID = requestid.FromContext(ctx)
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskDeliverNotification, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskDeliverNotification sends a notification through a single channel
+func (processor *RedisTaskProcessor) ProcessTaskDeliverNotification(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadDeliverNotification
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+	if !notification.IsSupportedChannel(payload.Channel) {
+		return fmt.Errorf("unsupported channel %q: %w", payload.Channel, asynq.SkipRetry)
+	}
+
+	err := processor.notifier.Send(ctx, payload.Channel, payload.Notification)
+	if err != nil {
+		return err
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Int64("transfer_id", payload.TransferID).
+		Str("channel", payload.Channel).Str("username", payload.Notification.Username).Msg("processed task")
+	return nil
+}
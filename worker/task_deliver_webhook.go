@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/antimatter007/go-backend/webhook"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const TaskDeliverWebhook = "task:deliver_webhook"
+
+type PayloadDeliverWebhook struct {
+	DeliveryID int64 `json:"delivery_id"`
+	// RequestID is carried over from the event being delivered, set by the distributor
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskDeliverWebhook(
+	ctx context.Context,
+	payload *PayloadDeliverWebhook,
+	opts ...asynq.Option,
+) error {
+	payload.RequestID = requestid.FromContext(ctx)
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskDeliverWebhook, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskDeliverWebhook sends one delivery and records the outcome of the attempt.
+// A failed attempt keeps the delivery pending until asynq runs out of retries.
+func (processor *RedisTaskProcessor) ProcessTaskDeliverWebhook(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadDeliverWebhook
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	delivery, err := processor.store.GetWebhookDelivery(ctx, payload.DeliveryID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("webhook delivery doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery.Status != webhook.StatusPending {
+		// the delivery was enqueued again after it was sent
+		return fmt.Errorf("webhook delivery is %s: %w", delivery.Status, asynq.SkipRetry)
+	}
+
+	endpoint, err := processor.store.GetWebhookEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("webhook endpoint doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	if !endpoint.IsActive {
+		_, err = processor.store.UpdateWebhookDelivery(ctx, db.UpdateWebhookDeliveryParams{
+			ID:        delivery.ID,
+			Status:    webhook.StatusFailed,
+			LastError: "endpoint is disabled",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update webhook delivery: %w", err)
+		}
+		return fmt.Errorf("webhook endpoint is disabled: %w", asynq.SkipRetry)
+	}
+
+	statusCode, sendErr := processor.webhookClient.Send(ctx, webhook.Request{
+		DeliveryID: delivery.ID,
+		Event:      delivery.Event,
+		URL:        endpoint.Url,
+		Secret:     endpoint.Secret,
+		Payload:    delivery.Payload,
+	})
+
+	arg := db.UpdateWebhookDeliveryParams{
+		ID:             delivery.ID,
+		Status:         webhook.StatusSucceeded,
+		ResponseStatus: int32(statusCode),
+	}
+	if sendErr != nil {
+		arg.Status = webhook.StatusPending
+		arg.LastError = sendErr.Error()
+		if isLastAttempt(ctx) {
+			arg.Status = webhook.StatusFailed
+		}
+	} else {
+		arg.DeliveredAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	}
+
+	_, err = processor.store.UpdateWebhookDelivery(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	if sendErr != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", sendErr)
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Int64("delivery_id", delivery.ID).
+		Int64("endpoint_id", endpoint.ID).Int("status_code", statusCode).Msg("processed task")
+	return nil
+}
@@ -0,0 +1,288 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/webhook"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDistributor keeps the tasks instead of sending them to redis
+type recordingDistributor struct {
+	TaskDistributor
+	deliveries      []int64
+	deliveryTaskIDs []string
+	deliveryErr     error
+	notifications   []int64
+	events          []string
+}
+
+func (distributor *recordingDistributor) DistributeTaskSendTransferNotification(
+	ctx context.Context,
+	payload *PayloadSendTransferNotification,
+	opts ...asynq.Option,
+) error {
+	distributor.notifications = append(distributor.notifications, payload.TransferID)
+	return nil
+}
+
+func (distributor *recordingDistributor) DistributeTaskPublishWebhookEvent(
+	ctx context.Context,
+	payload *PayloadPublishWebhookEvent,
+	opts ...asynq.Option,
+) error {
+	distributor.events = append(distributor.events, payload.Event)
+	return nil
+}
+
+func (distributor *recordingDistributor) DistributeTaskDeliverWebhook(
+	ctx context.Context,
+	payload *PayloadDeliverWebhook,
+	opts ...asynq.Option,
+) error {
+	distributor.deliveries = append(distributor.deliveries, payload.DeliveryID)
+	for _, opt := range opts {
+		if opt.Type() == asynq.TaskIDOpt {
+			distributor.deliveryTaskIDs = append(distributor.deliveryTaskIDs, opt.Value().(string))
+		}
+	}
+	return distributor.deliveryErr
+}
+
+func newTestDeliverWebhookTask(t *testing.T, deliveryID int64) *asynq.Task {
+	payload, err := json.Marshal(&PayloadDeliverWebhook{DeliveryID: deliveryID})
+	require.NoError(t, err)
+	return asynq.NewTask(TaskDeliverWebhook, payload)
+}
+
+func randomWebhookEndpoint(url string) db.WebhookEndpoint {
+	return db.WebhookEndpoint{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    util.RandomOwner(),
+		Url:      url,
+		Secret:   util.RandomString(32),
+		Events:   []string{webhook.EventTransferCreated},
+		IsActive: true,
+	}
+}
+
+func randomWebhookDelivery(endpoint db.WebhookEndpoint) db.WebhookDelivery {
+	return db.WebhookDelivery{
+		ID:         util.RandomInt(1, 1000),
+		EndpointID: endpoint.ID,
+		Event:      webhook.EventTransferCreated,
+		Payload:    []byte(`{"id":"1","event":"transfer.created"}`),
+		Status:     webhook.StatusPending,
+	}
+}
+
+func TestProcessTaskDeliverWebhook(t *testing.T) {
+	var receivedBody []byte
+	var receivedSignature string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = io.ReadAll(r.Body)
+		receivedSignature = r.Header.Get(webhook.SignatureHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	endpoint := randomWebhookEndpoint(receiver.URL)
+	delivery := randomWebhookDelivery(endpoint)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+	store.EXPECT().
+		UpdateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.UpdateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+			require.Equal(t, delivery.ID, arg.ID)
+			require.Equal(t, webhook.StatusSucceeded, arg.Status)
+			require.Equal(t, int32(http.StatusOK), arg.ResponseStatus)
+			require.Empty(t, arg.LastError)
+			require.True(t, arg.DeliveredAt.Valid)
+			return delivery, nil
+		})
+
+	processor := &RedisTaskProcessor{
+		store:         store,
+		webhookClient: webhook.NewHTTPClient(time.Second, true),
+	}
+
+	err := processor.ProcessTaskDeliverWebhook(context.Background(), newTestDeliverWebhookTask(t, delivery.ID))
+	require.NoError(t, err)
+
+	require.Equal(t, delivery.Payload, receivedBody)
+	err = webhook.Verify(endpoint.Secret, receivedSignature, receivedBody, time.Minute, time.Now())
+	require.NoError(t, err)
+}
+
+func TestProcessTaskDeliverWebhookReceiverError(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	endpoint := randomWebhookEndpoint(receiver.URL)
+	delivery := randomWebhookDelivery(endpoint)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+	store.EXPECT().
+		UpdateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.UpdateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+			// Without retry metadata in the context the attempt counts as the last one
+			require.Equal(t, webhook.StatusFailed, arg.Status)
+			require.Equal(t, int32(http.StatusServiceUnavailable), arg.ResponseStatus)
+			require.NotEmpty(t, arg.LastError)
+			require.False(t, arg.DeliveredAt.Valid)
+			return delivery, nil
+		})
+
+	processor := &RedisTaskProcessor{
+		store:         store,
+		webhookClient: webhook.NewHTTPClient(time.Second, true),
+	}
+
+	err := processor.ProcessTaskDeliverWebhook(context.Background(), newTestDeliverWebhookTask(t, delivery.ID))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, asynq.SkipRetry)
+}
+
+func TestProcessTaskDeliverWebhookDisabledEndpoint(t *testing.T) {
+	endpoint := randomWebhookEndpoint("http://localhost:1")
+	endpoint.IsActive = false
+	delivery := randomWebhookDelivery(endpoint)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+	store.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).Times(1).Return(delivery, nil)
+
+	processor := &RedisTaskProcessor{
+		store:         store,
+		webhookClient: webhook.NewHTTPClient(time.Second, true),
+	}
+
+	err := processor.ProcessTaskDeliverWebhook(context.Background(), newTestDeliverWebhookTask(t, delivery.ID))
+	require.ErrorIs(t, err, asynq.SkipRetry)
+}
+
+func TestProcessTaskDeliverWebhookAlreadySent(t *testing.T) {
+	endpoint := randomWebhookEndpoint("http://localhost:1")
+	delivery := randomWebhookDelivery(endpoint)
+	delivery.Status = webhook.StatusSucceeded
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+
+	processor := &RedisTaskProcessor{
+		store:         store,
+		webhookClient: webhook.NewHTTPClient(time.Second, true),
+	}
+
+	err := processor.ProcessTaskDeliverWebhook(context.Background(), newTestDeliverWebhookTask(t, delivery.ID))
+	require.ErrorIs(t, err, asynq.SkipRetry)
+}
+
+func TestProcessTaskPublishWebhookEvent(t *testing.T) {
+	username := util.RandomOwner()
+	endpoints := []db.WebhookEndpoint{
+		randomWebhookEndpoint("https://example.com/a"),
+		randomWebhookEndpoint("https://example.com/b"),
+	}
+
+	payload, err := NewPayloadPublishWebhookEvent(webhook.EventTransferCreated, []string{username, username}, db.Transfer{ID: 1})
+	require.NoError(t, err)
+	jsonPayload, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	distributor := &recordingDistributor{}
+
+	store.EXPECT().
+		ListWebhookEndpointsForEvent(gomock.Any(), gomock.Eq(db.ListWebhookEndpointsForEventParams{
+			Owner: username,
+			Event: webhook.EventTransferCreated,
+		})).
+		Times(1).
+		Return(endpoints, nil)
+	store.EXPECT().
+		CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(len(endpoints)).
+		DoAndReturn(func(ctx context.Context, arg db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+			var envelope webhook.Envelope
+			require.NoError(t, json.Unmarshal(arg.Payload, &envelope))
+			require.Equal(t, payload.EventID, envelope.ID)
+			require.Equal(t, payload.EventID, arg.EventID)
+			require.Equal(t, webhook.EventTransferCreated, envelope.Event)
+			return db.WebhookDelivery{ID: arg.EndpointID, EndpointID: arg.EndpointID}, nil
+		})
+
+	processor := &RedisTaskProcessor{
+		store:       store,
+		distributor: distributor,
+	}
+
+	err = processor.ProcessTaskPublishWebhookEvent(context.Background(), asynq.NewTask(TaskPublishWebhookEvent, jsonPayload))
+	require.NoError(t, err)
+	require.Equal(t, []int64{endpoints[0].ID, endpoints[1].ID}, distributor.deliveries)
+	require.Equal(t, []string{
+		webhookDeliveryTaskID(endpoints[0].ID),
+		webhookDeliveryTaskID(endpoints[1].ID),
+	}, distributor.deliveryTaskIDs)
+}
+
+func TestProcessTaskPublishWebhookEventRetry(t *testing.T) {
+	username := util.RandomOwner()
+	endpoint := randomWebhookEndpoint("https://example.com/a")
+	delivery := randomWebhookDelivery(endpoint)
+
+	payload, err := NewPayloadPublishWebhookEvent(webhook.EventTransferCreated, []string{username}, db.Transfer{ID: 1})
+	require.NoError(t, err)
+	jsonPayload, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListWebhookEndpointsForEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.WebhookEndpoint{endpoint}, nil)
+	// the delivery recorded by the failed attempt is returned again
+	store.EXPECT().
+		CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(delivery, nil)
+
+	// the earlier attempt enqueued the delivery before failing
+	distributor := &recordingDistributor{deliveryErr: asynq.ErrTaskIDConflict}
+	processor := &RedisTaskProcessor{
+		store:       store,
+		distributor: distributor,
+	}
+
+	err = processor.ProcessTaskPublishWebhookEvent(context.Background(), asynq.NewTask(TaskPublishWebhookEvent, jsonPayload))
+	require.NoError(t, err)
+	require.Equal(t, []string{webhookDeliveryTaskID(delivery.ID)}, distributor.deliveryTaskIDs)
+}
@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/antimatter007/go-backend/webhook"
+	"github.com/hibiken/asynq"
+)
+
+const TaskExecuteScheduledTransfers = "task:execute_scheduled_transfers"
+
+type PayloadExecuteScheduledTransfers struct {
+	BatchSize int32 `json:"batch_size"`
+}
+
+func newExecuteScheduledTransfersTask(batchSize int32) (*asynq.Task, error) {
+	jsonPayload, err := json.Marshal(&PayloadExecuteScheduledTransfers{BatchSize: batchSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	return asynq.NewTask(TaskExecuteScheduledTransfers, jsonPayload), nil
+}
+
+// ProcessTaskExecuteScheduledTransfers executes every scheduled transfer that is due.
+// Each execution moves the schedule past the current time, so the loop ends once a batch comes back short.
+func (processor *RedisTaskProcessor) ProcessTaskExecuteScheduledTransfers(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExecuteScheduledTransfers
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+	if payload.BatchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d: %w", payload.BatchSize, asynq.SkipRetry)
+	}
+
+	var total int
+	for {
+		scheduledTransfers, err := processor.store.ListDueScheduledTransfers(ctx, payload.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list due scheduled transfers: %w", err)
+		}
+
+		for _, scheduledTransfer := range scheduledTransfers {
+			if err := processor.executeScheduledTransfer(ctx, scheduledTransfer); err != nil {
+				return err
+			}
+		}
+
+		total += len(scheduledTransfers)
+		if len(scheduledTransfers) < int(payload.BatchSize) {
+			break
+		}
+
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("execution of scheduled transfers interrupted after %d runs: %w", total, err)
+		}
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Int("executed", total).Msg("processed task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) executeScheduledTransfer(ctx context.Context, scheduledTransfer db.ScheduledTransfer) error {
+	result, err := processor.store.ExecuteScheduledTransferTx(ctx, db.ExecuteScheduledTransferTxParams{
+		ID:           scheduledTransfer.ID,
+		ScheduledFor: scheduledTransfer.NextRunAt.Time,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrScheduledTransferNotDue) {
+			// another worker executed the run, or the owner paused the schedule in the meantime
+			return nil
+		}
+		return fmt.Errorf("failed to execute scheduled transfer %d: %w", scheduledTransfer.ID, err)
+	}
+
+	scheduledTransferRuns.WithLabelValues(result.Run.Status).Inc()
+
+	if result.Run.Status != db.ScheduledTransferRunSucceeded {
+		requestid.Logger(ctx).Warn().Int64("scheduled_transfer_id", scheduledTransfer.ID).
+			Str("error", result.Run.Error).Msg("scheduled transfer run failed")
+		return nil
+	}
+
+	processor.announceTransfer(ctx, result.Transfer)
+	return nil
+}
+
+// announceTransfer enqueues the notifications and webhook event of a transfer made by the worker.
+// The transfer is already committed, so a failure here is only logged.
+func (processor *RedisTaskProcessor) announceTransfer(ctx context.Context, result db.TransferTxResult) {
+	err := processor.distributor.DistributeTaskSendTransferNotification(ctx,
+		&PayloadSendTransferNotification{TransferID: result.Transfer.ID},
+		asynq.MaxRetry(10),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		requestid.Logger(ctx).Error().Err(err).Int64("transfer_id", result.Transfer.ID).Msg("failed to enqueue transfer notification")
+	}
+
+	usernames := []string{result.FromAccount.Owner, result.ToAccount.Owner}
+	webhookPayload, err := NewPayloadPublishWebhookEvent(webhook.EventTransferCreated, usernames, result.Transfer)
+	if err == nil {
+		err = processor.distributor.DistributeTaskPublishWebhookEvent(ctx, webhookPayload,
+			asynq.MaxRetry(10),
+			asynq.Queue(QueueDefault),
+		)
+	}
+	if err != nil {
+		requestid.Logger(ctx).Error().Err(err).Int64("transfer_id", result.Transfer.ID).Msg("failed to enqueue webhook event")
+	}
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/antimatter007/go-backend/webhook"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExecuteScheduledTransfersTask(t *testing.T, batchSize int32) *asynq.Task {
+	payload, err := json.Marshal(&PayloadExecuteScheduledTransfers{BatchSize: batchSize})
+	require.NoError(t, err)
+	return asynq.NewTask(TaskExecuteScheduledTransfers, payload)
+}
+
+func randomDueScheduledTransfer() db.ScheduledTransfer {
+	nextRunAt := time.Now().Add(-time.Minute)
+	return db.ScheduledTransfer{
+		ID:            util.RandomInt(1, 1000),
+		Owner:         util.RandomOwner(),
+		FromAccountID: util.RandomInt(1, 1000),
+		ToAccountID:   util.RandomInt(1, 1000),
+		Amount:        util.RandomMoney(),
+		Recurrence:    util.RecurrenceMonthly,
+		Status:        db.ScheduledTransferActive,
+		FirstRunAt:    nextRunAt,
+		NextRunAt:     pgtype.Timestamptz{Time: nextRunAt, Valid: true},
+	}
+}
+
+func TestProcessTaskExecuteScheduledTransfers(t *testing.T) {
+	succeeded := randomDueScheduledTransfer()
+	failed := randomDueScheduledTransfer()
+	notDue := randomDueScheduledTransfer()
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	distributor := &recordingDistributor{}
+
+	gomock.InOrder(
+		store.EXPECT().
+			ListDueScheduledTransfers(gomock.Any(), gomock.Eq(int32(3))).
+			Times(1).
+			Return([]db.ScheduledTransfer{succeeded, failed, notDue}, nil),
+		store.EXPECT().
+			ListDueScheduledTransfers(gomock.Any(), gomock.Eq(int32(3))).
+			Times(1).
+			Return([]db.ScheduledTransfer{}, nil),
+	)
+
+	store.EXPECT().
+		ExecuteScheduledTransferTx(gomock.Any(), gomock.Eq(db.ExecuteScheduledTransferTxParams{
+			ID:           succeeded.ID,
+			ScheduledFor: succeeded.NextRunAt.Time,
+		})).
+		Times(1).
+		Return(db.ExecuteScheduledTransferTxResult{
+			Run:      db.ScheduledTransferRun{Status: db.ScheduledTransferRunSucceeded},
+			Transfer: db.TransferTxResult{Transfer: db.Transfer{ID: 7}},
+		}, nil)
+	store.EXPECT().
+		ExecuteScheduledTransferTx(gomock.Any(), gomock.Eq(db.ExecuteScheduledTransferTxParams{
+			ID:           failed.ID,
+			ScheduledFor: failed.NextRunAt.Time,
+		})).
+		Times(1).
+		Return(db.ExecuteScheduledTransferTxResult{
+			Run: db.ScheduledTransferRun{Status: db.ScheduledTransferRunFailed, Error: "insufficient funds"},
+		}, nil)
+	store.EXPECT().
+		ExecuteScheduledTransferTx(gomock.Any(), gomock.Eq(db.ExecuteScheduledTransferTxParams{
+			ID:           notDue.ID,
+			ScheduledFor: notDue.NextRunAt.Time,
+		})).
+		Times(1).
+		Return(db.ExecuteScheduledTransferTxResult{}, db.ErrScheduledTransferNotDue)
+
+	processor := &RedisTaskProcessor{
+		store:       store,
+		distributor: distributor,
+	}
+
+	err := processor.ProcessTaskExecuteScheduledTransfers(context.Background(), newTestExecuteScheduledTransfersTask(t, 3))
+	require.NoError(t, err)
+
+	// only the succeeded run is announced
+	require.Equal(t, []int64{7}, distributor.notifications)
+	require.Equal(t, []string{webhook.EventTransferCreated}, distributor.events)
+}
+
+func TestProcessTaskExecuteScheduledTransfersError(t *testing.T) {
+	scheduledTransfer := randomDueScheduledTransfer()
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListDueScheduledTransfers(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.ScheduledTransfer{scheduledTransfer}, nil)
+	store.EXPECT().
+		ExecuteScheduledTransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.ExecuteScheduledTransferTxResult{}, errors.New("connection refused"))
+
+	processor := &RedisTaskProcessor{
+		store: store,
+	}
+
+	err := processor.ProcessTaskExecuteScheduledTransfers(context.Background(), newTestExecuteScheduledTransfersTask(t, 10))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, asynq.SkipRetry)
+}
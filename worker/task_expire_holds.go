@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskExpireHolds = "task:expire_holds"
+
+type PayloadExpireHolds struct {
+	BatchSize int32 `json:"batch_size"`
+}
+
+func newExpireHoldsTask(batchSize int32) (*asynq.Task, error) {
+	jsonPayload, err := json.Marshal(&PayloadExpireHolds{BatchSize: batchSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	return asynq.NewTask(TaskExpireHolds, jsonPayload), nil
+}
+
+// ProcessTaskExpireHolds marks active holds past their expiry as expired, batch by batch.
+// Expired holds already stop counting against the available balance, this only settles their status.
+func (processor *RedisTaskProcessor) ProcessTaskExpireHolds(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExpireHolds
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+	if payload.BatchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d: %w", payload.BatchSize, asynq.SkipRetry)
+	}
+
+	var total int64
+	for {
+		expired, err := processor.store.ExpireHolds(ctx, payload.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to expire holds: %w", err)
+		}
+
+		total += expired
+		expiredHolds.Add(float64(expired))
+
+		if expired < int64(payload.BatchSize) {
+			break
+		}
+
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("expiry of holds interrupted after %d holds: %w", total, err)
+		}
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Int64("expired", total).Msg("processed task")
+	return nil
+}
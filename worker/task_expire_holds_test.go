@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExpireHoldsTask(t *testing.T, batchSize int32) *asynq.Task {
+	payload, err := json.Marshal(&PayloadExpireHolds{BatchSize: batchSize})
+	require.NoError(t, err)
+	return asynq.NewTask(TaskExpireHolds, payload)
+}
+
+func TestProcessTaskExpireHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	gomock.InOrder(
+		store.EXPECT().ExpireHolds(gomock.Any(), gomock.Eq(int32(10))).Times(1).Return(int64(10), nil),
+		store.EXPECT().ExpireHolds(gomock.Any(), gomock.Eq(int32(10))).Times(1).Return(int64(4), nil),
+	)
+
+	processor := &RedisTaskProcessor{store: store}
+	err := processor.ProcessTaskExpireHolds(context.Background(), newTestExpireHoldsTask(t, 10))
+	require.NoError(t, err)
+}
+
+func TestProcessTaskExpireHoldsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ExpireHolds(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), errors.New("connection refused"))
+
+	processor := &RedisTaskProcessor{store: store}
+	err := processor.ProcessTaskExpireHolds(context.Background(), newTestExpireHoldsTask(t, 10))
+	require.Error(t, err)
+
+	err = processor.ProcessTaskExpireHolds(context.Background(), newTestExpireHoldsTask(t, 0))
+	require.ErrorIs(t, err, asynq.SkipRetry)
+}
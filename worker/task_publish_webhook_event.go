@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/antimatter007/go-backend/webhook"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskPublishWebhookEvent = "task:publish_webhook_event"
+
+// webhookMaxRetry gives an endpoint roughly half a day to recover,
+// given the exponential backoff between delivery attempts.
+const webhookMaxRetry = 12
+
+// webhookRetention keeps sent deliveries in asynq, so that their task id stays taken
+// and a retried publish task cannot enqueue them again
+const webhookRetention = 24 * time.Hour
+
+type PayloadPublishWebhookEvent struct {
+	EventID   string          `json:"event_id"`
+	Event     string          `json:"event"`
+	Usernames []string        `json:"usernames"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+	// RequestID is the id of the request that caused the event, set by the distributor
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// NewPayloadPublishWebhookEvent creates the payload to publish an event to the
+// webhook endpoints of the given users. The event ID is assigned here so that
+// retries of the publish task produce the same envelope.
+func NewPayloadPublishWebhookEvent(event string, usernames []string, data interface{}) (*PayloadPublishWebhookEvent, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	return &PayloadPublishWebhookEvent{
+		EventID:   uuid.NewString(),
+		Event:     event,
+		Usernames: usernames,
+		CreatedAt: time.Now(),
+		Data:      jsonData,
+	}, nil
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskPublishWebhookEvent(
+	ctx context.Context,
+	payload *PayloadPublishWebhookEvent,
+	opts ...asynq.Option,
+) error {
+	payload.RequestID = requestid.FromContext(ctx)
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskPublishWebhookEvent, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// webhookDeliveryTaskID identifies the task sending a delivery
+func webhookDeliveryTaskID(deliveryID int64) string {
+	return fmt.Sprintf("webhook_delivery:%d", deliveryID)
+}
+
+// ProcessTaskPublishWebhookEvent fans an event out to every active endpoint subscribed to it,
+// recording one delivery per endpoint and enqueuing a separate task to send each of them.
+// Retries of the task find the deliveries recorded by earlier attempts, by event id, and do not enqueue them twice.
+func (processor *RedisTaskProcessor) ProcessTaskPublishWebhookEvent(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadPublishWebhookEvent
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+	if !webhook.IsSupportedEvent(payload.Event) {
+		return fmt.Errorf("unsupported event %q: %w", payload.Event, asynq.SkipRetry)
+	}
+
+	envelope, err := json.Marshal(webhook.Envelope{
+		ID:        payload.EventID,
+		Event:     payload.Event,
+		CreatedAt: payload.CreatedAt,
+		Data:      payload.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %w", asynq.SkipRetry)
+	}
+
+	deliveries := 0
+	for _, username := range uniqueStrings(payload.Usernames) {
+		endpoints, err := processor.store.ListWebhookEndpointsForEvent(ctx, db.ListWebhookEndpointsForEventParams{
+			Owner: username,
+			Event: payload.Event,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list webhook endpoints: %w", err)
+		}
+
+		for _, endpoint := range endpoints {
+			delivery, err := processor.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+				EndpointID: endpoint.ID,
+				EventID:    payload.EventID,
+				Event:      payload.Event,
+				Payload:    envelope,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create webhook delivery: %w", err)
+			}
+
+			err = processor.distributor.DistributeTaskDeliverWebhook(
+				ctx,
+				&PayloadDeliverWebhook{DeliveryID: delivery.ID},
+				asynq.TaskID(webhookDeliveryTaskID(delivery.ID)),
+				asynq.Retention(webhookRetention),
+				asynq.MaxRetry(webhookMaxRetry),
+				asynq.Queue(QueueDefault),
+			)
+			if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+				return fmt.Errorf("failed to distribute webhook delivery: %w", err)
+			}
+			deliveries++
+		}
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Str("event", payload.Event).
+		Str("event_id", payload.EventID).Int("deliveries", deliveries).Msg("processed task")
+	return nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
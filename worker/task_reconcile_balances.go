@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskReconcileBalances = "task:reconcile_balances"
+
+type PayloadReconcileBalances struct {
+	BatchSize int32 `json:"batch_size"`
+}
+
+func newReconcileBalancesTask(batchSize int32) (*asynq.Task, error) {
+	jsonPayload, err := json.Marshal(&PayloadReconcileBalances{BatchSize: batchSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	return asynq.NewTask(TaskReconcileBalances, jsonPayload), nil
+}
+
+// ProcessTaskReconcileBalances compares the stored balance of every account with the sum of its entries,
+// batch by batch in account id order, and records each mismatch in a new reconciliation report.
+// Each batch is read in a single statement, so transfers committed meanwhile never show up as drift.
+func (processor *RedisTaskProcessor) ProcessTaskReconcileBalances(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadReconcileBalances
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+	if payload.BatchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d: %w", payload.BatchSize, asynq.SkipRetry)
+	}
+
+	report, err := processor.store.CreateReconciliationReport(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create reconciliation report: %w", err)
+	}
+
+	arg := db.FinishReconciliationReportParams{
+		ID:     report.ID,
+		Status: db.ReconciliationCompleted,
+	}
+	err = processor.reconcileBalances(ctx, payload.BatchSize, &arg)
+	if err != nil {
+		arg.Status = db.ReconciliationFailed
+		arg.Error = err.Error()
+	}
+
+	// the report is closed even when the task was canceled
+	_, finishErr := processor.store.FinishReconciliationReport(context.WithoutCancel(ctx), arg)
+	if err != nil {
+		reconciliationRuns.WithLabelValues(db.ReconciliationFailed).Inc()
+		return fmt.Errorf("reconciliation %d failed after %d accounts: %w", report.ID, arg.AccountsChecked, err)
+	}
+	if finishErr != nil {
+		return fmt.Errorf("failed to finish reconciliation report %d: %w", report.ID, finishErr)
+	}
+
+	reconciliationRuns.WithLabelValues(db.ReconciliationCompleted).Inc()
+	reconciliationDiscrepancies.Set(float64(arg.DiscrepancyCount))
+
+	if arg.DiscrepancyCount > 0 {
+		requestid.Logger(ctx).Error().Str("type", task.Type()).Int64("report_id", report.ID).
+			Int64("accounts", arg.AccountsChecked).Int64("discrepancies", arg.DiscrepancyCount).
+			Msg("account balances drifted from the ledger")
+		return nil
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Int64("report_id", report.ID).
+		Int64("accounts", arg.AccountsChecked).Msg("processed task")
+	return nil
+}
+
+// reconcileBalances checks all accounts and counts them and their discrepancies in arg
+func (processor *RedisTaskProcessor) reconcileBalances(ctx context.Context, batchSize int32, arg *db.FinishReconciliationReportParams) error {
+	var afterID int64
+	for {
+		balances, err := processor.store.ListAccountLedgerBalances(ctx, db.ListAccountLedgerBalancesParams{
+			AfterID: afterID,
+			Limit:   batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list ledger balances: %w", err)
+		}
+
+		for _, balance := range balances {
+			if balance.Balance != balance.LedgerBalance {
+				_, err := processor.store.CreateReconciliationDiscrepancy(ctx, db.CreateReconciliationDiscrepancyParams{
+					ReportID:      arg.ID,
+					AccountID:     balance.ID,
+					StoredBalance: balance.Balance,
+					LedgerBalance: balance.LedgerBalance,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to record discrepancy of account %d: %w", balance.ID, err)
+				}
+
+				requestid.Logger(ctx).Warn().Int64("report_id", arg.ID).Int64("account_id", balance.ID).
+					Int64("stored_balance", balance.Balance).Int64("ledger_balance", balance.LedgerBalance).
+					Msg("account balance doesn't match its entries")
+				arg.DiscrepancyCount++
+			}
+			afterID = balance.ID
+		}
+
+		arg.AccountsChecked += int64(len(balances))
+		if len(balances) < int(batchSize) {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
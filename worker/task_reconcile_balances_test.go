@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReconcileBalancesTask(t *testing.T, batchSize int32) *asynq.Task {
+	payload, err := json.Marshal(&PayloadReconcileBalances{BatchSize: batchSize})
+	require.NoError(t, err)
+	return asynq.NewTask(TaskReconcileBalances, payload)
+}
+
+func TestProcessTaskReconcileBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	report := db.ReconciliationReport{ID: 7, Status: db.ReconciliationRunning}
+	store.EXPECT().CreateReconciliationReport(gomock.Any()).Times(1).Return(report, nil)
+
+	gomock.InOrder(
+		store.EXPECT().
+			ListAccountLedgerBalances(gomock.Any(), gomock.Eq(db.ListAccountLedgerBalancesParams{AfterID: 0, Limit: 2})).
+			Times(1).
+			Return([]db.ListAccountLedgerBalancesRow{
+				{ID: 1, Balance: 100, LedgerBalance: 100},
+				{ID: 3, Balance: 90, LedgerBalance: 80},
+			}, nil),
+		store.EXPECT().
+			ListAccountLedgerBalances(gomock.Any(), gomock.Eq(db.ListAccountLedgerBalancesParams{AfterID: 3, Limit: 2})).
+			Times(1).
+			Return([]db.ListAccountLedgerBalancesRow{
+				{ID: 4, Balance: 0, LedgerBalance: 0},
+			}, nil),
+	)
+
+	store.EXPECT().
+		CreateReconciliationDiscrepancy(gomock.Any(), gomock.Eq(db.CreateReconciliationDiscrepancyParams{
+			ReportID:      report.ID,
+			AccountID:     3,
+			StoredBalance: 90,
+			LedgerBalance: 80,
+		})).
+		Times(1).
+		Return(db.ReconciliationDiscrepancy{}, nil)
+
+	store.EXPECT().
+		FinishReconciliationReport(gomock.Any(), gomock.Eq(db.FinishReconciliationReportParams{
+			ID:               report.ID,
+			Status:           db.ReconciliationCompleted,
+			AccountsChecked:  3,
+			DiscrepancyCount: 1,
+		})).
+		Times(1).
+		Return(db.ReconciliationReport{}, nil)
+
+	processor := &RedisTaskProcessor{store: store}
+	err := processor.ProcessTaskReconcileBalances(context.Background(), newTestReconcileBalancesTask(t, 2))
+	require.NoError(t, err)
+}
+
+func TestProcessTaskReconcileBalancesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	report := db.ReconciliationReport{ID: 7, Status: db.ReconciliationRunning}
+	store.EXPECT().CreateReconciliationReport(gomock.Any()).Times(1).Return(report, nil)
+	store.EXPECT().
+		ListAccountLedgerBalances(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, errors.New("connection refused"))
+
+	// the report is closed as failed
+	store.EXPECT().
+		FinishReconciliationReport(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.FinishReconciliationReportParams) (db.ReconciliationReport, error) {
+			require.Equal(t, db.ReconciliationFailed, arg.Status)
+			require.Contains(t, arg.Error, "connection refused")
+			return db.ReconciliationReport{}, nil
+		})
+
+	processor := &RedisTaskProcessor{store: store}
+	err := processor.ProcessTaskReconcileBalances(context.Background(), newTestReconcileBalancesTask(t, 2))
+	require.Error(t, err)
+
+	err = processor.ProcessTaskReconcileBalances(context.Background(), newTestReconcileBalancesTask(t, 0))
+	require.ErrorIs(t, err, asynq.SkipRetry)
+}
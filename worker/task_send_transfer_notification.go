@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/notification"
+	"github.com/antimatter007/go-backend/requestid"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/hibiken/asynq"
+)
+
+const TaskSendTransferNotification = "task:send_transfer_notification"
+
+type PayloadSendTransferNotification struct {
+	TransferID int64 `json:"transfer_id"`
+	// RequestID is the id of the request that made the transfer, set by the distributor
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendTransferNotification(
+	ctx context.Context,
+	payload *PayloadSendTransferNotification,
+	opts ...asynq.Option,
+) error {
+	payload.RequestID = requestid.FromContext(ctx)
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendTransferNotification, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendTransferNotification(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendTransferNotification
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	transfer, err := processor.store.GetTransfer(ctx, payload.TransferID)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	fromAccount, fromUser, err := processor.getAccountOwner(ctx, transfer.FromAccountID)
+	if err != nil {
+		return err
+	}
+
+	toAccount, toUser, err := processor.getAccountOwner(ctx, transfer.ToAccountID)
+	if err != nil {
+		return err
+	}
+
+	// converted transfers credit the destination account with the converted amount
+	receivedAmount := transfer.Amount
+	if transfer.ToAmount.Valid {
+		receivedAmount = transfer.ToAmount.Int64
+	}
+
+	notifications := []notification.Notification{
+		{
+			Username: fromUser.Username,
+			Email:    fromUser.Email,
+			Event:    notification.EventTransferSent,
+			Title:    "Money sent from your account",
+			Content: fmt.Sprintf(`Hello %s,<br/>
+	%s %s has been sent from your account #%d to account #%d.<br/>
+	`, fromUser.FullName, util.FormatAmount(transfer.Amount, fromAccount.Currency), fromAccount.Currency, fromAccount.ID, toAccount.ID),
+		},
+		{
+			Username: toUser.Username,
+			Email:    toUser.Email,
+			Event:    notification.EventTransferReceived,
+			Title:    "Money received in your account",
+			Content: fmt.Sprintf(`Hello %s,<br/>
+	You have received %s %s in your account #%d from account #%d.<br/>
+	`, toUser.FullName, util.FormatAmount(receivedAmount, toAccount.Currency), toAccount.Currency, toAccount.ID, fromAccount.ID),
+		},
+	}
+
+	// each channel of each recipient is delivered by a task of its own, so that a failing channel
+	// is retried alone instead of repeating the deliveries that succeeded
+	deliveries := 0
+	for _, n := range notifications {
+		channels, err := processor.notifier.EnabledChannels(ctx, n.Username, n.Event)
+		if err != nil {
+			return err
+		}
+
+		for _, channel := range channels {
+			err = processor.distributor.DistributeTaskDeliverNotification(
+				ctx,
+				&PayloadDeliverNotification{
+					TransferID:   transfer.ID,
+					Channel:      channel,
+					Notification: n,
+				},
+				asynq.TaskID(notificationTaskID(transfer.ID, n, channel)),
+				asynq.Retention(notificationRetention),
+				asynq.MaxRetry(notificationMaxRetry),
+				asynq.Queue(QueueDefault),
+			)
+			if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+				return fmt.Errorf("failed to distribute %s notification to %s: %w", channel, n.Username, err)
+			}
+			deliveries++
+		}
+	}
+
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Int64("transfer_id", transfer.ID).Int("deliveries", deliveries).Msg("processed task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) getAccountOwner(ctx context.Context, accountID int64) (db.Account, db.User, error) {
+	account, err := processor.store.GetAccount(ctx, accountID)
+	if err != nil {
+		return account, db.User{}, fmt.Errorf("failed to get account %d: %w", accountID, err)
+	}
+
+	user, err := processor.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return account, user, fmt.Errorf("failed to get owner of account %d: %w", accountID, err)
+	}
+
+	return account, user, nil
+}
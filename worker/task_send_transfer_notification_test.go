@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	mockdb "github.com/antimatter007/go-backend/db/mock"
+	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/notification"
+	"github.com/antimatter007/go-backend/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// notificationDistributor records the notification deliveries, the other tasks are not expected
+type notificationDistributor struct {
+	TaskDistributor
+	err      error
+	payloads []*PayloadDeliverNotification
+	taskIDs  []string
+}
+
+func (distributor *notificationDistributor) DistributeTaskDeliverNotification(
+	ctx context.Context,
+	payload *PayloadDeliverNotification,
+	opts ...asynq.Option,
+) error {
+	distributor.payloads = append(distributor.payloads, payload)
+	for _, opt := range opts {
+		if opt.Type() == asynq.TaskIDOpt {
+			distributor.taskIDs = append(distributor.taskIDs, opt.Value().(string))
+		}
+	}
+	return distributor.err
+}
+
+type recordingChannel struct {
+	name string
+	err  error
+	sent []notification.Notification
+}
+
+func (channel *recordingChannel) Name() string {
+	return channel.name
+}
+
+func (channel *recordingChannel) Send(ctx context.Context, n notification.Notification) error {
+	channel.sent = append(channel.sent, n)
+	return channel.err
+}
+
+func newTestTransferNotificationTask(t *testing.T, transferID int64) *asynq.Task {
+	payload, err := json.Marshal(&PayloadSendTransferNotification{TransferID: transferID})
+	require.NoError(t, err)
+	return asynq.NewTask(TaskSendTransferNotification, payload)
+}
+
+func TestProcessTaskSendTransferNotification(t *testing.T) {
+	fromUser := db.User{Username: util.RandomOwner(), Email: util.RandomEmail(), FullName: "Alice"}
+	toUser := db.User{Username: util.RandomOwner(), Email: util.RandomEmail(), FullName: "Bob"}
+	fromAccount := db.Account{ID: 1, Owner: fromUser.Username, Currency: util.USD}
+	toAccount := db.Account{ID: 2, Owner: toUser.Username, Currency: util.JPY}
+	transfer := db.Transfer{
+		ID:            42,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        12_345,
+		ToAmount:      pgtype.Int8{Int64: 18_500, Valid: true},
+	}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(fromUser.Username)).Times(1).Return(fromUser, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(toUser.Username)).Times(1).Return(toUser, nil)
+	store.EXPECT().
+		ListNotificationPreferences(gomock.Any(), gomock.Eq(fromUser.Username)).
+		Times(1).
+		Return([]db.NotificationPreference{
+			{Username: fromUser.Username, Event: notification.EventTransferSent, Channel: notification.ChannelEmail, Enabled: false},
+		}, nil)
+	store.EXPECT().
+		ListNotificationPreferences(gomock.Any(), gomock.Eq(toUser.Username)).
+		Times(1).
+		Return([]db.NotificationPreference{}, nil)
+
+	email := &recordingChannel{name: notification.ChannelEmail}
+	inApp := &recordingChannel{name: notification.ChannelInApp}
+	// deliveries enqueued by an earlier attempt of the task are not an error
+	distributor := &notificationDistributor{err: asynq.ErrTaskIDConflict}
+	processor := &RedisTaskProcessor{
+		store:       store,
+		notifier:    notification.NewDispatcher(store, email, inApp),
+		distributor: distributor,
+	}
+
+	err := processor.ProcessTaskSendTransferNotification(context.Background(), newTestTransferNotificationTask(t, transfer.ID))
+	require.NoError(t, err)
+
+	// nothing is sent by the task itself
+	require.Empty(t, email.sent)
+	require.Empty(t, inApp.sent)
+
+	require.Len(t, distributor.payloads, 3)
+	require.Equal(t, notification.ChannelInApp, distributor.payloads[0].Channel)
+	require.Equal(t, fromUser.Username, distributor.payloads[0].Notification.Username)
+	require.Contains(t, distributor.payloads[0].Notification.Content, "123.45 USD has been sent")
+	require.Equal(t, notification.ChannelEmail, distributor.payloads[1].Channel)
+	require.Equal(t, notification.ChannelInApp, distributor.payloads[2].Channel)
+	require.Contains(t, distributor.payloads[2].Notification.Content, "received 18500 JPY")
+
+	require.Equal(t, []string{
+		"notification:42:transfer.sent:" + fromUser.Username + ":in_app",
+		"notification:42:transfer.received:" + toUser.Username + ":email",
+		"notification:42:transfer.received:" + toUser.Username + ":in_app",
+	}, distributor.taskIDs)
+}
+
+func TestProcessTaskDeliverNotification(t *testing.T) {
+	email := &recordingChannel{name: notification.ChannelEmail, err: errors.New("smtp unavailable")}
+	inApp := &recordingChannel{name: notification.ChannelInApp}
+	processor := &RedisTaskProcessor{
+		notifier: notification.NewDispatcher(nil, email, inApp),
+	}
+
+	newTask := func(channel string) *asynq.Task {
+		payload, err := json.Marshal(&PayloadDeliverNotification{
+			TransferID:   42,
+			Channel:      channel,
+			Notification: notification.Notification{Username: util.RandomOwner(), Event: notification.EventTransferSent},
+		})
+		require.NoError(t, err)
+		return asynq.NewTask(TaskDeliverNotification, payload)
+	}
+
+	// a failing channel is retried on its own
+	err := processor.ProcessTaskDeliverNotification(context.Background(), newTask(notification.ChannelEmail))
+	require.ErrorContains(t, err, "smtp unavailable")
+	require.NotErrorIs(t, err, asynq.SkipRetry)
+	require.Len(t, email.sent, 1)
+	require.Empty(t, inApp.sent)
+
+	err = processor.ProcessTaskDeliverNotification(context.Background(), newTask(notification.ChannelInApp))
+	require.NoError(t, err)
+	require.Len(t, inApp.sent, 1)
+
+	err = processor.ProcessTaskDeliverNotification(context.Background(), newTask("sms"))
+	require.ErrorIs(t, err, asynq.SkipRetry)
+}
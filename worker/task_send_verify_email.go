@@ -6,22 +6,31 @@ import (
 	"fmt"
 
 	db "github.com/antimatter007/go-backend/db/sqlc"
+	"github.com/antimatter007/go-backend/requestid"
 	"github.com/antimatter007/go-backend/util"
 	"github.com/hibiken/asynq"
-	"github.com/rs/zerolog/log"
 )
 
 const TaskSendVerifyEmail = "task:send_verify_email"
 
 type PayloadSendVerifyEmail struct {
 	Username string `json:"username"`
+	// TraceCarrier and RequestID are filled in by the distributor,
+	// so that sending the email can be followed back to the signup in traces and logs
+	TraceCarrier TraceCarrier `json:"trace_carrier,omitempty"`
+	RequestID    string       `json:"request_id,omitempty"`
 }
 
 func (distributor *RedisTaskDistributor) DistributeTaskSendVerifyEmail(
 	ctx context.Context,
 	payload *PayloadSendVerifyEmail,
 	opts ...asynq.Option,
-) error {
+) (err error) {
+	ctx, span, carrier := startEnqueueSpan(ctx, TaskSendVerifyEmail)
+	defer func() { endSpan(span, err) }()
+	payload.TraceCarrier = carrier
+	payload.RequestID = requestid.FromContext(ctx)
+
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal task payload: %w", err)
@@ -33,7 +42,7 @@ func (distributor *RedisTaskDistributor) DistributeTaskSendVerifyEmail(
 		return fmt.Errorf("failed to enqueue task: %w", err)
 	}
 
-	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
 		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
 	return nil
 }
@@ -73,7 +82,7 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 		return fmt.Errorf("failed to send verify email: %w", err)
 	}
 
-	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+	requestid.Logger(ctx).Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
 		Str("email", user.Email).Msg("processed task")
 	return nil
 }
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/antimatter007/go-backend/worker"
+
+// TraceCarrier holds the trace context of the request that enqueued a task,
+// so that the processing of the task joins the same trace.
+type TraceCarrier map[string]string
+
+// tracedPayload reads the trace carrier of any task payload that has one
+type tracedPayload struct {
+	TraceCarrier TraceCarrier `json:"trace_carrier"`
+}
+
+// startEnqueueSpan starts the span of enqueuing a task, and returns the trace carrier to put in its payload
+func startEnqueueSpan(ctx context.Context, taskType string) (context.Context, trace.Span, TraceCarrier) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "enqueue "+taskType,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.String("asynq.task_type", taskType)),
+	)
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return ctx, span, nil
+	}
+	return ctx, span, TraceCarrier(carrier)
+}
+
+// endSpan records err, if any, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// taskTracing runs every task in a span that continues the trace found in its payload.
+// Tasks without a trace carrier, such as the periodic ones, start a new trace.
+func taskTracing(mux *asynq.ServeMux) asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			var payload tracedPayload
+			if err := json.Unmarshal(task.Payload(), &payload); err == nil && payload.TraceCarrier != nil {
+				ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(payload.TraceCarrier))
+			}
+
+			name := "unknown"
+			if _, pattern := mux.Handler(task); pattern != "" {
+				name = pattern
+			}
+
+			attrs := []attribute.KeyValue{
+				attribute.String("asynq.task_type", task.Type()),
+			}
+			if taskID, ok := asynq.GetTaskID(ctx); ok {
+				attrs = append(attrs, attribute.String("asynq.task_id", taskID))
+			}
+			if queue, ok := asynq.GetQueueName(ctx); ok {
+				attrs = append(attrs, attribute.String("asynq.queue", queue))
+			}
+			if retried, ok := asynq.GetRetryCount(ctx); ok {
+				attrs = append(attrs, attribute.Int("asynq.retry_count", retried))
+			}
+
+			ctx, span := otel.Tracer(instrumentationName).Start(ctx, "process "+name,
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(attrs...),
+			)
+
+			err := next.ProcessTask(ctx, task)
+			endSpan(span, err)
+			return err
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupTestTracing(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+func TestTaskTracingContinuesTrace(t *testing.T) {
+	exporter := setupTestTracing(t)
+
+	// the signup request enqueues the task
+	requestCtx, request := otel.Tracer("test").Start(context.Background(), "POST /v1/create_user")
+	_, enqueue, carrier := startEnqueueSpan(requestCtx, TaskSendVerifyEmail)
+	endSpan(enqueue, nil)
+	request.End()
+	require.NotNil(t, carrier)
+
+	payload, err := json.Marshal(&PayloadSendVerifyEmail{
+		Username:     "alice",
+		TraceCarrier: carrier,
+	})
+	require.NoError(t, err)
+
+	// the worker processes it later, in a context of its own
+	mux := asynq.NewServeMux()
+	var processCtx context.Context
+	mux.HandleFunc(TaskSendVerifyEmail, func(ctx context.Context, task *asynq.Task) error {
+		processCtx = ctx
+		return nil
+	})
+	handler := taskTracing(mux)(mux)
+
+	err = handler.ProcessTask(context.Background(), asynq.NewTask(TaskSendVerifyEmail, payload))
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	process := spans[2]
+	require.Equal(t, "process "+TaskSendVerifyEmail, process.Name)
+	require.Equal(t, trace.SpanKindConsumer, process.SpanKind)
+	require.Equal(t, request.SpanContext().TraceID(), process.SpanContext.TraceID())
+	require.Equal(t, enqueue.SpanContext().SpanID(), process.Parent.SpanID())
+	require.True(t, process.Parent.IsRemote())
+
+	// the handler runs within the span, so its queries join the trace
+	require.Equal(t, process.SpanContext.SpanID(), trace.SpanContextFromContext(processCtx).SpanID())
+}
+
+func TestTaskTracingStartsTrace(t *testing.T) {
+	exporter := setupTestTracing(t)
+
+	mux := asynq.NewServeMux()
+	mux.HandleFunc(TaskExpireHolds, func(ctx context.Context, task *asynq.Task) error {
+		return errors.New("db is down")
+	})
+	handler := taskTracing(mux)(mux)
+
+	err := handler.ProcessTask(context.Background(), asynq.NewTask(TaskExpireHolds, []byte(`{"batch_size":10}`)))
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "process "+TaskExpireHolds, spans[0].Name)
+	require.False(t, spans[0].Parent.IsValid())
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+}